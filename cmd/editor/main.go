@@ -1,16 +1,18 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"image/color"
 	"log"
 	"math"
-	"os"
 
 	"github.com/1siamBot/rts-engine/editor"
+	"github.com/1siamBot/rts-engine/engine/core"
 	"github.com/1siamBot/rts-engine/engine/input"
 	"github.com/1siamBot/rts-engine/engine/maplib"
 	"github.com/1siamBot/rts-engine/engine/render"
+	"github.com/1siamBot/rts-engine/engine/render3d"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -19,37 +21,67 @@ import (
 const (
 	ScreenWidth  = 1280
 	ScreenHeight = 720
+	PanSpeed     = 500.0 // pixels per second
 )
 
+var preview3D = flag.Bool("3d", false, "preview the map with the 3D renderer")
+
+// entityPresets are the objects the entity tool cycles through with [T]
+var entityPresets = []struct {
+	Kind maplib.EntityKind
+	Key  string
+}{
+	{maplib.EntityUnit, "gi"}, {maplib.EntityUnit, "conscript"},
+	{maplib.EntityUnit, "grizzly"}, {maplib.EntityUnit, "rhino"},
+	{maplib.EntityUnit, "mcv"}, {maplib.EntityUnit, "harvester_a"},
+	{maplib.EntityBuilding, "construction_yard"}, {maplib.EntityBuilding, "power_plant"},
+	{maplib.EntityBuilding, "barracks"}, {maplib.EntityBuilding, "pillbox"},
+	{maplib.EntityProp, "civ_house"}, {maplib.EntityProp, "civ_church"},
+	{maplib.EntityProp, "tree"}, {maplib.EntityProp, "boulder"},
+	{maplib.EntityCrate, "random"}, {maplib.EntityCrate, "credits"},
+}
+
 type EditorApp struct {
 	editor   *editor.Editor
-	renderer *render.IsoRenderer
+	renderer render.Renderer
+	world    *core.World // empty; the editor draws map entities itself
 	input    *input.InputState
 	hoverX   int
 	hoverY   int
 
 	terrains []maplib.TerrainType
 	selIdx   int
+
+	// Drag anchor for select/line tools
+	dragging     bool
+	dragX, dragY int
+	pasting      bool
+	entityIdx    int
 }
 
 func NewEditorApp() *EditorApp {
 	e := &EditorApp{
-		editor:   editor.NewEditor(64, 64),
-		renderer: render.NewIsoRenderer(ScreenWidth, ScreenHeight),
-		input:    input.NewInputState(),
+		editor: editor.NewEditor(64, 64),
+		world:  core.NewWorld(20),
+		input:  input.NewInputState(),
 		terrains: []maplib.TerrainType{
 			maplib.TerrainGrass, maplib.TerrainDirt, maplib.TerrainSand,
 			maplib.TerrainWater, maplib.TerrainDeepWater, maplib.TerrainRock,
 			maplib.TerrainCliff, maplib.TerrainRoad, maplib.TerrainBridge,
 			maplib.TerrainOre, maplib.TerrainGem, maplib.TerrainSnow,
-			maplib.TerrainUrban, maplib.TerrainForest,
+			maplib.TerrainUrban, maplib.TerrainForest, maplib.TerrainRamp,
 		},
 	}
-	e.renderer.Camera.CenterOn(32, 32)
+	if *preview3D {
+		e.renderer = render3d.NewRenderer3D(ScreenWidth, ScreenHeight)
+	} else {
+		e.renderer = render.NewIsoRenderer(ScreenWidth, ScreenHeight)
+	}
+	e.renderer.View().CenterOn(32, 32)
 
 	// Load file from command line if provided
-	if len(os.Args) > 1 {
-		if err := e.editor.LoadMap(os.Args[1]); err != nil {
+	if flag.NArg() > 0 {
+		if err := e.editor.LoadMap(flag.Arg(0)); err != nil {
 			log.Printf("Failed to load map: %v", err)
 		}
 	}
@@ -61,28 +93,28 @@ func (a *EditorApp) Update() error {
 	a.input.Update()
 
 	// Camera controls
-	speed := a.renderer.Camera.Speed / 60.0
+	speed := PanSpeed / 60.0
 	if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) {
-		a.renderer.Camera.Pan(0, -speed)
+		a.renderer.View().Pan(0, -speed)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyDown) {
-		a.renderer.Camera.Pan(0, speed)
+		a.renderer.View().Pan(0, speed)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		a.renderer.Camera.Pan(-speed, 0)
+		a.renderer.View().Pan(-speed, 0)
 	}
 	if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight) {
-		a.renderer.Camera.Pan(speed, 0)
+		a.renderer.View().Pan(speed, 0)
 	}
 	if a.input.ScrollY != 0 {
-		a.renderer.Camera.ZoomAt(a.input.ScrollY*0.1, a.input.MouseX, a.input.MouseY)
+		a.renderer.View().ZoomAt(a.input.ScrollY*0.1, a.input.MouseX, a.input.MouseY)
 	}
 	if ebiten.IsMouseButtonPressed(ebiten.MouseButtonMiddle) {
-		a.renderer.Camera.Pan(float64(-a.input.MouseDX), float64(-a.input.MouseDY))
+		a.renderer.View().Pan(float64(-a.input.MouseDX), float64(-a.input.MouseDY))
 	}
 
 	// Hover tile
-	wx, wy := a.renderer.Camera.ScreenToWorld(a.input.MouseX, a.input.MouseY)
+	wx, wy := a.renderer.View().ScreenToWorld(a.input.MouseX, a.input.MouseY)
 	a.hoverX = int(math.Floor(wx))
 	a.hoverY = int(math.Floor(wy))
 
@@ -101,6 +133,59 @@ func (a *EditorApp) Update() error {
 	if a.input.IsKeyJustPressed(ebiten.KeyH) {
 		a.editor.Tool = editor.ToolHeight
 	}
+	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	if !ctrl && a.input.IsKeyJustPressed(ebiten.KeyC) {
+		a.editor.Tool = editor.ToolCliff
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyM) {
+		a.editor.Tool = editor.ToolSelect
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyF) {
+		a.editor.Tool = editor.ToolFill
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyL) {
+		a.editor.Tool = editor.ToolLine
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyE) {
+		a.editor.Tool = editor.ToolEntity
+	}
+
+	// Entity type ([T]) and owner ([O]: neutral, then each player slot)
+	if a.input.IsKeyJustPressed(ebiten.KeyT) {
+		a.entityIdx = (a.entityIdx + 1) % len(entityPresets)
+		a.editor.EntityKind = entityPresets[a.entityIdx].Kind
+		a.editor.EntityKey = entityPresets[a.entityIdx].Key
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyO) {
+		a.editor.EntityOwner++
+		if a.editor.EntityOwner >= a.editor.TileMap.MaxPlayers {
+			a.editor.EntityOwner = maplib.NeutralOwner
+		}
+	}
+
+	// Clipboard (Ctrl+C / Ctrl+V), Esc cancels paste or clears selection
+	if ctrl && a.input.IsKeyJustPressed(ebiten.KeyC) {
+		a.editor.Copy()
+	}
+	if ctrl && a.input.IsKeyJustPressed(ebiten.KeyV) && a.editor.Clipboard != nil {
+		a.pasting = true
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyEscape) {
+		if a.pasting {
+			a.pasting = false
+		} else {
+			a.editor.ClearSelection()
+		}
+	}
+
+	// Cliff level ([ / ])
+	if a.input.IsKeyJustPressed(ebiten.KeyBracketLeft) && a.editor.CliffLevel > 0 {
+		a.editor.CliffLevel--
+	}
+	if a.input.IsKeyJustPressed(ebiten.KeyBracketRight) && a.editor.CliffLevel < maplib.MaxHeightLevel {
+		a.editor.CliffLevel++
+	}
 
 	// Brush size
 	if a.input.IsKeyJustPressed(ebiten.KeyTab) {
@@ -115,14 +200,55 @@ func (a *EditorApp) Update() error {
 		a.editor.ShowGrid = !a.editor.ShowGrid
 	}
 
-	// Paint with left click
-	if a.input.LeftPressed && a.input.MouseX < ScreenWidth-200 {
-		a.editor.Paint(a.hoverX, a.hoverY)
+	// Apply the active tool with the left mouse button
+	overMap := a.input.MouseX < ScreenWidth-200
+	switch {
+	case a.pasting:
+		if a.input.LeftJustPressed && overMap {
+			a.editor.Paste(a.hoverX, a.hoverY)
+			a.pasting = false
+		}
+		if a.input.RightJustPressed {
+			a.pasting = false
+		}
+	case a.editor.Tool == editor.ToolSelect:
+		if a.input.LeftJustPressed && overMap {
+			a.dragging = true
+			a.dragX, a.dragY = a.hoverX, a.hoverY
+		}
+		if a.dragging && a.input.LeftPressed {
+			a.editor.SetSelection(a.dragX, a.dragY, a.hoverX, a.hoverY)
+		}
+		if a.input.LeftJustReleased {
+			a.dragging = false
+		}
+	case a.editor.Tool == editor.ToolLine:
+		if a.input.LeftJustPressed && overMap {
+			a.dragging = true
+			a.dragX, a.dragY = a.hoverX, a.hoverY
+		}
+		if a.dragging && a.input.LeftJustReleased {
+			a.editor.Line(a.dragX, a.dragY, a.hoverX, a.hoverY)
+			a.dragging = false
+		}
+	case a.editor.Tool == editor.ToolFill:
+		if a.input.LeftJustPressed && overMap {
+			a.editor.Fill(a.hoverX, a.hoverY)
+		}
+	case a.editor.Tool == editor.ToolEntity:
+		if a.input.LeftJustPressed && overMap {
+			a.editor.PlaceEntity(a.hoverX, a.hoverY)
+		}
+		if a.input.RightJustPressed && overMap {
+			a.editor.RemoveEntity(a.hoverX, a.hoverY)
+		}
+	default:
+		if a.input.LeftPressed && overMap {
+			a.editor.Paint(a.hoverX, a.hoverY)
+		}
 	}
 
 	// Undo/Redo (Ctrl+Z / Ctrl+Shift+Z)
-	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
-	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
 	if ctrl && a.input.IsKeyJustPressed(ebiten.KeyZ) {
 		if shift {
 			a.editor.Redo()
@@ -150,14 +276,46 @@ func (a *EditorApp) Update() error {
 func (a *EditorApp) Draw(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{30, 30, 40, 255})
 
-	a.renderer.DrawMap(screen, a.editor.TileMap)
+	a.renderer.DrawScene(screen, a.editor.TileMap, a.world, 0)
 	if a.editor.ShowGrid {
 		a.renderer.DrawGrid(screen, a.editor.TileMap)
 	}
 
+	// Tool previews
+	var preview []editor.PreviewTile
+	switch {
+	case a.pasting:
+		preview = a.editor.PastePreview(a.hoverX, a.hoverY)
+	case a.editor.Tool == editor.ToolLine && a.dragging:
+		preview = a.editor.LinePreview(a.dragX, a.dragY, a.hoverX, a.hoverY)
+	case a.editor.Tool == editor.ToolFill:
+		preview = a.editor.FillPreview(a.hoverX, a.hoverY)
+	}
+	for _, pt := range preview {
+		clr := render.TerrainColors[pt.Tile.Terrain]
+		clr.A = 200
+		a.drawTileOutline(screen, pt.X, pt.Y, clr)
+	}
+
+	// Selection rectangle
+	if a.editor.HasSelection {
+		r := a.editor.Selection
+		corners := [4][2]float64{
+			{float64(r.X1), float64(r.Y1)}, {float64(r.X2 + 1), float64(r.Y1)},
+			{float64(r.X2 + 1), float64(r.Y2 + 1)}, {float64(r.X1), float64(r.Y2 + 1)},
+		}
+		selColor := color.RGBA{0, 255, 255, 220}
+		for i := range corners {
+			j := (i + 1) % 4
+			x0, y0 := a.renderer.View().WorldToScreen(corners[i][0], corners[i][1])
+			x1, y1 := a.renderer.View().WorldToScreen(corners[j][0], corners[j][1])
+			vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), 2, selColor, false)
+		}
+	}
+
 	// Hover highlight
 	if a.editor.TileMap.InBounds(a.hoverX, a.hoverY) {
-		sx, sy := a.renderer.Camera.WorldToScreen(float64(a.hoverX), float64(a.hoverY))
+		sx, sy := a.renderer.View().WorldToScreen(float64(a.hoverX), float64(a.hoverY))
 		tw := float32(a.editor.TileMap.TileWidth)
 		th := float32(a.editor.TileMap.TileHeight)
 		hw := tw / 2
@@ -173,12 +331,19 @@ func (a *EditorApp) Draw(screen *ebiten.Image) {
 
 	// Start positions
 	for _, sp := range a.editor.TileMap.StartPositions {
-		sx, sy := a.renderer.Camera.WorldToScreen(float64(sp.X)+0.5, float64(sp.Y)+0.5)
+		sx, sy := a.renderer.View().WorldToScreen(float64(sp.X)+0.5, float64(sp.Y)+0.5)
 		clr := color.RGBA{255, 255, 0, 255}
 		vector.DrawFilledCircle(screen, float32(sx), float32(sy), 8, clr, false)
 		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("P%d", sp.PlayerSlot), sx-5, sy-5)
 	}
 
+	// Entity spawn markers
+	for _, ent := range a.editor.TileMap.Entities {
+		sx, sy := a.renderer.View().WorldToScreen(float64(ent.X)+0.5, float64(ent.Y)+0.5)
+		vector.DrawFilledCircle(screen, float32(sx), float32(sy), 6, ownerColor(ent.Owner), false)
+		ebitenutil.DebugPrintAt(screen, ent.Key, sx+8, sy-8)
+	}
+
 	// Sidebar
 	a.drawSidebar(screen)
 
@@ -195,6 +360,17 @@ func (a *EditorApp) Draw(screen *ebiten.Image) {
 	ebitenutil.DebugPrintAt(screen, info, 5, ScreenHeight-20)
 }
 
+// drawTileOutline outlines a single tile diamond
+func (a *EditorApp) drawTileOutline(screen *ebiten.Image, x, y int, clr color.RGBA) {
+	corners := [4][2]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	for i := range corners {
+		j := (i + 1) % 4
+		x0, y0 := a.renderer.View().WorldToScreen(float64(x)+corners[i][0], float64(y)+corners[i][1])
+		x1, y1 := a.renderer.View().WorldToScreen(float64(x)+corners[j][0], float64(y)+corners[j][1])
+		vector.StrokeLine(screen, float32(x0), float32(y0), float32(x1), float32(y1), 2, clr, false)
+	}
+}
+
 func (a *EditorApp) drawSidebar(screen *ebiten.Image) {
 	sx := float32(ScreenWidth - 200)
 	vector.DrawFilledRect(screen, sx, 0, 200, float32(ScreenHeight), color.RGBA{20, 20, 40, 220}, false)
@@ -205,7 +381,7 @@ func (a *EditorApp) drawSidebar(screen *ebiten.Image) {
 	terrainNames := []string{
 		"Grass", "Dirt", "Sand", "Water", "DeepWater",
 		"Rock", "Cliff", "Road", "Bridge", "Ore",
-		"Gem", "Snow", "Urban", "Forest",
+		"Gem", "Snow", "Urban", "Forest", "Ramp",
 	}
 	for i, name := range terrainNames {
 		clr := color.RGBA{50, 50, 80, 255}
@@ -222,7 +398,12 @@ func (a *EditorApp) drawSidebar(screen *ebiten.Image) {
 	}
 
 	y += 10
-	tools := []string{"[P] Paint", "[H] Height"}
+	tools := []string{
+		"[P] Paint", "[H] Height", fmt.Sprintf("[C] Cliff (level %d, [ ] adjust)", a.editor.CliffLevel),
+		"[M] Select  [F] Fill  [L] Line", "[Ctrl+C] Copy  [Ctrl+V] Paste",
+		"[E] Entity  [T] Type  [O] Owner",
+		fmt.Sprintf("  %s (%s)", a.editor.EntityKey, ownerName(a.editor.EntityOwner)),
+	}
 	for _, t := range tools {
 		ebitenutil.DebugPrintAt(screen, t, int(sx)+10, y)
 		y += 18
@@ -233,11 +414,31 @@ func (a *EditorApp) drawSidebar(screen *ebiten.Image) {
 	}
 }
 
+func ownerName(owner int) string {
+	if owner == maplib.NeutralOwner {
+		return "neutral"
+	}
+	return fmt.Sprintf("P%d", owner)
+}
+
+func ownerColor(owner int) color.RGBA {
+	switch owner {
+	case maplib.NeutralOwner:
+		return color.RGBA{200, 200, 200, 255}
+	case 0:
+		return color.RGBA{60, 120, 255, 255}
+	case 1:
+		return color.RGBA{255, 60, 60, 255}
+	}
+	return color.RGBA{255, 200, 0, 255}
+}
+
 func (a *EditorApp) Layout(_, _ int) (int, int) {
 	return ScreenWidth, ScreenHeight
 }
 
 func main() {
+	flag.Parse()
 	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
 	ebiten.SetWindowTitle("🗺️ RTS Map Editor")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
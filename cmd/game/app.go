@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/1siamBot/rts-engine/engine/input"
+	"github.com/1siamBot/rts-engine/engine/ui"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// App implements ebiten.Game. It owns the menus and switches between the
+// menu scenes and a running match; each skirmish gets a fresh Game, which
+// is dropped again when the player quits to the main menu.
+type App struct {
+	menu     *ui.MenuSystem
+	bindings input.KeyBindings
+	keys     *input.InputState // app-wide hotkeys, live in menus too
+	match    *Game             // nil outside a match
+}
+
+func NewApp() *App {
+	a := &App{bindings: loadKeyBindings(), keys: input.NewInputState()}
+	a.keys.Bindings = a.bindings
+	a.menu = ui.NewMenuSystem(windowWidth, windowHeight, ui.NewUISprites())
+	a.menu.Bindings = a.bindings
+	a.menu.Settings = loadSettings()
+
+	a.menu.OnStartGame = func(ui.SkirmishSettings) {
+		a.startMatch()
+	}
+	a.menu.OnRestartGame = a.startMatch
+	a.menu.OnResumeGame = func() {
+		if a.match != nil {
+			a.match.gameLoop.Play()
+		}
+	}
+	a.menu.OnQuitToMenu = func() {
+		a.match = nil
+	}
+	a.menu.OnExitGame = func() {
+		os.Exit(0)
+	}
+	a.menu.OnOpenEditor = launchEditor
+	a.menu.MatchStats = func() []ui.PlayerResult {
+		if a.match == nil {
+			return nil
+		}
+		return a.match.matchStats()
+	}
+	a.menu.OnGameOver = func(bool) {
+		if a.match != nil {
+			a.match.saveStats()
+		}
+	}
+	a.menu.OnApplySettings = func(s ui.GameSettings) {
+		ebiten.SetVsyncEnabled(s.VSync)
+		ebiten.SetFullscreen(s.Fullscreen)
+		if a.match != nil {
+			a.match.applySettings(s)
+		}
+		if err := s.Save(settingsPath); err != nil {
+			log.Printf("Settings not saved: %v", err)
+		}
+	}
+	ebiten.SetVsyncEnabled(a.menu.Settings.VSync)
+	ebiten.SetFullscreen(a.menu.Settings.Fullscreen)
+
+	// Screenshot mode needs gameplay, so it skips the main menu
+	if screenshotTarget != "" {
+		a.startMatch()
+	}
+	return a
+}
+
+// startMatch replaces any running match with a new one from the skirmish settings
+func (a *App) startMatch() {
+	a.match = NewGame(a.menu, a.bindings)
+	a.menu.State = ui.StatePlaying
+}
+
+func (a *App) Update() error {
+	if a.keys.Action(input.ActionFullscreen) {
+		ebiten.SetFullscreen(!ebiten.IsFullscreen())
+		a.menu.Settings.Fullscreen = ebiten.IsFullscreen()
+	}
+	if a.match == nil {
+		a.menu.Update(1.0 / 60.0)
+		return nil
+	}
+	return a.match.Update()
+}
+
+func (a *App) Draw(screen *ebiten.Image) {
+	if a.match == nil {
+		a.menu.Draw(screen)
+	} else {
+		a.match.Draw(screen)
+	}
+
+	// Screenshot capture
+	frameCount++
+	if screenshotTarget != "" && frameCount >= screenshotFrame {
+		saveScreenshot(screen)
+	}
+}
+
+// Layout renders at the window's real size in device pixels, so the view
+// stays sharp on high-DPI displays; the HUD and menus anchor to the edges
+func (a *App) Layout(outsideW, outsideH int) (int, int) {
+	scale := ebiten.Monitor().DeviceScaleFactor()
+	w, h := int(float64(outsideW)*scale), int(float64(outsideH)*scale)
+	if w <= 0 || h <= 0 {
+		return a.menu.ScreenW, a.menu.ScreenH
+	}
+	if w != a.menu.ScreenW || h != a.menu.ScreenH {
+		a.menu.Resize(w, h)
+		if a.match != nil {
+			a.match.Resize(w, h)
+		}
+	}
+	return w, h
+}
+
+// loadKeyBindings reads the key binding file, falling back to the defaults
+func loadKeyBindings() input.KeyBindings {
+	kb, err := input.LoadKeyBindings(keyBindingsPath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Key bindings not loaded (%v), using defaults", err)
+		}
+		return input.DefaultKeyBindings()
+	}
+	return kb
+}
+
+// loadSettings reads the options file, falling back to the defaults
+func loadSettings() ui.GameSettings {
+	s, err := ui.LoadSettings(settingsPath)
+	if err != nil && !os.IsNotExist(err) {
+		log.Printf("Settings not loaded (%v), using defaults", err)
+	}
+	return s
+}
+
+// launchEditor starts the map editor alongside the game
+func launchEditor() {
+	cmd, err := editorCommand()
+	if err != nil {
+		log.Printf("Map editor not started: %v", err)
+		return
+	}
+	if err := cmd.Start(); err != nil {
+		log.Printf("Map editor not started: %v", err)
+		return
+	}
+	go cmd.Wait()
+}
+
+// editorCommand finds the map editor: a binary next to the game, else a
+// source build with "go run" when the game runs from a checkout
+func editorCommand() (*exec.Cmd, error) {
+	if exe, err := os.Executable(); err == nil {
+		for _, path := range editorCandidates(exe) {
+			if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+				return exec.Command(path), nil
+			}
+		}
+	}
+	if fi, err := os.Stat(filepath.Join("cmd", "editor")); err == nil && fi.IsDir() {
+		if goTool, err := exec.LookPath("go"); err == nil {
+			return exec.Command(goTool, "run", "./cmd/editor"), nil
+		}
+	}
+	return nil, errors.New("no editor binary next to the game and no source checkout to run")
+}
+
+// editorCandidates lists where the editor may sit next to the game binary
+// exe: release builds ship rts-game-<os>-<arch> with rts-editor-<os>-<arch>,
+// local builds a plain editor binary.
+func editorCandidates(exe string) []string {
+	dir, base := filepath.Split(exe)
+	ext := filepath.Ext(base)
+	if runtime.GOOS != "windows" {
+		ext = ""
+	}
+	var paths []string
+	if stem := strings.TrimSuffix(base, ext); strings.Contains(stem, "game") {
+		paths = append(paths, filepath.Join(dir, strings.Replace(stem, "game", "editor", 1)+ext))
+	}
+	for _, name := range []string{"rts-editor", "editor"} {
+		if p := filepath.Join(dir, name+ext); !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestEditorCandidates(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("expectations use unix binary names")
+	}
+	dir := filepath.Join("opt", "rts")
+	tests := []struct {
+		exe  string
+		want []string
+	}{
+		{"rts-game-linux-amd64", []string{"rts-editor-linux-amd64", "rts-editor", "editor"}},
+		{"game", []string{"editor", "rts-editor"}},
+		{"rts", []string{"rts-editor", "editor"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.exe, func(t *testing.T) {
+			var want []string
+			for _, name := range tc.want {
+				want = append(want, filepath.Join(dir, name))
+			}
+			if got := editorCandidates(filepath.Join(dir, tc.exe)); !reflect.DeepEqual(got, want) {
+				t.Errorf("editorCandidates(%q) = %v, want %v", tc.exe, got, want)
+			}
+		})
+	}
+}
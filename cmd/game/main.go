@@ -9,46 +9,51 @@ import (
 	"math"
 	"os"
 
-	"github.com/1siamBot/rts-engine/engine/ai"
 	"github.com/1siamBot/rts-engine/engine/audio"
 	"github.com/1siamBot/rts-engine/engine/core"
 	"github.com/1siamBot/rts-engine/engine/input"
 	"github.com/1siamBot/rts-engine/engine/maplib"
 	"github.com/1siamBot/rts-engine/engine/pathfind"
+	"github.com/1siamBot/rts-engine/engine/render"
 	"github.com/1siamBot/rts-engine/engine/render3d"
 	"github.com/1siamBot/rts-engine/engine/systems"
 	"github.com/1siamBot/rts-engine/engine/ui"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
 const (
-	ScreenWidth  = 1280
+	ScreenWidth  = 1280 // default window size; the game follows the real one
 	ScreenHeight = 720
 	TickRate     = 20.0
 	MapSize      = 64
+
+	AttackWarnTicks = 10 * TickRate // minimum gap between under-attack warnings
 )
 
 var (
 	screenshotTarget string
 	screenshotFrame  int
 	frameCount       int
+	mapSeed          int64 = -1 // >= 0 selects a procedurally generated map
+	mapDayNight      bool       // turn on the day/night cycle
+	mapWeather       string     // "rain" or "fog"
+	use3D                  = true // draw with the 3D renderer rather than the 2D isometric one
+	keyBindingsPath        = "keybindings.json"
+	settingsPath           = "settings.json"
+	statsPath              = "" // optional: last match's stats are written here
+	windowWidth            = ScreenWidth
+	windowHeight           = ScreenHeight
 )
 
-// Game implements ebiten.Game
+// Game is a running skirmish match; App switches to it from the menus
 type Game struct {
-	renderer *render3d.Renderer3D
-	tileMap  *maplib.TileMap
-	gameLoop *core.GameLoop
+	*Sim
+	renderer render.Renderer
+	r3d      *render3d.Renderer3D // the 3D renderer's effects and lighting; nil when drawing in 2D
 	input    *input.InputState
-	players  *core.PlayerManager
-	eventBus *core.EventBus
-	navGrid  *pathfind.NavGrid
-	techTree *systems.TechTree
 	hud      *ui.HUD
 	audioMgr *audio.AudioManager
-	fogSys   *systems.FogSystem
 	menu     *ui.MenuSystem
 
 	// State
@@ -57,41 +62,48 @@ type Game struct {
 	hoverTileX  int
 	hoverTileY  int
 
+	// Wall drag placement
+	wallDragging           bool
+	wallStartX, wallStartY int
+
 	// Settings
 	scrollSpeed float64
+	edgeSpeed   float64
+	edgeScroll  bool
+	healthBars  ui.HealthBarMode
+
+	perf ui.PerfOverlay
+
+	attackWarned uint64      // tick of the last under-attack warning
+	relocate     *relocation // base move in progress, or nil
 
 	// Cached images
 	fogWhiteImg   *ebiten.Image
 	selectionFill *ebiten.Image
 }
 
-func NewGame() *Game {
+// NewGame sets up a skirmish match from the menu's skirmish settings
+func NewGame(menu *ui.MenuSystem, kb input.KeyBindings) *Game {
 	g := &Game{
-		renderer:    render3d.NewRenderer3D(ScreenWidth, ScreenHeight),
-		tileMap:     generateDemoMap(),
-		gameLoop:    core.NewGameLoop(TickRate),
+		Sim:         newSim(menu.Skirmish, false),
 		input:       input.NewInputState(),
-		players:     core.NewPlayerManager(),
-		eventBus:    core.NewEventBus(),
-		techTree:    systems.NewTechTree(),
 		audioMgr:    audio.NewAudioManager(),
+		menu:        menu,
 		showMinimap: true,
 		scrollSpeed: 500,
+		edgeSpeed:   500,
+	}
+	g.input.Bindings = kb
+	if use3D {
+		g.r3d = render3d.NewRenderer3D(menu.ScreenW, menu.ScreenH)
+		g.renderer = g.r3d
+	} else {
+		g.renderer = render.NewIsoRenderer(menu.ScreenW, menu.ScreenH)
 	}
 
-	// Players
-	g.players.AddPlayer(&core.Player{
-		ID: 0, Name: "Player 1", TeamID: 0, Faction: "Allied",
-		Color: 0x0066FFFF, Credits: 10000,
-	})
-	g.players.AddPlayer(&core.Player{
-		ID: 1, Name: "AI Enemy", TeamID: 1, Faction: "Soviet",
-		Color: 0xFF0000FF, Credits: 10000, IsAI: true,
-	})
-
-	g.navGrid = pathfind.NewNavGrid(g.tileMap)
-
-	g.hud = ui.NewHUD(ScreenWidth, ScreenHeight, g.techTree, g.players, 0)
+	g.hud = ui.NewHUD(menu.ScreenW, menu.ScreenH, g.techTree, g.players, 0)
+	g.hud.Fog = g.fogSys.Fogs[0]
+	g.hud.MapWidth, g.hud.MapHeight = g.tileMap.Width, g.tileMap.Height
 
 	// Wire up 3D sprite rendering callbacks (return false to use HUD default fallback)
 	g.hud.UnitDrawFn = func(screen *ebiten.Image, w *core.World, id core.EntityID, sx, sy int, playerID int) bool {
@@ -101,127 +113,124 @@ func NewGame() *Game {
 		return false // Buildings are drawn by 3D renderer now
 	}
 
-	g.fogSys = systems.NewFogSystem(g.tileMap.Width, g.tileMap.Height, g.players)
-
-	// Register systems
-	w := g.gameLoop.World
-	w.AddSystem(&systems.PowerSystem{Players: g.players})
-	w.AddSystem(&systems.BuildingConstructionSystem{Players: g.players, EventBus: g.eventBus})
-	w.AddSystem(g.fogSys)
-	w.AddSystem(&systems.MovementSystem{NavGrid: g.navGrid})
-	w.AddSystem(&systems.CombatSystem{EventBus: g.eventBus, Players: g.players})
-	w.AddSystem(&systems.ProjectileSystem{EventBus: g.eventBus})
-	w.AddSystem(&systems.HarvesterSystem{NavGrid: g.navGrid, TileMap: g.tileMap, Players: g.players, EventBus: g.eventBus})
-	w.AddSystem(&systems.ProductionSystem{TechTree: g.techTree, Players: g.players, EventBus: g.eventBus})
-	w.AddSystem(&systems.AnimationSystem{})
-	w.AddSystem(&systems.GameOverSystem{Players: g.players})
-	w.AddSystem(&ai.AISystem{
-		Controllers: []*ai.AIController{
-			ai.NewAIController(1, ai.DiffMedium, g.techTree, g.navGrid, g.tileMap),
-		},
-		Players: g.players,
+	core.Subscribe(g.eventBus, core.EvtMissionMessage, func(msg string) {
+		g.hud.ShowMessage(msg, 5.0)
 	})
-
-	g.renderer.Camera.SetMapSize(MapSize, MapSize)
-	g.renderer.Camera.CenterOn(12, 12)
-
-	g.spawnInitialEntities()
-
-	// Mark initial building tiles as occupied
-	g.markInitialBuildingTiles()
-
-	// Menu system
-	g.menu = ui.NewMenuSystem(ScreenWidth, ScreenHeight, g.hud.Sprites)
-	g.menu.OnStartGame = func(s ui.SkirmishSettings) {
-		// Apply skirmish settings
-		player := g.players.GetPlayer(0)
-		if player != nil {
-			player.Credits = []int{5000, 10000, 20000}[s.StartingCredits]
-			player.Faction = []string{"Allied", "Soviet"}[s.Faction]
+	core.Subscribe(g.eventBus, core.EvtUnitDamaged, func(d core.DamageEvent) {
+		// Hits inside the fog would give away unseen fights
+		if fog := g.fogSys.Fogs[0]; fog != nil && !fog.IsVisible(int(d.X), int(d.Y)) {
+			return
 		}
-		g.gameLoop.Play()
-	}
-	g.menu.OnResumeGame = func() {
-		g.gameLoop.Play()
-	}
-	g.menu.OnRestartGame = func() {
-		// Simple restart: reset credits and unpause
-		player := g.players.GetPlayer(0)
-		if player != nil {
-			player.Credits = 10000
-			player.Defeated = false
+		g.hud.AddDamage(d)
+	})
+	core.Subscribe(g.eventBus, core.EvtUnderAttack, func(ua core.UnderAttack) {
+		now := g.gameLoop.World.TickCount
+		if ua.PlayerID != 0 || (g.attackWarned != 0 && now-g.attackWarned < AttackWarnTicks) {
+			return
+		}
+		g.attackWarned = now
+		if ua.Building {
+			g.audioMgr.PlayAnnouncer(audio.CueBaseUnderAttack)
+			g.hud.ShowMessage("Our base is under attack", 3.0)
+		} else {
+			g.hud.ShowMessage("Unit under attack", 3.0)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtBuildingComplete, func(ev core.UnitEvent) {
+		if ev.PlayerID != 0 {
+			return
+		}
+		name := ev.Key
+		if bdef, ok := g.techTree.Buildings[ev.Key]; ok {
+			name = bdef.Name
+		}
+		g.hud.ShowMessage(name+" complete", 2.0)
+		g.audioMgr.PlayAnnouncer(audio.CueConstructionComplete)
+	})
+	core.Subscribe(g.eventBus, core.EvtUnitCreated, func(ev core.UnitEvent) {
+		if ev.PlayerID == 0 {
+			g.audioMgr.PlayAnnouncer(audio.CueUnitReady)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtLowPower, func(playerID int) {
+		if playerID == 0 {
+			g.audioMgr.PlayAnnouncer(audio.CueLowPower)
+			g.hud.ShowMessage("Low power", 2.0)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtNoFunds, func(playerID int) {
+		if playerID == 0 {
+			g.audioMgr.PlayAnnouncer(audio.CueInsufficientFunds)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtResourceHarvested, g.hud.RecordHarvest)
+	core.Subscribe(g.eventBus, core.EvtOreWasted, func(ow core.OreWasted) {
+		if ow.PlayerID == 0 {
+			g.hud.ShowMessage(fmt.Sprintf("Silos needed: $%d of ore lost", ow.Amount), 3.0)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtChronoWarp, func(cw core.ChronoWarp) {
+		if g.r3d == nil {
+			return // the 2D renderer has no particle effects
+		}
+		fog := g.fogSys.Fogs[0]
+		if fog == nil || fog.IsVisible(int(cw.FromX), int(cw.FromY)) {
+			g.r3d.Particles.AddWarp(cw.FromX, cw.FromY)
+		}
+		if fog == nil || fog.IsVisible(int(cw.ToX), int(cw.ToY)) {
+			g.r3d.Particles.AddWarp(cw.ToX, cw.ToY)
+		}
+	})
+	core.Subscribe(g.eventBus, core.EvtCrateCollected, func(pick core.CratePickup) {
+		if pick.PlayerID != 0 {
+			return
+		}
+		switch pick.Bonus {
+		case core.CrateCredits:
+			g.hud.ShowMessage(fmt.Sprintf("Crate: +%d credits", systems.CrateCreditAmount), 3.0)
+		case core.CrateReveal:
+			g.hud.ShowMessage("Crate: map revealed", 3.0)
+		case core.CrateVeterancy:
+			g.hud.ShowMessage("Crate: unit promoted", 3.0)
+		case core.CrateUnit:
+			g.hud.ShowMessage("Crate: reinforcements", 3.0)
+		}
+	})
+	if g.r3d != nil {
+		g.r3d.Camera.SetMapSize(MapSize, MapSize)
+		if fog := g.fogSys.Fogs[0]; fog != nil {
+			g.r3d.Explored = func(x, y int) bool { return fog.At(x, y) != systems.FogShroud }
 		}
-		g.gameLoop.Play()
-	}
-	g.menu.OnQuitToMenu = func() {
-		g.gameLoop.Pause()
-	}
-	g.menu.OnExitGame = func() {
-		os.Exit(0)
-	}
-	g.menu.OnApplySettings = func(s ui.GameSettings) {
-		g.scrollSpeed = s.ScrollSpeed * 100
-		g.showMinimap = s.ShowMinimap
-		ebiten.SetVsyncEnabled(s.VSync)
-		ebiten.SetFullscreen(s.Fullscreen)
-	}
-
-	// Start in main menu (unless screenshot mode which needs gameplay)
-	if screenshotTarget != "" {
-		// Screenshot mode: skip menu, go directly to gameplay
-		g.menu.State = ui.StatePlaying
-		g.gameLoop.Play()
-	} else {
-		g.menu.State = ui.StateMainMenu
-		// Pause game loop until game starts
-		g.gameLoop.Pause()
 	}
+	sx, sy := g.startPos(0, 10, 10)
+	g.renderer.View().CenterOn(float64(sx)+2, float64(sy)+2)
 
+	g.applySettings(menu.Settings)
 	return g
 }
 
-func (g *Game) spawnInitialEntities() {
-	w := g.gameLoop.World
-
-	// ---- Player 0: MCV only (authentic RA2 start) ----
-	mcvID := w.Spawn()
-	w.Attach(mcvID, &core.Position{X: 10, Y: 10})
-	w.Attach(mcvID, &core.Health{Current: 1000, Max: 1000})
-	w.Attach(mcvID, &core.Movable{Speed: 0.8, MoveType: core.MoveVehicle})
-	w.Attach(mcvID, &core.Sprite{Width: 32, Height: 32, Visible: true, ScaleX: 1, ScaleY: 1})
-	w.Attach(mcvID, &core.Selectable{Radius: 0.8})
-	w.Attach(mcvID, &core.Owner{PlayerID: 0, Faction: "Allied"})
-	w.Attach(mcvID, &core.FogVision{Range: 6})
-	w.Attach(mcvID, &core.MCV{CanDeploy: true})
-	w.Attach(mcvID, &core.Armor{ArmorType: core.ArmorHeavy})
-
-	// ---- AI Player 1: MCV that auto-deploys immediately ----
-	aiMcvID := w.Spawn()
-	w.Attach(aiMcvID, &core.Position{X: 54, Y: 54})
-	w.Attach(aiMcvID, &core.Health{Current: 1000, Max: 1000})
-	w.Attach(aiMcvID, &core.Movable{Speed: 0.8, MoveType: core.MoveVehicle})
-	w.Attach(aiMcvID, &core.Sprite{Width: 32, Height: 32, Visible: true, ScaleX: 1, ScaleY: 1})
-	w.Attach(aiMcvID, &core.Selectable{Radius: 0.8})
-	w.Attach(aiMcvID, &core.Owner{PlayerID: 1, Faction: "Soviet"})
-	w.Attach(aiMcvID, &core.FogVision{Range: 6})
-	w.Attach(aiMcvID, &core.MCV{CanDeploy: true})
-	w.Attach(aiMcvID, &core.Armor{ArmorType: core.ArmorHeavy})
-
-	// Auto-deploy AI MCV into Construction Yard immediately
-	systems.DeployMCV(w, aiMcvID, g.eventBus)
-}
-
-func (g *Game) markInitialBuildingTiles() {
-	w := g.gameLoop.World
-	for _, id := range w.Query(core.CompBuilding, core.CompPosition) {
-		pos := w.Get(id, core.CompPosition).(*core.Position)
-		bldg := w.Get(id, core.CompBuilding).(*core.Building)
-		systems.OccupyTiles(g.tileMap, int(pos.X), int(pos.Y), bldg.SizeX, bldg.SizeY)
-	}
+// applySettings pushes the options screen settings into the match
+func (g *Game) applySettings(s ui.GameSettings) {
+	g.scrollSpeed = s.ScrollSpeed * 100
+	g.edgeSpeed = s.EdgeSpeed * 100
+	g.edgeScroll = s.EdgeScroll
+	switch cam := g.renderer.View().(type) {
+	case *render3d.Camera3D:
+		cam.EdgeSize = s.EdgeSize
+	case *render.Camera:
+		cam.EdgeSize = s.EdgeSize
+	}
+	g.showMinimap = s.ShowMinimap
+	g.healthBars = s.HealthBars
+	g.audioMgr.MusicVolume = s.MusicVolume
+	g.audioMgr.SFXVolume = s.SFXVolume
 }
 
 func (g *Game) Update() error {
 	g.input.Update()
+	if g.input.Action(input.ActionPerfOverlay) {
+		g.perf.Toggle()
+	}
 	g.menu.Update(1.0 / 60.0)
 
 	// Non-playing states: only update menu
@@ -230,12 +239,16 @@ func (g *Game) Update() error {
 	}
 
 	g.hud.Update(1.0 / 60.0)
-	g.renderer.Update(1.0 / 60.0)
-	g.renderer.Camera.SmoothUpdate(1.0 / 60.0)
+	if g.r3d != nil {
+		g.r3d.Update(1.0 / 60.0)
+		g.r3d.Camera.SmoothUpdate(1.0 / 60.0)
+	}
 
-	if g.input.IsKeyJustPressed(ebiten.KeyEscape) {
+	if g.input.Action(input.ActionMenu) {
 		if g.hud.Placement.Active {
 			g.cancelPlacementWithRefund()
+		} else if g.relocate != nil && g.relocate.picking {
+			g.relocate = nil
 		} else {
 			g.menu.State = ui.StatePaused
 			g.gameLoop.Pause()
@@ -246,44 +259,81 @@ func (g *Game) Update() error {
 	g.handleCamera()
 
 	// Toggles
-	if g.input.IsKeyJustPressed(ebiten.KeyG) {
+	if g.input.Action(input.ActionToggleGrid) {
 		g.showGrid = !g.showGrid
 	}
-	if g.input.IsKeyJustPressed(ebiten.KeyM) {
+	if g.input.Action(input.ActionToggleMinimap) {
 		g.showMinimap = !g.showMinimap
 	}
 
 	// Hover tile
-	wx, wy := g.renderer.Camera.ScreenToWorld(g.input.MouseX, g.input.MouseY)
+	wx, wy := g.renderer.View().ScreenToWorld(g.input.MouseX, g.input.MouseY)
 	g.hoverTileX = int(math.Floor(wx))
 	g.hoverTileY = int(math.Floor(wy))
 
-	// Update placement ghost position
+	// Update placement ghost position, centred on the cursor and snapped to whole tiles
 	if g.hud.Placement.Active {
-		g.hud.Placement.TileX = g.hoverTileX
-		g.hud.Placement.TileY = g.hoverTileY
-		g.hud.Placement.Valid = g.canPlaceBuilding(g.hoverTileX, g.hoverTileY, g.hud.Placement.SizeX, g.hud.Placement.SizeY)
+		g.hud.Placement.TileX, g.hud.Placement.TileY = placementOrigin(wx, wy, g.hud.Placement.SizeX, g.hud.Placement.SizeY)
+		g.hud.Placement.Valid = g.canPlaceBuilding(g.hud.Placement.TileX, g.hud.Placement.TileY, g.hud.Placement.SizeX, g.hud.Placement.SizeY)
+		if g.hud.Placement.BuildingKey == "wall" {
+			g.updateWallDrag()
+		}
 	}
+	g.updateRelocate(wx, wy)
 
 	// Control groups
-	ctrl := ebiten.IsKeyPressed(ebiten.KeyControl)
+	ctrl := g.input.ActionHeld(input.ActionGroupModifier)
+	build := g.input.ActionHeld(input.ActionBuildModifier)
 	for i := 0; i <= 9; i++ {
-		key := ebiten.Key0 + ebiten.Key(i)
-		if g.input.IsKeyJustPressed(key) {
-			if ctrl {
+		if g.input.Action(input.ActionControlGroup(i)) {
+			if build {
+				// 1 is the first slot, 0 the tenth
+				g.buildSlot((i + 9) % 10)
+			} else if ctrl {
 				g.hud.AssignControlGroup(i)
 			} else {
-				g.hud.RecallControlGroup(i)
+				g.hud.RecallControlGroup(g.gameLoop.World, i)
 			}
 		}
 	}
 
-	if g.input.IsKeyJustPressed(ebiten.KeyH) {
+	if g.input.Action(input.ActionDeploy) {
 		g.tryDeployMCV()
 	}
-	if g.input.IsKeyJustPressed(ebiten.KeyDelete) {
+	if g.input.Action(input.ActionSell) {
 		g.trySellBuilding()
 	}
+	if g.input.Action(input.ActionPowerToggle) {
+		g.togglePowerSelected()
+	}
+	if g.input.Action(input.ActionCycleSubGroup) {
+		g.hud.CycleSubGroup(g.gameLoop.World)
+	}
+	if g.input.Action(input.ActionFlare) && g.tileMap.InBounds(g.hoverTileX, g.hoverTileY) {
+		if g.envSys.Flare(0, g.hoverTileX, g.hoverTileY) {
+			g.audioMgr.PlaySFX(audio.SndClick, float64(g.hoverTileX), float64(g.hoverTileY))
+		}
+	}
+	if g.input.Action(input.ActionChrono) && g.tileMap.InBounds(g.hoverTileX, g.hoverTileY) {
+		g.chronoSelected(g.hoverTileX, g.hoverTileY)
+	}
+	// Stop acts at once, whether from the key or the command button
+	if g.input.Action(input.ActionStop) || g.hud.CurrentCommand == ui.CmdStop {
+		g.stopSelected()
+		g.hud.CurrentCommand = ui.CmdNone
+	}
+	if g.input.Action(input.ActionRelocate) {
+		g.startRelocate()
+	}
+	if g.input.Action(input.ActionScatter) {
+		systems.ScatterUnits(g.gameLoop.World, g.navGrid, g.hud.SelectedIDs)
+	}
+	if g.input.Action(input.ActionAttackPoint) {
+		g.setAttackPoint(g.hoverTileX, g.hoverTileY)
+	}
+	if g.input.Action(input.ActionWarMode) {
+		g.toggleWarMode()
+	}
 
 	// Handle right click
 	if g.input.RightJustPressed {
@@ -293,6 +343,8 @@ func (g *Game) Update() error {
 			g.hud.SellMode = false
 		} else if g.hud.Placement.Active {
 			g.cancelPlacementWithRefund()
+		} else if g.relocate != nil && g.relocate.picking {
+			g.relocate = nil
 		} else if g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) && g.hud.ActiveTab == ui.TabUnits {
 			// Right-click on unit cameo: cancel production
 			if uKey := g.hud.GetSidebarUnitClick(g.input.MouseX, g.input.MouseY, g.gameLoop.World); uKey != "" {
@@ -301,7 +353,21 @@ func (g *Game) Update() error {
 		} else if !g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
 			gx, gy := int(math.Floor(wx)), int(math.Floor(wy))
 			w := g.gameLoop.World
+			// Harvesters sent onto ore adopt that patch as their field
+			tile := g.tileMap.At(gx, gy)
+			onOre := tile != nil && tile.OreAmount > 0
+			// Engineers sent onto a wrecked span go and rebuild it
+			onWreck := tile != nil && tile.Terrain == maplib.TerrainBridgeWreck
 			for _, id := range g.hud.SelectedIDs {
+				if own, ok := core.GetComponent[*core.Owner](w, id); !ok || own.PlayerID != 0 {
+					continue // taken over since it was selected
+				}
+				if onWreck && systems.OrderBridgeRepair(w, g.navGrid, g.tileMap, id, gx, gy) {
+					continue
+				}
+				if onOre && systems.AssignField(w, g.navGrid, id, gx, gy) {
+					continue
+				}
 				if w.Has(id, core.CompMovable) {
 					systems.OrderMove(w, g.navGrid, id, gx, gy)
 				}
@@ -312,12 +378,24 @@ func (g *Game) Update() error {
 
 	// Handle left click
 	if g.input.LeftJustReleased && !g.input.Dragging {
-		if g.hud.Placement.Active && g.hud.Placement.Valid &&
+		if g.hud.Placement.Active && len(g.hud.Placement.Segments) > 0 &&
+			!g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
+			g.placeWalls()
+		} else if g.hud.Placement.Active && g.hud.Placement.Valid &&
 			!g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
 			g.placeBuilding()
+		} else if g.relocate != nil && g.relocate.picking && !g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
+			g.pickRelocateTarget()
 		} else if g.hud.IsInMinimap(g.input.MouseX, g.input.MouseY) {
-			wmx, wmy := g.hud.GetMinimapWorldPos(g.input.MouseX, g.input.MouseY, MapSize)
-			g.renderer.Camera.CenterOn(wmx, wmy)
+			// A dark minimap still swallows the click but doesn't scroll
+			if g.hud.RadarOnline() {
+				wmx, wmy := g.hud.GetMinimapWorldPos(g.input.MouseX, g.input.MouseY)
+				g.renderer.View().CenterOn(wmx, wmy)
+			}
+		} else if g.hud.PowerButtonHit(g.input.MouseX, g.input.MouseY, g.gameLoop.World) {
+			g.togglePowerSelected()
+		} else if g.hud.HandleSubGroupClick(g.input.MouseX, g.input.MouseY, g.gameLoop.World, g.input.ActionHeld(input.ActionAddModifier)) {
+			// Portrait click narrowed the selection
 		} else if g.hud.HandleClick(g.input.MouseX, g.input.MouseY) {
 			// Tab or command button click handled
 		} else if g.hud.RepairMode && !g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
@@ -327,11 +405,11 @@ func (g *Game) Update() error {
 		} else if bKey := g.hud.GetSidebarBuildingClick(g.input.MouseX, g.input.MouseY, g.gameLoop.World); bKey != "" {
 			g.startBuildingPurchase(bKey)
 		} else if uKey := g.hud.GetSidebarUnitClick(g.input.MouseX, g.input.MouseY, g.gameLoop.World); uKey != "" {
-			g.queueUnit(uKey)
+			g.queueUnit(uKey, g.queueCount())
 		} else if g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
 			// Click in sidebar but not on any button — consume to avoid selecting behind
 		} else {
-			shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+			shift := g.input.ActionHeld(input.ActionAddModifier)
 			g.handleSelection(wx, wy, shift)
 		}
 	}
@@ -339,9 +417,12 @@ func (g *Game) Update() error {
 	if g.input.LeftJustReleased && g.input.Dragging && !g.hud.Placement.Active {
 		g.handleBoxSelect()
 	}
+	if g.input.LeftJustReleased {
+		g.wallDragging = false
+	}
 
-	if g.input.IsKeyJustPressed(ebiten.KeyQ) {
-		g.queueUnit("gi")
+	if g.input.Action(input.ActionQueueInfantry) {
+		g.queueUnit("gi", 1)
 	}
 
 	// Repair active building
@@ -356,10 +437,11 @@ func (g *Game) Update() error {
 		}
 	}
 
-	g.audioMgr.SetCameraPos(g.renderer.Camera.TargetX, g.renderer.Camera.TargetY)
+	g.audioMgr.SetCameraPos(g.renderer.ScreenToWorld(g.hud.ScreenW/2, g.hud.ScreenH/2))
+	g.audioMgr.Update(1.0 / 60.0)
 
 	g.gameLoop.Update()
-	g.eventBus.Dispatch()
+	g.hud.PruneDead(g.gameLoop.World)
 
 	return nil
 }
@@ -389,10 +471,11 @@ func (g *Game) startBuildingPurchase(key string) {
 	// Check credits
 	if player.Credits < bdef.Cost {
 		g.hud.ShowMessage("Insufficient Funds", 2.0)
+		g.eventBus.Publish(core.Event{Type: core.EvtNoFunds, Tick: g.gameLoop.World.TickCount, Payload: 0})
 		return
 	}
 
-	player.Credits -= bdef.Cost
+	player.Spend(bdef.Cost)
 	g.hud.StartPlacement(key)
 }
 
@@ -407,8 +490,8 @@ func (g *Game) placeBuilding() {
 
 	systems.PlaceBuilding(g.gameLoop.World, key, g.techTree, 0, tx, ty, faction, g.eventBus)
 
-	// Mark tiles occupied
-	if bdef, ok := g.techTree.Buildings[key]; ok {
+	// Mark tiles occupied; vehicles drive onto pads
+	if bdef, ok := g.techTree.Buildings[key]; ok && !bdef.Pad {
 		systems.OccupyTiles(g.tileMap, tx, ty, bdef.SizeX, bdef.SizeY)
 	}
 
@@ -416,6 +499,117 @@ func (g *Game) placeBuilding() {
 	g.audioMgr.PlaySFX(audio.SndBuild, float64(tx), float64(ty))
 }
 
+// updateWallDrag tracks a click-drag while placing walls and previews the
+// segments between the drag start and the hovered tile
+func (g *Game) updateWallDrag() {
+	if g.input.LeftJustPressed && !g.hud.IsInSidebar(g.input.MouseX, g.input.MouseY) {
+		g.wallDragging = true
+		g.wallStartX, g.wallStartY = g.hoverTileX, g.hoverTileY
+	}
+	if !g.wallDragging {
+		g.hud.Placement.Segments = nil
+		g.hud.Placement.SegmentValid = nil
+		return
+	}
+	rect := g.input.ActionHeld(input.ActionAddModifier)
+	segs := wallSegments(g.wallStartX, g.wallStartY, g.hoverTileX, g.hoverTileY, rect)
+	valid := make([]bool, len(segs))
+	g.hud.Placement.Valid = false
+	for i, t := range segs {
+		valid[i] = g.canPlaceBuilding(t.X, t.Y, 1, 1)
+		if valid[i] {
+			g.hud.Placement.Valid = true
+		}
+	}
+	g.hud.Placement.Segments = segs
+	g.hud.Placement.SegmentValid = valid
+}
+
+// wallSegments returns the tiles of a wall dragged from (x0,y0) to (x1,y1):
+// a straight line along the longer axis, or the rectangle outline if rect.
+func wallSegments(x0, y0, x1, y1 int, rect bool) []core.TilePos {
+	var segs []core.TilePos
+	step := func(a, b int) int {
+		if b < a {
+			return -1
+		}
+		return 1
+	}
+	if !rect || x0 == x1 || y0 == y1 {
+		dx, dy := x1-x0, y1-y0
+		if dx*dx >= dy*dy {
+			for x := x0; ; x += step(x0, x1) {
+				segs = append(segs, core.TilePos{X: x, Y: y0})
+				if x == x1 {
+					break
+				}
+			}
+		} else {
+			for y := y0; ; y += step(y0, y1) {
+				segs = append(segs, core.TilePos{X: x0, Y: y})
+				if y == y1 {
+					break
+				}
+			}
+		}
+		return segs
+	}
+	if x0 > x1 {
+		x0, x1 = x1, x0
+	}
+	if y0 > y1 {
+		y0, y1 = y1, y0
+	}
+	for x := x0; x <= x1; x++ {
+		segs = append(segs, core.TilePos{X: x, Y: y0}, core.TilePos{X: x, Y: y1})
+	}
+	for y := y0 + 1; y < y1; y++ {
+		segs = append(segs, core.TilePos{X: x0, Y: y}, core.TilePos{X: x1, Y: y})
+	}
+	return segs
+}
+
+// placeWalls builds the previewed wall segments. The first segment was paid
+// for when placement started; the rest are charged one by one, and segments
+// that can't be built (or afforded) cost nothing.
+func (g *Game) placeWalls() {
+	key := g.hud.Placement.BuildingKey
+	bdef, ok := g.techTree.Buildings[key]
+	player := g.players.GetPlayer(0)
+	if !ok || player == nil {
+		return
+	}
+	segs, valid := g.hud.Placement.Segments, g.hud.Placement.SegmentValid
+	player.Refund(bdef.Cost) // refund the prepaid segment, charge per segment below
+
+	placed, blocked, unpaid := 0, 0, 0
+	for i, t := range segs {
+		if !valid[i] {
+			blocked++
+			continue
+		}
+		if player.Credits < bdef.Cost {
+			unpaid++
+			continue
+		}
+		player.Spend(bdef.Cost)
+		systems.PlaceBuilding(g.gameLoop.World, key, g.techTree, 0, t.X, t.Y, player.Faction, g.eventBus)
+		systems.OccupyTiles(g.tileMap, t.X, t.Y, 1, 1)
+		placed++
+	}
+
+	switch {
+	case unpaid > 0:
+		g.hud.ShowMessage(fmt.Sprintf("Insufficient Funds: %d segments skipped", unpaid), 2.0)
+	case blocked > 0:
+		g.hud.ShowMessage(fmt.Sprintf("%d blocked segments refunded", blocked), 2.0)
+	}
+	g.hud.CancelPlacement()
+	if placed > 0 {
+		g.audioMgr.PlaySFX(audio.SndBuild, float64(segs[0].X), float64(segs[0].Y))
+	}
+}
+
 func (g *Game) cancelPlacementWithRefund() {
 	if !g.hud.Placement.Active {
 		return
@@ -424,69 +618,85 @@ func (g *Game) cancelPlacementWithRefund() {
 	if bdef, ok := g.techTree.Buildings[key]; ok {
 		player := g.players.GetPlayer(0)
 		if player != nil {
-			player.Credits += bdef.Cost
+			player.Refund(bdef.Cost)
 		}
 	}
 	g.hud.CancelPlacement()
 }
 
+// placementOrigin returns the top-left tile of a sizeX x sizeY footprint
+// centred on world point (wx, wy) and snapped to whole tiles
+func placementOrigin(wx, wy float64, sizeX, sizeY int) (int, int) {
+	return int(math.Floor(wx - float64(sizeX)/2 + 0.5)), int(math.Floor(wy - float64(sizeY)/2 + 0.5))
+}
+
 func (g *Game) canPlaceBuilding(tileX, tileY, sizeX, sizeY int) bool {
-	for dy := 0; dy < sizeY; dy++ {
-		for dx := 0; dx < sizeX; dx++ {
-			tx, ty := tileX+dx, tileY+dy
-			if !g.tileMap.InBounds(tx, ty) {
-				return false
-			}
-			tile := g.tileMap.At(tx, ty)
-			if tile == nil {
-				return false
-			}
-			// Can't build on water, deep water, cliffs
-			if tile.Terrain == maplib.TerrainWater || tile.Terrain == maplib.TerrainDeepWater || tile.Terrain == maplib.TerrainCliff {
-				return false
-			}
-			// Can't overlap existing buildings
-			if tile.Occupied {
-				return false
-			}
-		}
+	return systems.FootprintClear(g.gameLoop.World, g.tileMap, tileX, tileY, sizeX, sizeY, 0) &&
+		systems.InBuildRadius(g.gameLoop.World, g.techTree, 0, g.localFaction(), tileX, tileY)
+}
+
+// localFaction returns the local player's faction
+func (g *Game) localFaction() string {
+	if p := g.players.GetPlayer(0); p != nil {
+		return p.Faction
 	}
-	// Must be near an existing owned building (build radius ~10 tiles)
+	return ""
+}
+
+func (g *Game) tryDeployMCV() {
+	if !systems.DeployAll(g.gameLoop.World, g.tileMap, g.hud.SelectedIDs) {
+		g.hud.ShowMessage("Can't deploy here", 2.0)
+	}
+}
+
+// stopSelected halts the selected units and puts them on hold
+func (g *Game) stopSelected() {
 	w := g.gameLoop.World
-	nearBuilding := false
-	for _, bid := range w.Query(core.CompBuilding, core.CompOwner, core.CompPosition) {
-		o := w.Get(bid, core.CompOwner).(*core.Owner)
-		if o.PlayerID != 0 {
-			continue
-		}
-		bp := w.Get(bid, core.CompPosition).(*core.Position)
-		dx := float64(tileX) - bp.X
-		dy := float64(tileY) - bp.Y
-		if dx*dx+dy*dy < 100 {
-			nearBuilding = true
-			break
-		}
+	for _, id := range g.hud.SelectedIDs {
+		systems.StopUnit(w, id)
 	}
-	return nearBuilding
 }
 
-func (g *Game) tryDeployMCV() {
+// chronoSelected starts a jump for every selected chrono unit, keeping the
+// squad's formation around the target tile
+func (g *Game) chronoSelected(tx, ty int) {
 	w := g.gameLoop.World
+	var ids []core.EntityID
+	var cx, cy float64
 	for _, id := range g.hud.SelectedIDs {
-		if w.Has(id, core.CompMCV) {
-			systems.DeployMCV(w, id, g.eventBus)
-			g.hud.SelectedIDs = nil
-			return
+		own := w.Get(id, core.CompOwner)
+		if !w.Has(id, core.CompChrono) || !w.Has(id, core.CompPosition) || own == nil || own.(*core.Owner).PlayerID != 0 {
+			continue
 		}
-		if bldg := w.Get(id, core.CompBuilding); bldg != nil {
-			b := bldg.(*core.Building)
-			if b.IsConYard {
-				systems.UndeployConYard(w, id, g.eventBus)
-				g.hud.SelectedIDs = nil
-				return
-			}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		cx += pos.X
+		cy += pos.Y
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	cx /= float64(len(ids))
+	cy /= float64(len(ids))
+	started := false
+	for _, id := range ids {
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		x := tx + int(math.Round(pos.X-cx))
+		y := ty + int(math.Round(pos.Y-cy))
+		if systems.StartChrono(w, g.tileMap, id, x, y) || systems.StartChrono(w, g.tileMap, id, tx, ty) {
+			started = true
 		}
 	}
+	if started {
+		g.audioMgr.PlaySFX(audio.SndClick, float64(tx), float64(ty))
+	} else {
+		g.hud.ShowMessage("Chrono not ready", 1.5)
+	}
+}
+
+// togglePowerSelected powers selected buildings down, or back up if all are already off
+func (g *Game) togglePowerSelected() {
+	systems.TogglePower(g.gameLoop.World, g.players, g.hud.SelectedIDs, 0)
 }
 
 func (g *Game) trySellBuilding() {
@@ -496,8 +706,7 @@ func (g *Game) trySellBuilding() {
 			b := bldg.(*core.Building)
 			if b.Sellable {
 				pos := w.Get(id, core.CompPosition).(*core.Position)
-				g.hud.AddEffect(pos.X, pos.Y, "explosion", 15)
-				g.renderer.Particles.AddExplosion(pos.X, pos.Y)
+				g.explode(pos.X, pos.Y)
 				// Free occupied tiles
 				systems.FreeTiles(g.tileMap, int(pos.X), int(pos.Y), b.SizeX, b.SizeY)
 				systems.SellBuilding(w, id, g.techTree, g.players)
@@ -507,7 +716,11 @@ func (g *Game) trySellBuilding() {
 	g.hud.SelectedIDs = nil
 }
 
-func (g *Game) queueUnit(unitType string) {
+// QueueBatch is how many units a shift-click queues
+const QueueBatch = 5
+
+// queueUnit queues up to n units of a type, as many as the player can afford
+func (g *Game) queueUnit(unitType string, n int) {
 	w := g.gameLoop.World
 	player := g.players.GetPlayer(0)
 	udef, ok := g.techTree.Units[unitType]
@@ -518,6 +731,7 @@ func (g *Game) queueUnit(unitType string) {
 	// Check credits
 	if player.Credits < udef.Cost {
 		g.hud.ShowMessage("Insufficient Funds", 2.0)
+		g.eventBus.Publish(core.Event{Type: core.EvtNoFunds, Tick: g.gameLoop.World.TickCount, Payload: 0})
 		return
 	}
 
@@ -528,15 +742,34 @@ func (g *Game) queueUnit(unitType string) {
 	}
 
 	// Find a production building that can produce this unit
-	bid := systems.FindProductionBuilding(w, g.techTree, 0, unitType)
-	if bid == 0 {
+	if systems.FindProductionBuilding(w, g.techTree, 0, unitType) == 0 {
 		g.hud.ShowMessage("No building can produce this unit", 2.0)
 		return
 	}
 
-	prod := w.Get(bid, core.CompProduction).(*core.Production)
-	player.Credits -= udef.Cost
-	prod.Queue = append(prod.Queue, unitType)
+	systems.QueueUnits(w, g.techTree, g.players, 0, unitType, n)
+}
+
+// buildSlot acts on the nth visible slot of the sidebar tab as if clicked
+func (g *Game) buildSlot(n int) {
+	items := g.hud.GetBuildItems(g.gameLoop.World)
+	idx := n + g.hud.ScrollOffset
+	if idx >= len(items) {
+		return
+	}
+	if items[idx].IsBuilding {
+		g.startBuildingPurchase(items[idx].Key)
+	} else {
+		g.queueUnit(items[idx].Key, g.queueCount())
+	}
+}
+
+// queueCount is how many units a queue order asks for
+func (g *Game) queueCount() int {
+	if g.input.ActionHeld(input.ActionAddModifier) {
+		return QueueBatch
+	}
+	return 1
 }
 
 func (g *Game) tryStartRepair(wx, wy float64) {
@@ -548,7 +781,7 @@ func (g *Game) tryStartRepair(wx, wy float64) {
 			continue
 		}
 		pos := w.Get(id, core.CompPosition).(*core.Position)
-		sx, sy := g.renderer.Camera.WorldToScreen(pos.X, pos.Y)
+		sx, sy := g.renderer.View().WorldToScreen(pos.X, pos.Y)
 		dx := float64(g.input.MouseX - sx)
 		dy := float64(g.input.MouseY - sy)
 		if math.Sqrt(dx*dx+dy*dy) < 30 {
@@ -566,6 +799,14 @@ func (g *Game) tryStartRepair(wx, wy float64) {
 	g.hud.ShowMessage("Click on a damaged building", 1.5)
 }
 
+// explode blows up a sold building: a HUD burst, plus particles in 3D
+func (g *Game) explode(x, y float64) {
+	g.hud.AddEffect(x, y, "explosion", 15)
+	if g.r3d != nil {
+		g.r3d.Particles.AddExplosion(x, y)
+	}
+}
+
 func (g *Game) trySellBuildingAtPos(wx, wy float64) {
 	w := g.gameLoop.World
 	for _, id := range w.Query(core.CompBuilding, core.CompOwner, core.CompPosition) {
@@ -575,12 +816,11 @@ func (g *Game) trySellBuildingAtPos(wx, wy float64) {
 		}
 		pos := w.Get(id, core.CompPosition).(*core.Position)
 		bldg := w.Get(id, core.CompBuilding).(*core.Building)
-		sx, sy := g.renderer.Camera.WorldToScreen(pos.X, pos.Y)
+		sx, sy := g.renderer.View().WorldToScreen(pos.X, pos.Y)
 		dx := float64(g.input.MouseX - sx)
 		dy := float64(g.input.MouseY - sy)
 		if math.Sqrt(dx*dx+dy*dy) < 30 && bldg.Sellable {
-			g.hud.AddEffect(pos.X, pos.Y, "explosion", 15)
-			g.renderer.Particles.AddExplosion(pos.X, pos.Y)
+			g.explode(pos.X, pos.Y)
 			systems.FreeTiles(g.tileMap, int(pos.X), int(pos.Y), bldg.SizeX, bldg.SizeY)
 			systems.SellBuilding(w, id, g.techTree, g.players)
 			g.hud.SellMode = false
@@ -591,22 +831,10 @@ func (g *Game) trySellBuildingAtPos(wx, wy float64) {
 	g.hud.ShowMessage("Click on a building to sell", 1.5)
 }
 
+// cancelUnitProduction drops the last queued unit of a type and refunds it
 func (g *Game) cancelUnitProduction(unitKey string) {
-	w := g.gameLoop.World
-	// Find a production building with this unit in queue
-	for _, bid := range w.Query(core.CompProduction, core.CompOwner, core.CompBuildingName) {
-		own := w.Get(bid, core.CompOwner).(*core.Owner)
-		if own.PlayerID != 0 {
-			continue
-		}
-		prod := w.Get(bid, core.CompProduction).(*core.Production)
-		for _, qk := range prod.Queue {
-			if qk == unitKey {
-				systems.CancelUnitProduction(w, g.techTree, bid, g.players)
-				g.hud.ShowMessage("Production cancelled", 1.0)
-				return
-			}
-		}
+	if systems.DequeueUnit(g.gameLoop.World, g.techTree, g.players, 0, unitKey) {
+		g.hud.ShowMessage("Production cancelled", 1.0)
 	}
 }
 
@@ -615,21 +843,16 @@ func (g *Game) handleSelection(wx, wy float64, shift bool) {
 	if !shift {
 		g.hud.SelectedIDs = nil
 	}
-	units := w.Query(core.CompPosition, core.CompSelectable, core.CompOwner)
-	for _, id := range units {
-		own := w.Get(id, core.CompOwner).(*core.Owner)
-		if own.PlayerID != 0 {
-			continue
-		}
-		pos := w.Get(id, core.CompPosition).(*core.Position)
-		sx, sy := g.renderer.Camera.WorldToScreen(pos.X, pos.Y)
-		dx := float64(g.input.MouseX - sx)
-		dy := float64(g.input.MouseY - sy)
-		if math.Sqrt(dx*dx+dy*dy) < 20 {
-			g.hud.SelectedIDs = append(g.hud.SelectedIDs, id)
-			g.audioMgr.PlaySFX(audio.SndSelect, pos.X, pos.Y)
-			break
-		}
+	id := pickAt(w, g.renderer.View(), g.input.MouseX, g.input.MouseY)
+	if id == 0 {
+		return
+	}
+	g.hud.SelectedIDs = append(g.hud.SelectedIDs, id)
+	pos := w.Get(id, core.CompPosition).(*core.Position)
+	g.audioMgr.PlaySFX(audio.SndSelect, pos.X, pos.Y)
+	// Double-clicking a factory makes it the primary for its kind
+	if g.input.DoubleClick && systems.SetPrimary(w, id) {
+		g.hud.ShowMessage("Primary building set", 1.5)
 	}
 }
 
@@ -642,51 +865,28 @@ func (g *Game) handleBoxSelect() {
 	if y1 > y2 {
 		y1, y2 = y2, y1
 	}
-	w := g.gameLoop.World
-	g.hud.SelectedIDs = nil
-	units := w.Query(core.CompPosition, core.CompSelectable, core.CompOwner)
-	for _, id := range units {
-		own := w.Get(id, core.CompOwner).(*core.Owner)
-		if own.PlayerID != 0 {
-			continue
-		}
-		pos := w.Get(id, core.CompPosition).(*core.Position)
-		sx, sy := g.renderer.Camera.WorldToScreen(pos.X, pos.Y)
-		if sx >= x1 && sx <= x2 && sy >= y1 && sy <= y2 {
-			g.hud.SelectedIDs = append(g.hud.SelectedIDs, id)
-		}
-	}
+	g.hud.SelectedIDs = boxSelect(g.gameLoop.World, g.renderer.View(), x1, y1, x2, y2, g.input.ActionHeld(input.ActionBoxAll))
 }
 
 func (g *Game) handleCamera() {
 	speed := g.scrollSpeed / 60.0
-	if ebiten.IsKeyPressed(ebiten.KeyW) || ebiten.IsKeyPressed(ebiten.KeyUp) {
-		g.renderer.Camera.Pan(0, -speed)
+	if g.input.ActionHeld(input.ActionScrollUp) {
+		g.renderer.View().Pan(0, -speed)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyS) || ebiten.IsKeyPressed(ebiten.KeyDown) {
-		g.renderer.Camera.Pan(0, speed)
+	if g.input.ActionHeld(input.ActionScrollDown) {
+		g.renderer.View().Pan(0, speed)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyA) || ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		g.renderer.Camera.Pan(-speed, 0)
+	if g.input.ActionHeld(input.ActionScrollLeft) {
+		g.renderer.View().Pan(-speed, 0)
 	}
-	if ebiten.IsKeyPressed(ebiten.KeyD) || ebiten.IsKeyPressed(ebiten.KeyRight) {
-		g.renderer.Camera.Pan(speed, 0)
+	if g.input.ActionHeld(input.ActionScrollRight) {
+		g.renderer.View().Pan(speed, 0)
 	}
-	cam := g.renderer.Camera
-	if cam.EdgeScroll {
-		edge := cam.EdgeSize
-		if g.input.MouseX < edge {
-			cam.Pan(-speed, 0)
-		}
-		if g.input.MouseX > ScreenWidth-edge {
-			cam.Pan(speed, 0)
-		}
-		if g.input.MouseY < edge {
-			cam.Pan(0, -speed)
-		}
-		if g.input.MouseY > ScreenHeight-edge {
-			cam.Pan(0, speed)
-		}
+	cam := g.renderer.View()
+	if g.edgeScrollActive() {
+		dx, dy := cam.EdgeDir(g.input.MouseX, g.input.MouseY)
+		espeed := g.edgeSpeed / 60.0
+		cam.Pan(float64(dx)*espeed, float64(dy)*espeed)
 	}
 	if g.input.ScrollY != 0 {
 		cam.ZoomAt(g.input.ScrollY, g.input.MouseX, g.input.MouseY)
@@ -696,23 +896,39 @@ func (g *Game) handleCamera() {
 	}
 }
 
-func (g *Game) Draw(screen *ebiten.Image) {
-	// Main menu / settings (no game scene)
-	if g.menu.State == ui.StateMainMenu || g.menu.State == ui.StateSkirmishSetup ||
-		(g.menu.State == ui.StateSettings && g.menu.PrevState == ui.StateMainMenu) {
-		g.menu.Draw(screen)
+// Resize adapts the camera and HUD to a new screen size
+func (g *Game) Resize(w, h int) {
+	g.renderer.Resize(w, h)
+	g.hud.Resize(w, h)
+}
 
-		// Screenshot capture
-		frameCount++
-		if screenshotTarget != "" && frameCount >= screenshotFrame {
-			g.saveScreenshot(screen)
-		}
-		return
-	}
+// edgeScrollActive reports whether the cursor should edge-scroll: the option
+// is on, the window has focus, and the cursor is inside the window within the
+// edge band. Only the band counts, so the right edge still scrolls where the
+// sidebar covers it while the rest of the sidebar never does. This stops the
+// view drifting while alt-tabbed or while the pointer rests outside the
+// window.
+func (g *Game) edgeScrollActive() bool {
+	cam := g.renderer.View()
+	mx, my := g.input.MouseX, g.input.MouseY
+	if !g.edgeScroll || !ebiten.IsFocused() {
+		return false
+	}
+	if mx < 0 || my < 0 || mx >= g.hud.ScreenW || my >= g.hud.ScreenH {
+		return false
+	}
+	dx, dy := cam.EdgeDir(mx, my)
+	return dx != 0 || dy != 0
+}
 
+func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(color.RGBA{12, 12, 20, 255})
 
-	// Draw 3D scene (terrain + buildings + units + projectiles + particles)
+	// Draw the scene (terrain + buildings + units + projectiles + particles)
+	if g.r3d != nil {
+		g.r3d.Darkness = g.envSys.Darkness()
+		g.r3d.Weather = g.envSys.Weather
+	}
 	g.renderer.DrawScene(screen, g.tileMap, g.gameLoop.World, 0)
 
 	if g.showGrid {
@@ -722,18 +938,31 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	// Fog of war overlay
 	g.drawFogOverlay(screen)
 
-	// Hover tile highlight in 3D
+	// Hover tile highlight
 	if g.tileMap.InBounds(g.hoverTileX, g.hoverTileY) {
 		g.drawHoverTile(screen)
 	}
 
-	// Health bars as 2D overlays at 3D projected positions
+	// Health bars as 2D overlays at projected positions
 	g.drawHealthBars(screen)
+	g.drawPrimaryBadges(screen)
+	g.drawAttackPoint(screen)
+	g.hud.DrawFloaters(screen, func(x, y float64) (int, int) {
+		sx, sy := g.renderer.Project(x, y, 0.5)
+		return sx, sy
+	})
 
-	// Placement ghost in 3D
+	// Placement ghost
 	if g.hud.Placement.Active {
 		g.drawPlacementGhost(screen)
 	}
+	if r := g.relocate; r != nil && r.picking {
+		for dx := 0; dx < systems.ConYardSize; dx++ {
+			for dy := 0; dy < systems.ConYardSize; dy++ {
+				g.drawGhostTile(screen, r.tx+dx, r.ty+dy, r.valid)
+			}
+		}
+	}
 
 	// Selection box
 	if x1, y1, x2, y2, active := g.input.DragRect(); active && !g.hud.Placement.Active {
@@ -745,7 +974,17 @@ func (g *Game) Draw(screen *ebiten.Image) {
 
 	// Placement mode indicator
 	if g.hud.Placement.Active {
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Placing: %s (Click to place, ESC/Right-click to cancel)", g.hud.Placement.BuildingKey), 10, ScreenHeight-20)
+		hint := "Click to place"
+		if g.hud.Placement.BuildingKey == "wall" {
+			hint = "Drag for a line, Shift+drag for a box"
+			if n := len(g.hud.Placement.Segments); n > 0 {
+				hint = fmt.Sprintf("%d segments", n)
+			}
+		}
+		g.hud.Font.DrawText(screen, fmt.Sprintf("Placing: %s (%s, ESC/Right-click to cancel)", g.hud.Placement.BuildingKey, hint), 10, g.hud.ScreenH-20, ui.FontNormal, color.White)
+	}
+	if r := g.relocate; r != nil && r.picking {
+		g.hud.Font.DrawText(screen, "Relocate: click where the MCV should deploy (ESC/Right-click to cancel)", 10, g.hud.ScreenH-20, ui.FontNormal, color.White)
 	}
 
 	// Overlay menus (pause, settings, game over) drawn on top of game scene
@@ -753,28 +992,32 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		g.menu.Draw(screen)
 	}
 
+	// Perf overlay (F3)
+	g.perf.Frame()
+	stats := ui.PerfStats{
+		Ticks:    g.gameLoop.TicksRun,
+		TickTime: g.gameLoop.TickTime,
+		Entities: g.gameLoop.World.EntityCount(),
+	}
+	if g.r3d != nil {
+		stats.SceneTime, stats.DrawCalls = g.r3d.Stats.SceneTime, g.r3d.Stats.DrawCalls
+	}
+	g.perf.Draw(screen, stats)
+
 	// Game over detection
 	for _, p := range g.players.Players {
 		if p.Defeated && p.ID == 0 && g.menu.State == ui.StatePlaying {
-			g.menu.GameOverData = ui.GameOverStats{Victory: false}
-			g.menu.State = ui.StateGameOver
 			g.gameLoop.Pause()
+			g.menu.ShowGameOver(false)
 		}
 		if p.Defeated && p.ID == 1 && g.menu.State == ui.StatePlaying {
-			g.menu.GameOverData = ui.GameOverStats{Victory: true}
-			g.menu.State = ui.StateGameOver
 			g.gameLoop.Pause()
+			g.menu.ShowGameOver(true)
 		}
 	}
-
-	// Screenshot capture
-	frameCount++
-	if screenshotTarget != "" && frameCount >= screenshotFrame {
-		g.saveScreenshot(screen)
-	}
 }
 
-func (g *Game) saveScreenshot(screen *ebiten.Image) {
+func saveScreenshot(screen *ebiten.Image) {
 	f, err := os.Create(screenshotTarget)
 	if err != nil {
 		log.Fatalf("Screenshot: %v", err)
@@ -784,7 +1027,7 @@ func (g *Game) saveScreenshot(screen *ebiten.Image) {
 		log.Fatalf("Screenshot encode: %v", err)
 	}
 	f.Close()
-	log.Printf("Screenshot saved to %s (%dx%d)", screenshotTarget, ScreenWidth, ScreenHeight)
+	log.Printf("Screenshot saved to %s (%dx%d)", screenshotTarget, screen.Bounds().Dx(), screen.Bounds().Dy())
 	os.Exit(0)
 }
 
@@ -792,10 +1035,10 @@ func (g *Game) drawHoverTile(screen *ebiten.Image) {
 	x, y := g.hoverTileX, g.hoverTileY
 	hoverColor := color.RGBA{255, 255, 0, 80}
 
-	sx0, sy0, _ := g.renderer.Camera.Project3DToScreen(float64(x), 0.02, float64(y))
-	sx1, sy1, _ := g.renderer.Camera.Project3DToScreen(float64(x+1), 0.02, float64(y))
-	sx2, sy2, _ := g.renderer.Camera.Project3DToScreen(float64(x+1), 0.02, float64(y+1))
-	sx3, sy3, _ := g.renderer.Camera.Project3DToScreen(float64(x), 0.02, float64(y+1))
+	sx0, sy0 := g.renderer.Project(float64(x), float64(y), 0.02)
+	sx1, sy1 := g.renderer.Project(float64(x+1), float64(y), 0.02)
+	sx2, sy2 := g.renderer.Project(float64(x+1), float64(y+1), 0.02)
+	sx3, sy3 := g.renderer.Project(float64(x), float64(y+1), 0.02)
 
 	vector.StrokeLine(screen, float32(sx0), float32(sy0), float32(sx1), float32(sy1), 2, hoverColor, false)
 	vector.StrokeLine(screen, float32(sx1), float32(sy1), float32(sx2), float32(sy2), 2, hoverColor, false)
@@ -803,27 +1046,60 @@ func (g *Game) drawHoverTile(screen *ebiten.Image) {
 	vector.StrokeLine(screen, float32(sx3), float32(sy3), float32(sx0), float32(sy0), 2, hoverColor, false)
 }
 
+// drawHealthBars draws world health bars per the health bar setting; holding
+// the show-health key shows all of them. Each bar gets a team-color pip.
 func (g *Game) drawHealthBars(screen *ebiten.Image) {
+	showAll := g.healthBars == ui.HealthBarsAlways || g.input.ActionHeld(input.ActionShowHealth)
+	if g.healthBars == ui.HealthBarsNever && !showAll {
+		return
+	}
+	selected := make(map[core.EntityID]bool, len(g.hud.SelectedIDs))
+	for _, id := range g.hud.SelectedIDs {
+		selected[id] = true
+	}
 	w := g.gameLoop.World
 	for _, id := range w.Query(core.CompPosition, core.CompHealth, core.CompOwner) {
 		pos := w.Get(id, core.CompPosition).(*core.Position)
 		hp := w.Get(id, core.CompHealth).(*core.Health)
-		if hp.Ratio() >= 1.0 {
-			continue // Don't show full health bars
+		if !showAll && hp.Ratio() >= 1.0 && !selected[id] {
+			continue // Damaged mode: skip full health unless selected
 		}
+		own := w.Get(id, core.CompOwner).(*core.Owner)
 
 		// Project to screen with slight Y offset above the entity
 		heightOffset := 0.5
 		if w.Has(id, core.CompBuilding) {
 			heightOffset = 1.5
 		}
-		sx, sy, _ := g.renderer.Camera.Project3DToScreen(pos.X, heightOffset, pos.Y)
+		sx, sy := g.renderer.Project(pos.X, pos.Y, heightOffset)
 
 		barWidth := 30
 		if w.Has(id, core.CompBuilding) {
 			barWidth = 50
 		}
-		g.renderer.DrawHealthBar(screen, sx, sy, hp.Ratio(), barWidth)
+		render.DrawHealthBar(screen, sx, sy, hp.Ratio(), barWidth)
+
+		pip := color.RGBA{180, 180, 180, 255} // neutral
+		if p := g.players.GetPlayer(own.PlayerID); p != nil {
+			pip = p.RGBA()
+		}
+		render.DrawTeamPip(screen, sx, sy, barWidth, pip)
+	}
+}
+
+// drawPrimaryBadges labels the local player's primary production buildings
+func (g *Game) drawPrimaryBadges(screen *ebiten.Image) {
+	w := g.gameLoop.World
+	for _, id := range w.Query(core.CompProduction, core.CompBuilding, core.CompPosition, core.CompOwner) {
+		if !w.Get(id, core.CompProduction).(*core.Production).Primary || w.Get(id, core.CompOwner).(*core.Owner).PlayerID != 0 {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		b := w.Get(id, core.CompBuilding).(*core.Building)
+		sx, sy := g.renderer.Project(pos.X+float64(b.SizeX)/2, pos.Y+float64(b.SizeY)/2, 0.2)
+		tw := g.hud.Font.Measure("PRIMARY", ui.FontSmall) + 8
+		vector.DrawFilledRect(screen, float32(sx-tw/2), float32(sy), float32(tw), 14, color.RGBA{0, 0, 0, 180}, false)
+		g.hud.Font.DrawCentered(screen, "PRIMARY", sx, sy+1, ui.FontSmall, color.RGBA{220, 190, 60, 255})
 	}
 }
 
@@ -831,28 +1107,83 @@ func (g *Game) drawPlacementGhost(screen *ebiten.Image) {
 	tx, ty := g.hud.Placement.TileX, g.hud.Placement.TileY
 	sx, sy := g.hud.Placement.SizeX, g.hud.Placement.SizeY
 
-	var outlineColor color.RGBA
-	if g.hud.Placement.Valid {
-		outlineColor = color.RGBA{0, 255, 0, 150}
-	} else {
-		outlineColor = color.RGBA{255, 0, 0, 150}
+	g.drawBuildableArea(screen)
+
+	// Wall drag: one outline per segment
+	if segs := g.hud.Placement.Segments; len(segs) > 0 {
+		for i, t := range segs {
+			g.drawGhostTile(screen, t.X, t.Y, g.hud.Placement.SegmentValid[i])
+		}
+		return
 	}
 
 	// Draw outline of placement area
 	for dx := 0; dx < sx; dx++ {
 		for dy := 0; dy < sy; dy++ {
-			fx, fy := float64(tx+dx), float64(ty+dy)
-			s0x, s0y, _ := g.renderer.Camera.Project3DToScreen(fx, 0.03, fy)
-			s1x, s1y, _ := g.renderer.Camera.Project3DToScreen(fx+1, 0.03, fy)
-			s2x, s2y, _ := g.renderer.Camera.Project3DToScreen(fx+1, 0.03, fy+1)
-			s3x, s3y, _ := g.renderer.Camera.Project3DToScreen(fx, 0.03, fy+1)
+			g.drawGhostTile(screen, tx+dx, ty+dy, g.hud.Placement.Valid)
+		}
+	}
+}
+
+// drawBuildableArea tints the visible tiles a building could be placed on
+func (g *Game) drawBuildableArea(screen *ebiten.Image) {
+	if g.fogWhiteImg == nil {
+		g.fogWhiteImg = ebiten.NewImage(4, 4)
+		g.fogWhiteImg.Fill(color.White)
+	}
+	mask := systems.BuildMask(g.gameLoop.World, g.techTree, 0, g.localFaction(), g.tileMap.Width, g.tileMap.Height)
+	minX, minY, maxX, maxY := g.renderer.View().VisibleTileRange(g.tileMap.Width, g.tileMap.Height)
 
-			vector.StrokeLine(screen, float32(s0x), float32(s0y), float32(s1x), float32(s1y), 2, outlineColor, false)
-			vector.StrokeLine(screen, float32(s1x), float32(s1y), float32(s2x), float32(s2y), 2, outlineColor, false)
-			vector.StrokeLine(screen, float32(s2x), float32(s2y), float32(s3x), float32(s3y), 2, outlineColor, false)
-			vector.StrokeLine(screen, float32(s3x), float32(s3y), float32(s0x), float32(s0y), 2, outlineColor, false)
+	var vertices []ebiten.Vertex
+	var indices []uint16
+	for y := minY; y <= maxY; y++ {
+		for x := minX; x <= maxX; x++ {
+			if !systems.TileBuildable(g.gameLoop.World, g.tileMap, x, y) || !mask[y*g.tileMap.Width+x] {
+				continue
+			}
+			fx, fy := float64(x), float64(y)
+			s0x, s0y := g.renderer.Project(fx, fy, 0.02)
+			s1x, s1y := g.renderer.Project(fx+1, fy, 0.02)
+			s2x, s2y := g.renderer.Project(fx+1, fy+1, 0.02)
+			s3x, s3y := g.renderer.Project(fx, fy+1, 0.02)
+
+			base := uint16(len(vertices))
+			vertices = append(vertices,
+				ebiten.Vertex{DstX: float32(s0x), DstY: float32(s0y), SrcX: 1, SrcY: 1, ColorG: 0.1, ColorA: 0.1},
+				ebiten.Vertex{DstX: float32(s1x), DstY: float32(s1y), SrcX: 1, SrcY: 1, ColorG: 0.1, ColorA: 0.1},
+				ebiten.Vertex{DstX: float32(s2x), DstY: float32(s2y), SrcX: 1, SrcY: 1, ColorG: 0.1, ColorA: 0.1},
+				ebiten.Vertex{DstX: float32(s3x), DstY: float32(s3y), SrcX: 1, SrcY: 1, ColorG: 0.1, ColorA: 0.1},
+			)
+			indices = append(indices, base, base+1, base+2, base, base+2, base+3)
+
+			if len(vertices) >= 65000 {
+				screen.DrawTriangles(vertices, indices, g.fogWhiteImg, nil)
+				vertices = vertices[:0]
+				indices = indices[:0]
+			}
 		}
 	}
+	if len(vertices) > 0 {
+		screen.DrawTriangles(vertices, indices, g.fogWhiteImg, nil)
+	}
+}
+
+// drawGhostTile outlines one placement tile in green (valid) or red
+func (g *Game) drawGhostTile(screen *ebiten.Image, tx, ty int, valid bool) {
+	outlineColor := color.RGBA{255, 0, 0, 150}
+	if valid {
+		outlineColor = color.RGBA{0, 255, 0, 150}
+	}
+	fx, fy := float64(tx), float64(ty)
+	s0x, s0y := g.renderer.Project(fx, fy, 0.03)
+	s1x, s1y := g.renderer.Project(fx+1, fy, 0.03)
+	s2x, s2y := g.renderer.Project(fx+1, fy+1, 0.03)
+	s3x, s3y := g.renderer.Project(fx, fy+1, 0.03)
+
+	vector.StrokeLine(screen, float32(s0x), float32(s0y), float32(s1x), float32(s1y), 2, outlineColor, false)
+	vector.StrokeLine(screen, float32(s1x), float32(s1y), float32(s2x), float32(s2y), 2, outlineColor, false)
+	vector.StrokeLine(screen, float32(s2x), float32(s2y), float32(s3x), float32(s3y), 2, outlineColor, false)
+	vector.StrokeLine(screen, float32(s3x), float32(s3y), float32(s0x), float32(s0y), 2, outlineColor, false)
 }
 
 func (g *Game) drawFogOverlay(screen *ebiten.Image) {
@@ -867,7 +1198,7 @@ func (g *Game) drawFogOverlay(screen *ebiten.Image) {
 		g.fogWhiteImg.Fill(color.White)
 	}
 
-	minX, minY, maxX, maxY := g.renderer.Camera.VisibleTileRange(g.tileMap.Width, g.tileMap.Height)
+	minX, minY, maxX, maxY := g.renderer.View().VisibleTileRange(g.tileMap.Width, g.tileMap.Height)
 
 	// Batch fog triangles
 	var vertices []ebiten.Vertex
@@ -891,10 +1222,10 @@ func (g *Game) drawFogOverlay(screen *ebiten.Image) {
 			}
 
 			fx, fy := float64(x), float64(y)
-			s0x, s0y, _ := g.renderer.Camera.Project3DToScreen(fx, 0.05, fy)
-			s1x, s1y, _ := g.renderer.Camera.Project3DToScreen(fx+1, 0.05, fy)
-			s2x, s2y, _ := g.renderer.Camera.Project3DToScreen(fx+1, 0.05, fy+1)
-			s3x, s3y, _ := g.renderer.Camera.Project3DToScreen(fx, 0.05, fy+1)
+			s0x, s0y := g.renderer.Project(fx, fy, 0.05)
+			s1x, s1y := g.renderer.Project(fx+1, fy, 0.05)
+			s2x, s2y := g.renderer.Project(fx+1, fy+1, 0.05)
+			s3x, s3y := g.renderer.Project(fx, fy+1, 0.05)
 
 			base := uint16(len(vertices))
 			vertices = append(vertices,
@@ -922,8 +1253,34 @@ func (g *Game) drawFogOverlay(screen *ebiten.Image) {
 	}
 }
 
-func (g *Game) Layout(_, _ int) (int, int) {
-	return ScreenWidth, ScreenHeight
+// buildMap returns the demo map, or a generated one when -mapseed is set,
+// with the -daynight and -weather environment applied
+func buildMap() *maplib.TileMap {
+	tm := pickMap()
+	if mapDayNight {
+		tm.DayNight = true
+	}
+	if mapWeather != "" {
+		tm.Weather = maplib.Weather(mapWeather)
+	}
+	return tm
+}
+
+func pickMap() *maplib.TileMap {
+	if mapSeed < 0 {
+		return generateDemoMap()
+	}
+	tm, err := pathfind.GenerateMap(maplib.GenOptions{
+		Width:   MapSize,
+		Height:  MapSize,
+		Players: 2,
+		Seed:    mapSeed,
+	})
+	if err != nil {
+		log.Printf("Map generation failed (%v), using demo map", err)
+		return generateDemoMap()
+	}
+	return tm
 }
 
 func generateDemoMap() *maplib.TileMap {
@@ -966,6 +1323,16 @@ func generateDemoMap() *maplib.TileMap {
 	}
 	tm.SetTerrain(50, 50, 60, 60, maplib.TerrainSand)
 
+	// Hill overlooking the bridge
+	for lvl := 1; lvl <= maplib.MaxHeightLevel; lvl++ {
+		r := maplib.MaxHeightLevel - lvl + 1
+		for y := 39 - r; y <= 39+r; y++ {
+			for x := 39 - r; x <= 39+r; x++ {
+				tm.SetHeight(x, y, lvl)
+			}
+		}
+	}
+
 	tm.StartPositions = []maplib.StartPos{
 		{PlayerSlot: 0, X: 10, Y: 10},
 		{PlayerSlot: 1, X: 54, Y: 54},
@@ -974,30 +1341,41 @@ func generateDemoMap() *maplib.TileMap {
 }
 
 func main() {
-	headless := flag.Bool("headless", false, "Run in headless mode (no window)")
+	headless := flag.Bool("headless", false, "Simulate an AI-vs-AI match without a window and print the result")
+	ticks := flag.Int("ticks", 20*60*int(TickRate), "Tick limit for -headless")
 	screenshot := flag.String("screenshot", "", "Render one frame to PNG file and exit")
+	flag.Int64Var(&mapSeed, "mapseed", -1, "Generate a random map from this seed instead of the demo map")
+	flag.BoolVar(&mapDayNight, "daynight", false, "Turn on the day/night cycle")
+	flag.StringVar(&mapWeather, "weather", "", "Map weather: rain or fog")
+	flag.BoolVar(&use3D, "3d", use3D, "Draw with the 3D renderer; -3d=false uses the 2D isometric one")
+	flag.StringVar(&keyBindingsPath, "keys", keyBindingsPath, "Key binding config file (JSON: action -> key names)")
+	flag.StringVar(&settingsPath, "settings", settingsPath, "Options config file (JSON), written when options are applied")
+	flag.StringVar(&statsPath, "stats", "", "Write the last match's stats to this file (JSON)")
+	flag.IntVar(&windowWidth, "width", ScreenWidth, "Initial window width")
+	flag.IntVar(&windowHeight, "height", ScreenHeight, "Initial window height")
 	flag.Parse()
 
+	if *headless {
+		runHeadless(*ticks)
+		return
+	}
+
 	if os.Getenv("EBITENGINE_GRAPHICS_LIBRARY") == "" {
 		os.Setenv("EBITENGINE_GRAPHICS_LIBRARY", "opengl")
 	}
 
-	if *screenshot != "" || *headless {
-		screenshotPath := *screenshot
-		if screenshotPath == "" {
-			screenshotPath = "screenshot.png"
-		}
-		screenshotTarget = screenshotPath
+	if *screenshot != "" {
+		screenshotTarget = *screenshot
 		screenshotFrame = 30
 	}
 
-	ebiten.SetWindowSize(ScreenWidth, ScreenHeight)
+	ebiten.SetWindowSize(windowWidth, windowHeight)
 	ebiten.SetWindowTitle("⚔️ RTS Engine v0.4.0 — Real 3D Isometric")
 	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetVsyncEnabled(true)
 
-	game := NewGame()
-	if err := ebiten.RunGame(game); err != nil {
+	app := NewApp()
+	if err := ebiten.RunGame(app); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"math"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/input"
+	"github.com/1siamBot/rts-engine/engine/render"
+	"github.com/1siamBot/rts-engine/engine/ui"
+)
+
+func TestPlacementOrigin(t *testing.T) {
+	tests := []struct {
+		name         string
+		wx, wy       float64
+		sizeX, sizeY int
+		tx, ty       int
+	}{
+		{"1x1 under cursor", 5.3, 7.9, 1, 1, 5, 7},
+		{"2x2 centred on tile corner", 10.0, 10.0, 2, 2, 9, 9},
+		{"2x2 snaps to nearest corner", 10.4, 9.6, 2, 2, 9, 9},
+		{"3x3 centred on tile", 10.5, 10.5, 3, 3, 9, 9},
+		{"3x2 mixed", 4.2, 4.2, 3, 2, 3, 3},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, ty := placementOrigin(tc.wx, tc.wy, tc.sizeX, tc.sizeY)
+			if tx != tc.tx || ty != tc.ty {
+				t.Errorf("placementOrigin(%v, %v, %d, %d) = (%d, %d), want (%d, %d)",
+					tc.wx, tc.wy, tc.sizeX, tc.sizeY, tx, ty, tc.tx, tc.ty)
+			}
+		})
+	}
+}
+
+func TestNewGameRenderer(t *testing.T) {
+	defer func(v bool) { use3D = v }(use3D)
+	tests := []struct {
+		name string
+		is3D bool
+	}{
+		{"3d", true},
+		{"2d", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			use3D = tc.is3D
+			g := NewGame(ui.NewMenuSystem(1280, 720, nil), input.DefaultKeyBindings())
+			if (g.r3d != nil) != tc.is3D {
+				t.Fatalf("3D extras present = %v, want %v", g.r3d != nil, tc.is3D)
+			}
+			if _, iso := g.renderer.(*render.IsoRenderer); iso == tc.is3D {
+				t.Fatalf("renderer is %T", g.renderer)
+			}
+
+			// the match is driven through the shared interface whichever renderer runs it
+			g.Resize(1920, 1080)
+			g.renderer.View().CenterOn(30, 30)
+			wx, wy := g.renderer.ScreenToWorld(960, 540)
+			if math.Abs(wx-30) > 0.1 || math.Abs(wy-30) > 0.1 {
+				t.Errorf("screen centre picks (%.2f, %.2f), want (30, 30)", wx, wy)
+			}
+			if dx, dy := g.renderer.View().EdgeDir(1919, 540); dx != 1 || dy != 0 {
+				t.Errorf("EdgeDir at the right edge = (%d, %d), want (1, 0)", dx, dy)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/systems"
+)
+
+// relocation is a guided base move: the Construction Yard packs up, the MCV
+// it becomes is selected, and it redeploys at the next valid tile clicked
+type relocation struct {
+	yard    core.EntityID // yard still packing up, or 0
+	yardX   float64       // where the yard stood, to find its MCV
+	yardY   float64
+	mcv     core.EntityID
+	picking bool // waiting for a destination click
+	tx, ty  int  // footprint corner hovered or chosen
+	valid   bool // the hovered footprint can take a yard
+}
+
+// startRelocate packs up the selected Construction Yard, or sends a selected
+// MCV straight to picking a destination
+func (g *Game) startRelocate() {
+	w := g.gameLoop.World
+	for _, id := range g.hud.SelectedIDs {
+		if w.Has(id, core.CompMCV) && !w.Has(id, core.CompDeploying) {
+			g.relocate = &relocation{mcv: id, picking: true}
+			return
+		}
+		if systems.StartUndeploy(w, id) {
+			pos := w.Get(id, core.CompPosition).(*core.Position)
+			g.relocate = &relocation{yard: id, yardX: pos.X, yardY: pos.Y}
+			g.hud.ShowMessage("Packing up Construction Yard", 2.0)
+			return
+		}
+	}
+}
+
+// updateRelocate advances a base move, dropping it if the yard or MCV is lost
+func (g *Game) updateRelocate(wx, wy float64) {
+	r := g.relocate
+	if r == nil {
+		return
+	}
+	w := g.gameLoop.World
+	if r.yard != 0 {
+		if w.Alive(r.yard) {
+			return
+		}
+		r.yard = 0
+		r.mcv = g.mcvAt(r.yardX, r.yardY)
+		if r.mcv == 0 {
+			g.relocate = nil
+			g.hud.ShowMessage("Construction Yard lost", 2.0)
+			return
+		}
+		g.hud.SelectedIDs = []core.EntityID{r.mcv}
+		r.picking = true
+	}
+	if !w.Alive(r.mcv) {
+		g.relocate = nil
+		g.hud.ShowMessage("MCV lost", 2.0)
+		return
+	}
+	// Deploying by hand ends the guided move
+	if w.Has(r.mcv, core.CompDeploying) {
+		g.relocate = nil
+		return
+	}
+	if r.picking {
+		r.tx = int(math.Floor(wx - float64(systems.ConYardSize)/2 + 0.5))
+		r.ty = int(math.Floor(wy - float64(systems.ConYardSize)/2 + 0.5))
+		r.valid = g.canDeployAt(r.tx, r.ty, r.mcv)
+		return
+	}
+	mov := w.Get(r.mcv, core.CompMovable).(*core.Movable)
+	if mov.Unreachable {
+		r.picking = true
+		g.hud.ShowMessage("Can't reach that spot", 2.0)
+		return
+	}
+	if mov.PathIdx < len(mov.Path) {
+		return
+	}
+	pos := w.Get(r.mcv, core.CompPosition).(*core.Position)
+	if int(pos.X) != r.tx || int(pos.Y) != r.ty {
+		// Ordered somewhere else on the way
+		g.relocate = nil
+		return
+	}
+	if !g.canDeployAt(r.tx, r.ty, r.mcv) {
+		r.picking = true
+		g.hud.ShowMessage("Can't deploy here", 2.0)
+		return
+	}
+	systems.StartDeploy(w, g.tileMap, r.mcv)
+	g.relocate = nil
+}
+
+// pickRelocateTarget sends the MCV to the hovered footprint if it's valid
+func (g *Game) pickRelocateTarget() {
+	r := g.relocate
+	if !r.valid {
+		g.hud.ShowMessage("Can't deploy here", 2.0)
+		return
+	}
+	r.picking = false
+	systems.OrderMove(g.gameLoop.World, g.navGrid, r.mcv, r.tx, r.ty)
+}
+
+// mcvAt returns the local player's MCV standing at (x, y), or 0
+func (g *Game) mcvAt(x, y float64) core.EntityID {
+	w := g.gameLoop.World
+	for _, id := range w.Query(core.CompMCV, core.CompPosition, core.CompOwner) {
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		if w.Get(id, core.CompOwner).(*core.Owner).PlayerID == 0 && pos.X == x && pos.Y == y {
+			return id
+		}
+	}
+	return 0
+}
+
+// canDeployAt reports whether a yard fits with its corner at (tx, ty), using
+// the placement footprint checks. The MCV itself doesn't block the spot.
+func (g *Game) canDeployAt(tx, ty int, mcv core.EntityID) bool {
+	return systems.FootprintClear(g.gameLoop.World, g.tileMap, tx, ty, systems.ConYardSize, systems.ConYardSize, mcv)
+}
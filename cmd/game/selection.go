@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/render"
+)
+
+// MinPickRadius is the smallest click target in screen pixels, however far
+// the camera is zoomed out
+const MinPickRadius = 10.0
+
+// Click priorities for overlapping entities
+const (
+	pickBuilding = iota
+	pickUnit
+	pickCombatUnit
+)
+
+// pickRank orders overlapping entities for a click: combat units first,
+// then other units, then buildings
+func pickRank(w *core.World, id core.EntityID) int {
+	switch {
+	case isCombatUnit(w, id):
+		return pickCombatUnit
+	case w.Has(id, core.CompMovable):
+		return pickUnit
+	}
+	return pickBuilding
+}
+
+// isCombatUnit reports whether an entity is a mobile unit that can fight
+func isCombatUnit(w *core.World, id core.EntityID) bool {
+	return w.Has(id, core.CompMovable) && w.Has(id, core.CompWeapon)
+}
+
+// pickCenter returns the world point and radius an entity is clicked by.
+// Buildings are positioned by their corner, so they use the footprint.
+func pickCenter(w *core.World, id core.EntityID) (x, y, r float64) {
+	pos := w.Get(id, core.CompPosition).(*core.Position)
+	r = w.Get(id, core.CompSelectable).(*core.Selectable).Radius
+	if b, ok := core.GetComponent[*core.Building](w, id); ok {
+		return pos.X + float64(b.SizeX)/2, pos.Y + float64(b.SizeY)/2, math.Max(r, float64(max(b.SizeX, b.SizeY))/2)
+	}
+	return pos.X, pos.Y, r
+}
+
+// pickAt returns the local player's entity under a screen point, or 0.
+// Each entity's radius is projected to the screen, so the target grows and
+// shrinks with the zoom.
+func pickAt(w *core.World, cam render.View, mx, my int) core.EntityID {
+	var best core.EntityID
+	bestRank, bestDist := -1, math.Inf(1)
+	for _, id := range w.Query(core.CompPosition, core.CompSelectable, core.CompOwner) {
+		if w.Get(id, core.CompOwner).(*core.Owner).PlayerID != 0 {
+			continue
+		}
+		cx, cy, r := pickCenter(w, id)
+		sx, sy := cam.WorldToScreen(cx, cy)
+		ex, ey := cam.WorldToScreen(cx+r, cy)
+		rpx := math.Max(math.Hypot(float64(ex-sx), float64(ey-sy)), MinPickRadius)
+		d := math.Hypot(float64(mx-sx), float64(my-sy)) / rpx
+		if d > 1 {
+			continue
+		}
+		if rank := pickRank(w, id); rank > bestRank || (rank == bestRank && d < bestDist) {
+			best, bestRank, bestDist = id, rank, d
+		}
+	}
+	return best
+}
+
+// boxSelect returns the local player's entities whose centres project into
+// the screen rectangle (x1, y1)-(x2, y2), narrowed by boxFilter
+func boxSelect(w *core.World, cam render.View, x1, y1, x2, y2 int, all bool) []core.EntityID {
+	var boxed []core.EntityID
+	for _, id := range w.Query(core.CompPosition, core.CompSelectable, core.CompOwner) {
+		if w.Get(id, core.CompOwner).(*core.Owner).PlayerID != 0 {
+			continue
+		}
+		cx, cy, _ := pickCenter(w, id)
+		sx, sy := cam.WorldToScreen(cx, cy)
+		if sx >= x1 && sx <= x2 && sy >= y1 && sy <= y2 {
+			boxed = append(boxed, id)
+		}
+	}
+	return boxFilter(w, boxed, all)
+}
+
+// boxFilter narrows a box selection to combat units when it caught any, so
+// dragging over an army doesn't also grab harvesters and buildings. With
+// all set, or no combat units in the box, everything is kept.
+func boxFilter(w *core.World, ids []core.EntityID, all bool) []core.EntityID {
+	if all {
+		return ids
+	}
+	var combat []core.EntityID
+	for _, id := range ids {
+		if isCombatUnit(w, id) {
+			combat = append(combat, id)
+		}
+	}
+	if len(combat) == 0 {
+		return ids
+	}
+	return combat
+}
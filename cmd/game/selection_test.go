@@ -0,0 +1,149 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/render3d"
+)
+
+// pickScene builds entities for click tests; every entity is player 0's
+// unless noted
+type pickScene struct {
+	w *core.World
+}
+
+func newPickScene() *pickScene { return &pickScene{w: core.NewWorld(20)} }
+
+func (s *pickScene) unit(x, y float64, armed bool, owner int) core.EntityID {
+	id := s.w.Spawn()
+	s.w.Attach(id, &core.Position{X: x, Y: y})
+	s.w.Attach(id, &core.Selectable{Radius: 0.5})
+	s.w.Attach(id, &core.Owner{PlayerID: owner})
+	s.w.Attach(id, &core.Movable{Speed: 1})
+	if armed {
+		s.w.Attach(id, &core.Weapon{Damage: 10})
+	}
+	return id
+}
+
+func (s *pickScene) building(x, y float64, size int) core.EntityID {
+	id := s.w.Spawn()
+	s.w.Attach(id, &core.Position{X: x, Y: y})
+	s.w.Attach(id, &core.Selectable{Radius: 1})
+	s.w.Attach(id, &core.Owner{PlayerID: 0})
+	s.w.Attach(id, &core.Building{SizeX: size, SizeY: size})
+	return id
+}
+
+func pickCamera(zoom float64) *render3d.Camera3D {
+	cam := render3d.NewCamera3D(1280, 720)
+	cam.Zoom = zoom
+	cam.CenterOn(10, 10)
+	return cam
+}
+
+func TestPickAt(t *testing.T) {
+	tests := []struct {
+		name  string
+		zoom  float64
+		setup func(s *pickScene) (want core.EntityID)
+		// click point in world tiles, offset in screen pixels
+		cx, cy float64
+		dx     int
+	}{
+		{"unit over building", render3d.ZoomDefault, func(s *pickScene) core.EntityID {
+			s.building(9, 9, 3)
+			return s.unit(10.5, 10.5, true, 0)
+		}, 10.5, 10.5, 0},
+		{"tank over harvester", render3d.ZoomDefault, func(s *pickScene) core.EntityID {
+			s.unit(10.3, 10.5, false, 0)
+			return s.unit(10.7, 10.5, true, 0)
+		}, 10.3, 10.5, 0},
+		{"nearest of two tanks", render3d.ZoomDefault, func(s *pickScene) core.EntityID {
+			s.unit(10.0, 10.5, true, 0)
+			return s.unit(10.8, 10.5, true, 0)
+		}, 10.7, 10.5, 0},
+		{"building alone", render3d.ZoomDefault, func(s *pickScene) core.EntityID {
+			return s.building(9, 9, 3)
+		}, 9.2, 9.2, 0},
+		{"enemy unit ignored", render3d.ZoomDefault, func(s *pickScene) core.EntityID {
+			s.unit(10.5, 10.5, true, 1)
+			return 0
+		}, 10.5, 10.5, 0},
+		{"just off a unit, zoomed in", render3d.ZoomMin, func(s *pickScene) core.EntityID {
+			return s.unit(10.5, 10.5, true, 0)
+		}, 10.5, 10.5, 30},
+		{"same offset, zoomed out", render3d.ZoomMax, func(s *pickScene) core.EntityID {
+			s.unit(10.5, 10.5, true, 0)
+			return 0
+		}, 10.5, 10.5, 30},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newPickScene()
+			want := tc.setup(s)
+			cam := pickCamera(tc.zoom)
+			mx, my := cam.WorldToScreen(tc.cx, tc.cy)
+			if got := pickAt(s.w, cam, mx+tc.dx, my); got != want {
+				t.Errorf("pickAt = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestBoxSelect(t *testing.T) {
+	tests := []struct {
+		name  string
+		all   bool
+		setup func(s *pickScene) (want []core.EntityID)
+	}{
+		{"tanks only", false, func(s *pickScene) []core.EntityID {
+			t1 := s.unit(9.5, 9.5, true, 0)
+			t2 := s.unit(10.5, 10.5, true, 0)
+			s.unit(11.5, 9.5, false, 0) // harvester
+			s.building(9, 11, 2)
+			return []core.EntityID{t1, t2}
+		}},
+		{"everything with the modifier", true, func(s *pickScene) []core.EntityID {
+			t1 := s.unit(9.5, 9.5, true, 0)
+			h := s.unit(11.5, 9.5, false, 0)
+			b := s.building(9, 11, 2)
+			return []core.EntityID{t1, h, b}
+		}},
+		{"no combat units keeps the rest", false, func(s *pickScene) []core.EntityID {
+			h := s.unit(11.5, 9.5, false, 0)
+			b := s.building(9, 11, 2)
+			return []core.EntityID{h, b}
+		}},
+		{"never enemies", true, func(s *pickScene) []core.EntityID {
+			t1 := s.unit(9.5, 9.5, true, 0)
+			s.unit(10.5, 10.5, true, 1)
+			return []core.EntityID{t1}
+		}},
+		{"outside the box", false, func(s *pickScene) []core.EntityID {
+			s.unit(30.5, 30.5, true, 0)
+			return nil
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newPickScene()
+			want := tc.setup(s)
+			cam := pickCamera(render3d.ZoomDefault)
+			// A box around tiles (8, 8)-(13, 13), whatever the projection
+			xs, ys := []int{}, []int{}
+			for _, c := range [][2]float64{{8, 8}, {13, 8}, {8, 13}, {13, 13}} {
+				x, y := cam.WorldToScreen(c[0], c[1])
+				xs, ys = append(xs, x), append(ys, y)
+			}
+			got := boxSelect(s.w, cam, slices.Min(xs), slices.Min(ys), slices.Max(xs), slices.Max(ys), tc.all)
+			slices.Sort(got)
+			slices.Sort(want)
+			if !slices.Equal(got, want) {
+				t.Errorf("boxSelect = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/1siamBot/rts-engine/engine/ai"
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+	"github.com/1siamBot/rts-engine/engine/systems"
+	"github.com/1siamBot/rts-engine/engine/ui"
+)
+
+// Sim is the simulation half of a match: map, world, players and systems.
+// It owns no window, renderer or HUD, so it can also run headless.
+type Sim struct {
+	tileMap  *maplib.TileMap
+	gameLoop *core.GameLoop
+	players  *core.PlayerManager
+	eventBus *core.EventBus
+	navGrid  *pathfind.NavGrid
+	techTree *systems.TechTree
+	fogSys   *systems.FogSystem
+	envSys   *systems.EnvironmentSystem
+	stats    *systems.StatsSystem
+}
+
+// newSim sets up the map, players and systems for a skirmish. With autoplay
+// the local player's slot is handed to an AI too.
+func newSim(sk ui.SkirmishSettings, autoplay bool) *Sim {
+	s := &Sim{
+		tileMap:  buildMap(),
+		gameLoop: core.NewGameLoop(TickRate),
+		players:  core.NewPlayerManager(),
+		eventBus: core.NewEventBus(),
+		techTree: systems.NewTechTree(),
+	}
+	s.gameLoop.Events = s.eventBus
+
+	// Players
+	s.players.AddPlayer(&core.Player{
+		ID: 0, Name: "Player 1", TeamID: 0, Faction: sk.FactionName(),
+		Color: 0x0066FFFF, Credits: sk.Credits(), IsAI: autoplay,
+	})
+	s.players.AddPlayer(&core.Player{
+		ID: 1, Name: "AI Enemy", TeamID: 1, Faction: "Soviet",
+		Color: 0xFF0000FF, Credits: 10000, IsAI: true,
+	})
+
+	s.navGrid = pathfind.NewNavGrid(s.tileMap)
+	s.navGrid.Friendly = s.players.AreAllies
+
+	s.fogSys = systems.NewFogSystem(s.tileMap.Width, s.tileMap.Height, s.players)
+	s.fogSys.TileMap = s.tileMap
+	s.envSys = systems.NewEnvironmentSystem(s.tileMap, s.fogSys)
+	s.fogSys.Environment = s.envSys
+
+	// Register systems
+	w := s.gameLoop.World
+	w.AddSystem(s.envSys)
+	w.AddSystem(&systems.PowerSystem{Players: s.players, EventBus: s.eventBus})
+	w.AddSystem(&systems.StorageSystem{Players: s.players})
+	w.AddSystem(&systems.BuildingConstructionSystem{Players: s.players, EventBus: s.eventBus})
+	w.AddSystem(&systems.DeploySystem{TileMap: s.tileMap, EventBus: s.eventBus})
+	w.AddSystem(&systems.DeliverySystem{TileMap: s.tileMap, EventBus: s.eventBus})
+	w.AddSystem(&systems.ChronoSystem{TileMap: s.tileMap, EventBus: s.eventBus})
+	w.AddSystem(&systems.GateSystem{NavGrid: s.navGrid, Players: s.players})
+	w.AddSystem(s.fogSys)
+	w.AddSystem(&systems.MovementSystem{NavGrid: s.navGrid, TileMap: s.tileMap})
+	w.AddSystem(&systems.CombatSystem{EventBus: s.eventBus, Players: s.players, TileMap: s.tileMap})
+	w.AddSystem(&systems.ProjectileSystem{EventBus: s.eventBus, TileMap: s.tileMap})
+	w.AddSystem(&systems.MindControlSystem{})
+	w.AddSystem(&systems.HarvesterSystem{NavGrid: s.navGrid, TileMap: s.tileMap, Players: s.players, EventBus: s.eventBus})
+	w.AddSystem(&systems.ProductionSystem{TechTree: s.techTree, Players: s.players, EventBus: s.eventBus, NavGrid: s.navGrid})
+	w.AddSystem(systems.NewForestSystem(s.tileMap, s.navGrid, time.Now().UnixNano()))
+	w.AddSystem(&systems.BridgeSystem{TileMap: s.tileMap, NavGrid: s.navGrid, EventBus: s.eventBus})
+	w.AddSystem(&systems.RegenSystem{})
+	w.AddSystem(&systems.RepairDepotSystem{TechTree: s.techTree, Players: s.players})
+	w.AddSystem(systems.NewCrateSystem(s.tileMap, s.techTree, s.players, s.eventBus, s.fogSys, time.Now().UnixNano()))
+	w.AddSystem(&systems.AnimationSystem{})
+	w.AddSystem(&systems.GameOverSystem{Players: s.players})
+	s.stats = systems.NewStatsSystem(s.eventBus, s.players)
+	w.AddSystem(s.stats)
+	if len(s.tileMap.Triggers) > 0 {
+		ts := systems.NewTriggerSystem(s.tileMap.Triggers, s.techTree, s.players, s.eventBus, s.fogSys)
+		ts.TileMap = s.tileMap
+		w.AddSystem(ts)
+	}
+
+	aiSys := &ai.AISystem{Players: s.players}
+	for _, p := range s.players.Players {
+		if p.IsAI {
+			c := ai.NewAIController(p.ID, ai.Difficulty(sk.AIDifficulty), s.techTree, s.navGrid, s.tileMap)
+			c.EventBus = s.eventBus
+			aiSys.Controllers = append(aiSys.Controllers, c)
+		}
+	}
+	w.AddSystem(aiSys)
+
+	s.spawnInitialEntities()
+	s.spawnMapEntities()
+
+	// Mark initial building tiles as occupied
+	s.markInitialBuildingTiles()
+	return s
+}
+
+// Step advances the simulation one fixed tick and delivers its events
+func (s *Sim) Step() {
+	s.gameLoop.Step()
+}
+
+// winner returns the last undefeated player, or nil while the match is open
+func (s *Sim) winner() *core.Player {
+	var last *core.Player
+	for _, p := range s.players.Players {
+		if p.Defeated {
+			continue
+		}
+		if last != nil {
+			return nil
+		}
+		last = p
+	}
+	return last
+}
+
+// matchStats lists every player's tally for the game over screen
+func (s *Sim) matchStats() []ui.PlayerResult {
+	var rows []ui.PlayerResult
+	for _, p := range s.players.Players {
+		rows = append(rows, ui.PlayerResult{Name: p.Name, Stats: *s.stats.For(p.ID)})
+	}
+	return rows
+}
+
+// saveStats writes the match stats when -stats was given
+func (s *Sim) saveStats() {
+	if statsPath == "" {
+		return
+	}
+	if err := s.stats.Save(statsPath); err != nil {
+		log.Printf("Stats not saved: %v", err)
+	}
+}
+
+func (s *Sim) spawnInitialEntities() {
+	w := s.gameLoop.World
+
+	// ---- Player 0: MCV only (authentic RA2 start) ----
+	px, py := s.startPos(0, 10, 10)
+	mcvID := w.Spawn()
+	w.Attach(mcvID, &core.Position{X: float64(px), Y: float64(py)})
+	w.Attach(mcvID, &core.Health{Current: 1000, Max: 1000})
+	w.Attach(mcvID, &core.Movable{Speed: 0.8, MoveType: core.MoveVehicle})
+	w.Attach(mcvID, &core.Sprite{Width: 32, Height: 32, Visible: true, ScaleX: 1, ScaleY: 1})
+	w.Attach(mcvID, &core.Selectable{Radius: 0.8})
+	w.Attach(mcvID, &core.Owner{PlayerID: 0, Faction: s.players.GetPlayer(0).Faction})
+	w.Attach(mcvID, &core.FogVision{Range: 6})
+	w.Attach(mcvID, &core.MCV{CanDeploy: true})
+	w.Attach(mcvID, &core.Armor{ArmorType: core.ArmorHeavy})
+	w.Attach(mcvID, &core.UnitType{Key: "mcv"})
+
+	// ---- AI Player 1: MCV that auto-deploys immediately ----
+	ax, ay := s.startPos(1, 54, 54)
+	aiMcvID := w.Spawn()
+	w.Attach(aiMcvID, &core.Position{X: float64(ax), Y: float64(ay)})
+	w.Attach(aiMcvID, &core.Health{Current: 1000, Max: 1000})
+	w.Attach(aiMcvID, &core.Movable{Speed: 0.8, MoveType: core.MoveVehicle})
+	w.Attach(aiMcvID, &core.Sprite{Width: 32, Height: 32, Visible: true, ScaleX: 1, ScaleY: 1})
+	w.Attach(aiMcvID, &core.Selectable{Radius: 0.8})
+	w.Attach(aiMcvID, &core.Owner{PlayerID: 1, Faction: "Soviet"})
+	w.Attach(aiMcvID, &core.FogVision{Range: 6})
+	w.Attach(aiMcvID, &core.MCV{CanDeploy: true})
+	w.Attach(aiMcvID, &core.Armor{ArmorType: core.ArmorHeavy})
+	w.Attach(aiMcvID, &core.UnitType{Key: "mcv"})
+
+	// Auto-deploy AI MCV into Construction Yard immediately
+	systems.DeployMCV(w, aiMcvID, s.eventBus)
+}
+
+// spawnMapEntities instantiates units, buildings and props placed in the map editor
+func (s *Sim) spawnMapEntities() {
+	w := s.gameLoop.World
+	for _, ent := range s.tileMap.Entities {
+		playerID := core.NeutralPlayerID
+		faction := ""
+		if ent.Owner != maplib.NeutralOwner {
+			playerID = ent.Owner
+			if p := s.players.GetPlayer(playerID); p != nil {
+				faction = p.Faction
+			}
+		}
+		switch ent.Kind {
+		case maplib.EntityUnit:
+			if udef, ok := s.techTree.Units[ent.Key]; ok {
+				systems.SpawnUnit(w, ent.Key, udef, playerID, faction, float64(ent.X)+0.5, float64(ent.Y)+0.5)
+			}
+		case maplib.EntityBuilding:
+			id := systems.PlaceBuilding(w, ent.Key, s.techTree, playerID, ent.X, ent.Y, faction, nil)
+			if id == 0 {
+				continue
+			}
+			// Pre-placed buildings start finished
+			hp := w.Get(id, core.CompHealth).(*core.Health)
+			hp.Current = hp.Max
+			bc := w.Get(id, core.CompBuildingConstruction).(*core.BuildingConstruction)
+			bc.Progress = 1
+			bc.Complete = true
+			if ent.Key == "construction_yard" {
+				w.Get(id, core.CompBuilding).(*core.Building).IsConYard = true
+				w.Attach(id, &core.Production{Rate: 1.0, Rally: core.TilePos{X: ent.X + 3, Y: ent.Y + 3}})
+			}
+		case maplib.EntityProp:
+			id := w.Spawn()
+			w.Attach(id, &core.Position{X: float64(ent.X), Y: float64(ent.Y)})
+			w.Attach(id, &core.Health{Current: 300, Max: 300})
+			w.Attach(id, &core.Building{SizeX: 1, SizeY: 1})
+			w.Attach(id, &core.Owner{PlayerID: playerID, Faction: faction})
+			w.Attach(id, &core.BuildingName{Key: ent.Key})
+		case maplib.EntityCrate:
+			systems.SpawnCrate(w, ent.X, ent.Y, systems.ParseCrateBonus(ent.Key))
+		}
+	}
+}
+
+// startPos returns the map's start position for a player slot, or the fallback
+func (s *Sim) startPos(slot, defX, defY int) (int, int) {
+	for _, sp := range s.tileMap.StartPositions {
+		if sp.PlayerSlot == slot {
+			return sp.X, sp.Y
+		}
+	}
+	return defX, defY
+}
+
+func (s *Sim) markInitialBuildingTiles() {
+	w := s.gameLoop.World
+	for _, id := range w.Query(core.CompBuilding, core.CompPosition) {
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		bldg := w.Get(id, core.CompBuilding).(*core.Building)
+		if bldg.Pad {
+			continue
+		}
+		systems.OccupyTiles(s.tileMap, int(pos.X), int(pos.Y), bldg.SizeX, bldg.SizeY)
+	}
+}
+
+// runHeadless plays two AIs against each other for up to maxTicks without a
+// window or renderer, then prints the outcome and how long it took
+func runHeadless(maxTicks int) {
+	s := newSim(ui.DefaultSkirmishSettings(), true)
+
+	start := time.Now()
+	ticks := 0
+	for ticks < maxTicks && s.winner() == nil {
+		s.Step()
+		ticks++
+	}
+	wall := time.Since(start)
+
+	if p := s.winner(); p != nil {
+		fmt.Printf("Winner:    %s (player %d)\n", p.Name, p.ID)
+	} else {
+		fmt.Printf("Winner:    none after %d ticks\n", maxTicks)
+	}
+	fmt.Printf("Ticks:     %d (%.1fs game time)\n", ticks, float64(ticks)/TickRate)
+	fmt.Printf("Wall time: %v (%.3f ms/tick)\n", wall.Round(time.Millisecond), float64(wall.Microseconds())/1000/float64(max(ticks, 1)))
+	for _, r := range s.matchStats() {
+		fmt.Printf("%-10s score %d, units %d built/%d lost/%d killed, buildings %d built/%d lost\n",
+			r.Name, r.Stats.Score(), r.Stats.UnitsBuilt, r.Stats.UnitsLost, r.Stats.UnitsKilled, r.Stats.BuildingsBuilt, r.Stats.BuildingsLost)
+	}
+	s.saveStats()
+}
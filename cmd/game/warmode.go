@@ -0,0 +1,54 @@
+package main
+
+import (
+	"image/color"
+
+	"github.com/1siamBot/rts-engine/engine/audio"
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// setAttackPoint makes a tile the local player's war mode staging point
+func (g *Game) setAttackPoint(tx, ty int) {
+	p := g.players.GetPlayer(0)
+	if p == nil || !g.tileMap.InBounds(tx, ty) {
+		return
+	}
+	p.AttackPoint = core.TilePos{X: tx, Y: ty}
+	g.hud.ShowMessage("Attack point set", 1.5)
+	g.audioMgr.PlaySFX(audio.SndClick, float64(tx), float64(ty))
+}
+
+// toggleWarMode switches war mode for the local player. Turning it on
+// before an attack point was chosen stages at the tile under the cursor.
+func (g *Game) toggleWarMode() {
+	p := g.players.GetPlayer(0)
+	if p == nil {
+		return
+	}
+	if !p.WarMode && p.AttackPoint == (core.TilePos{}) {
+		g.setAttackPoint(g.hoverTileX, g.hoverTileY)
+	}
+	p.WarMode = !p.WarMode
+	if p.WarMode {
+		g.hud.ShowMessage("War mode on", 1.5)
+	} else {
+		g.hud.ShowMessage("War mode off", 1.5)
+	}
+}
+
+// drawAttackPoint marks the staging point while war mode is on
+func (g *Game) drawAttackPoint(screen *ebiten.Image) {
+	p := g.players.GetPlayer(0)
+	if p == nil || !p.WarMode {
+		return
+	}
+	fx, fy := float64(p.AttackPoint.X)+0.5, float64(p.AttackPoint.Y)+0.5
+	bx, by := g.renderer.Project(fx, fy, 0.05)
+	tx, ty := g.renderer.Project(fx, fy, 1.2)
+	red := color.RGBA{230, 50, 40, 255}
+	vector.StrokeLine(screen, float32(bx), float32(by), float32(tx), float32(ty), 2, red, false)
+	vector.DrawFilledRect(screen, float32(tx), float32(ty), 12, 8, red, false)
+	vector.StrokeCircle(screen, float32(bx), float32(by), 6, 1.5, red, false)
+}
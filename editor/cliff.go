@@ -0,0 +1,184 @@
+package editor
+
+import (
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// PaintCliff raises (or lowers) the brush area to CliffLevel and rebuilds
+// the cliff faces around it. Every plateau touched by the stroke gets at
+// least one ramp so it stays reachable by ground units.
+func (e *Editor) PaintCliff(cx, cy int) {
+	tm := e.TileMap
+	before := make([]maplib.Tile, len(tm.Tiles))
+	copy(before, tm.Tiles)
+
+	r := e.BrushSize / 2
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			x, y := cx+dx, cy+dy
+			t := tm.At(x, y)
+			if t == nil {
+				continue
+			}
+			tm.SetHeight(x, y, e.CliffLevel)
+			if isCliffTerrain(t.Terrain) {
+				tm.SetTerrain(x, y, x, y, maplib.TerrainGrass)
+			}
+		}
+	}
+	e.RebuildCliffs(cx-r-1, cy-r-1, cx+r+1, cy+r+1)
+	e.pushDiff(before)
+}
+
+// RebuildCliffs recomputes cliff and ramp tiles inside a rectangle.
+// A tile becomes a cliff face when any neighbour sits lower; stale
+// cliff/ramp tiles that no longer border a step revert to grass.
+func (e *Editor) RebuildCliffs(x1, y1, x2, y2 int) {
+	tm := e.TileMap
+	var edges [][2]int
+	for y := y1; y <= y2; y++ {
+		for x := x1; x <= x2; x++ {
+			t := tm.At(x, y)
+			if t == nil {
+				continue
+			}
+			if isStepEdge(tm, x, y) {
+				if t.Terrain != maplib.TerrainRamp {
+					tm.SetTerrain(x, y, x, y, maplib.TerrainCliff)
+				}
+				edges = append(edges, [2]int{x, y})
+			} else if isCliffTerrain(t.Terrain) {
+				tm.SetTerrain(x, y, x, y, maplib.TerrainGrass)
+			}
+		}
+	}
+
+	// Make sure every plateau bordering the rectangle has a ramp
+	seen := make(map[int]bool)
+	for _, p := range edges {
+		if seen[p[1]*tm.Width+p[0]] {
+			continue
+		}
+		boundary := plateauBoundary(tm, p[0], p[1], seen)
+		ensureRamp(tm, boundary)
+	}
+}
+
+// pushDiff records every tile changed since the snapshot as one undo step
+func (e *Editor) pushDiff(before []maplib.Tile) {
+	var actions []Action
+	for i, old := range before {
+		if old != e.TileMap.Tiles[i] {
+			actions = append(actions, Action{
+				X: i % e.TileMap.Width, Y: i / e.TileMap.Width,
+				OldTile: old, NewTile: e.TileMap.Tiles[i],
+			})
+		}
+	}
+	if len(actions) > 0 {
+		e.UndoStack = append(e.UndoStack, actions)
+		e.RedoStack = nil
+		e.Modified = true
+	}
+}
+
+func isCliffTerrain(t maplib.TerrainType) bool {
+	return t == maplib.TerrainCliff || t == maplib.TerrainRamp
+}
+
+// isStepEdge reports whether (x, y) is higher than any of its 8 neighbours
+func isStepEdge(tm *maplib.TileMap, x, y int) bool {
+	h := tm.HeightAt(x, y)
+	for dy := -1; dy <= 1; dy++ {
+		for dx := -1; dx <= 1; dx++ {
+			if (dx != 0 || dy != 0) && tm.InBounds(x+dx, y+dy) && tm.HeightAt(x+dx, y+dy) < h {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// plateauBoundary flood-fills the same-height region containing (x, y)
+// and returns its step-edge tiles, marking them in seen.
+func plateauBoundary(tm *maplib.TileMap, x, y int, seen map[int]bool) [][2]int {
+	h := tm.HeightAt(x, y)
+	visited := map[int]bool{y*tm.Width + x: true}
+	stack := [][2]int{{x, y}}
+	var boundary [][2]int
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if isStepEdge(tm, p[0], p[1]) {
+			boundary = append(boundary, p)
+			seen[p[1]*tm.Width+p[0]] = true
+		}
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, ny := p[0]+d[0], p[1]+d[1]
+			idx := ny*tm.Width + nx
+			if !tm.InBounds(nx, ny) || visited[idx] || tm.HeightAt(nx, ny) != h {
+				continue
+			}
+			visited[idx] = true
+			stack = append(stack, [2]int{nx, ny})
+		}
+	}
+	return boundary
+}
+
+// ensureRamp turns one boundary tile into a ramp unless one already exists.
+// Candidates must have lower ground on one side and the plateau on the
+// opposite side; the one closest to the map centre wins. A plateau too
+// narrow for that, such as a single raised tile, gets its ramp on any
+// boundary tile with open lower ground beside it instead.
+func ensureRamp(tm *maplib.TileMap, boundary [][2]int) {
+	best, bestDist, bestFull := -1, 0, false
+	for i, p := range boundary {
+		if tm.At(p[0], p[1]).Terrain == maplib.TerrainRamp {
+			return
+		}
+		full := isRampCandidate(tm, p[0], p[1])
+		if !full && !hasLowGround(tm, p[0], p[1]) {
+			continue
+		}
+		dx, dy := p[0]-tm.Width/2, p[1]-tm.Height/2
+		d := dx*dx + dy*dy
+		if best < 0 || (full && !bestFull) || (full == bestFull && d < bestDist) {
+			best, bestDist, bestFull = i, d, full
+		}
+	}
+	if best >= 0 {
+		p := boundary[best]
+		tm.SetTerrain(p[0], p[1], p[0], p[1], maplib.TerrainRamp)
+	}
+}
+
+func isRampCandidate(tm *maplib.TileMap, x, y int) bool {
+	h := tm.HeightAt(x, y)
+	for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		lo := tm.At(x+d[0], y+d[1])
+		hi := tm.At(x-d[0], y-d[1])
+		if lo == nil || hi == nil || isCliffTerrain(lo.Terrain) || isCliffTerrain(hi.Terrain) {
+			continue
+		}
+		if tm.HeightAt(x+d[0], y+d[1]) < h && tm.HeightAt(x-d[0], y-d[1]) == h &&
+			lo.Passable&maplib.PassVehicle != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// hasLowGround reports whether a vehicle could drive up to (x, y) from a
+// lower orthogonal neighbour
+func hasLowGround(tm *maplib.TileMap, x, y int) bool {
+	h := tm.HeightAt(x, y)
+	for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		lo := tm.At(x+d[0], y+d[1])
+		if lo != nil && !isCliffTerrain(lo.Terrain) && tm.HeightAt(x+d[0], y+d[1]) < h &&
+			lo.Passable&maplib.PassVehicle != 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package editor
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestPaintCliff(t *testing.T) {
+	type stroke struct{ x, y, brush, level int }
+	tests := []struct {
+		name       string
+		strokes    []stroke
+		wantCliffs int
+		wantRamps  int
+	}{
+		{"1-tile brush", []stroke{{8, 8, 1, 1}}, 0, 1},
+		{"3-tile brush", []stroke{{8, 8, 3, 1}}, 7, 1},
+		{"5-tile brush", []stroke{{8, 8, 5, 1}}, 15, 1},
+		{"strokes merge into one plateau", []stroke{{6, 8, 3, 1}, {9, 8, 3, 1}}, 13, 1},
+		{"separate plateaus", []stroke{{3, 3, 3, 1}, {12, 12, 3, 1}}, 14, 2},
+		{"separate 1-tile plateaus", []stroke{{3, 3, 1, 1}, {12, 12, 1, 1}}, 0, 2},
+		{"plateau at the map corner", []stroke{{0, 0, 3, 1}}, 2, 1},
+		{"lowering removes the faces", []stroke{{8, 8, 3, 1}, {8, 8, 3, 0}}, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEditor(16, 16)
+			for _, s := range tc.strokes {
+				e.BrushSize, e.CliffLevel = s.brush, s.level
+				e.PaintCliff(s.x, s.y)
+			}
+			tm := e.TileMap
+
+			cliffs, ramps := 0, 0
+			for y := 0; y < tm.Height; y++ {
+				for x := 0; x < tm.Width; x++ {
+					terrain := tm.At(x, y).Terrain
+					if edge := isStepEdge(tm, x, y); edge != isCliffTerrain(terrain) {
+						t.Errorf("tile (%d, %d): step edge %v but terrain %v", x, y, edge, terrain)
+					}
+					switch terrain {
+					case maplib.TerrainCliff:
+						cliffs++
+					case maplib.TerrainRamp:
+						ramps++
+						if !hasLowGround(tm, x, y) {
+							t.Errorf("ramp at (%d, %d) has no open ground below it", x, y)
+						}
+					}
+				}
+			}
+			if cliffs != tc.wantCliffs || ramps != tc.wantRamps {
+				t.Errorf("%d cliffs and %d ramps, want %d and %d", cliffs, ramps, tc.wantCliffs, tc.wantRamps)
+			}
+		})
+	}
+}
+
+func TestRampLeadsOntoPlateau(t *testing.T) {
+	// from the 3-tile brush up, the ramp sits between low ground and the
+	// plateau's top rather than on a corner
+	for _, brush := range []int{3, 5, 7} {
+		e := NewEditor(16, 16)
+		e.BrushSize = brush
+		e.PaintCliff(8, 8)
+		tm := e.TileMap
+		found := false
+		for y := 0; y < tm.Height; y++ {
+			for x := 0; x < tm.Width; x++ {
+				if tm.At(x, y).Terrain != maplib.TerrainRamp {
+					continue
+				}
+				found = true
+				if !isRampCandidate(tm, x, y) {
+					t.Errorf("brush %d: ramp at (%d, %d) doesn't lead onto the plateau", brush, x, y)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("brush %d: no ramp", brush)
+		}
+	}
+}
+
+func TestPaintCliffUndo(t *testing.T) {
+	e := NewEditor(16, 16)
+	before := append([]maplib.Tile(nil), e.TileMap.Tiles...)
+	e.BrushSize = 3
+	e.PaintCliff(8, 8)
+	after := append([]maplib.Tile(nil), e.TileMap.Tiles...)
+	if len(e.UndoStack) != 1 {
+		t.Fatalf("%d undo steps, want 1 per stroke", len(e.UndoStack))
+	}
+
+	e.Undo()
+	for i := range before {
+		if e.TileMap.Tiles[i] != before[i] {
+			t.Fatalf("tile %d not restored by Undo", i)
+		}
+	}
+	e.Redo()
+	for i := range after {
+		if e.TileMap.Tiles[i] != after[i] {
+			t.Fatalf("tile %d not restored by Redo", i)
+		}
+	}
+}
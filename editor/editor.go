@@ -9,6 +9,11 @@ type Action struct {
 	X, Y     int
 	OldTile  maplib.Tile
 	NewTile  maplib.Tile
+
+	// Entity layer edits snapshot the whole spawn list
+	Entities    bool
+	OldEntities []maplib.EntitySpawn
+	NewEntities []maplib.EntitySpawn
 }
 
 // Editor holds map editor state
@@ -23,6 +28,15 @@ type Editor struct {
 	Modified     bool
 	ShowGrid     bool
 	OreAmount    int
+	CliffLevel   int // target height for ToolCliff
+	Selection    Rect
+	HasSelection bool
+	Clipboard    *Clipboard
+
+	// Entity placement (ToolEntity)
+	EntityKind  maplib.EntityKind
+	EntityKey   string
+	EntityOwner int
 }
 
 // EditorTool represents the current editor tool
@@ -34,16 +48,24 @@ const (
 	ToolOre
 	ToolStartPos
 	ToolHeight
+	ToolCliff
+	ToolSelect
+	ToolFill
+	ToolLine
+	ToolEntity
 )
 
 // NewEditor creates a new map editor
 func NewEditor(width, height int) *Editor {
 	return &Editor{
-		TileMap:   maplib.NewTileMap("Untitled", width, height),
-		Brush:     maplib.TerrainGrass,
-		BrushSize: 1,
-		ShowGrid:  true,
-		OreAmount: 1000,
+		TileMap:    maplib.NewTileMap("Untitled", width, height),
+		Brush:      maplib.TerrainGrass,
+		BrushSize:  1,
+		ShowGrid:   true,
+		OreAmount:  1000,
+		CliffLevel: 1,
+		EntityKind: maplib.EntityUnit,
+		EntityKey:  "gi",
 	}
 }
 
@@ -76,6 +98,14 @@ func (e *Editor) SaveMap(path string) error {
 
 // Paint applies the current brush at (cx, cy) with brush size
 func (e *Editor) Paint(cx, cy int) {
+	switch e.Tool {
+	case ToolCliff:
+		e.PaintCliff(cx, cy)
+		return
+	case ToolEntity:
+		e.PlaceEntity(cx, cy)
+		return
+	}
 	var actions []Action
 	r := e.BrushSize / 2
 	for dy := -r; dy <= r; dy++ {
@@ -95,10 +125,11 @@ func (e *Editor) Paint(cx, cy int) {
 				e.TileMap.SetTerrain(x, y, x, y, maplib.TerrainGrass)
 				t.OreAmount = 0
 			case ToolHeight:
-				t.Height++
-				if t.Height > 7 {
-					t.Height = 0
+				h := int(t.Height) + 1
+				if h > maplib.MaxHeightLevel {
+					h = 0
 				}
+				e.TileMap.SetHeight(x, y, h)
 			}
 			newTile := *e.TileMap.At(x, y)
 			actions = append(actions, Action{X: x, Y: y, OldTile: old, NewTile: newTile})
@@ -133,6 +164,10 @@ func (e *Editor) Undo() {
 	actions := e.UndoStack[len(e.UndoStack)-1]
 	e.UndoStack = e.UndoStack[:len(e.UndoStack)-1]
 	for _, a := range actions {
+		if a.Entities {
+			e.TileMap.Entities = append([]maplib.EntitySpawn(nil), a.OldEntities...)
+			continue
+		}
 		t := e.TileMap.At(a.X, a.Y)
 		if t != nil {
 			*t = a.OldTile
@@ -150,6 +185,10 @@ func (e *Editor) Redo() {
 	actions := e.RedoStack[len(e.RedoStack)-1]
 	e.RedoStack = e.RedoStack[:len(e.RedoStack)-1]
 	for _, a := range actions {
+		if a.Entities {
+			e.TileMap.Entities = append([]maplib.EntitySpawn(nil), a.NewEntities...)
+			continue
+		}
 		t := e.TileMap.At(a.X, a.Y)
 		if t != nil {
 			*t = a.NewTile
@@ -0,0 +1,48 @@
+package editor
+
+import (
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// PlaceEntity puts a spawn marker of the current entity type at (x, y),
+// replacing any marker already on that tile.
+func (e *Editor) PlaceEntity(x, y int) {
+	if !e.TileMap.InBounds(x, y) || e.EntityKey == "" {
+		return
+	}
+	spawn := maplib.EntitySpawn{Kind: e.EntityKind, Key: e.EntityKey, Owner: e.EntityOwner, X: x, Y: y}
+	if i := e.TileMap.EntityAt(x, y); i >= 0 && e.TileMap.Entities[i] == spawn {
+		return
+	}
+	e.editEntities(func(list []maplib.EntitySpawn) []maplib.EntitySpawn {
+		if i := e.TileMap.EntityAt(x, y); i >= 0 {
+			list[i] = spawn
+			return list
+		}
+		return append(list, spawn)
+	})
+}
+
+// RemoveEntity deletes the spawn marker at (x, y), if any
+func (e *Editor) RemoveEntity(x, y int) {
+	i := e.TileMap.EntityAt(x, y)
+	if i < 0 {
+		return
+	}
+	e.editEntities(func(list []maplib.EntitySpawn) []maplib.EntitySpawn {
+		return append(list[:i], list[i+1:]...)
+	})
+}
+
+// editEntities applies fn to a copy of the entity list and records an undo step
+func (e *Editor) editEntities(fn func([]maplib.EntitySpawn) []maplib.EntitySpawn) {
+	old := append([]maplib.EntitySpawn(nil), e.TileMap.Entities...)
+	e.TileMap.Entities = fn(append([]maplib.EntitySpawn(nil), old...))
+	e.UndoStack = append(e.UndoStack, []Action{{
+		Entities:    true,
+		OldEntities: old,
+		NewEntities: append([]maplib.EntitySpawn(nil), e.TileMap.Entities...),
+	}})
+	e.RedoStack = nil
+	e.Modified = true
+}
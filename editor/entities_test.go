@@ -0,0 +1,110 @@
+package editor
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestEntityUndoRedo(t *testing.T) {
+	place := func(kind maplib.EntityKind, key string, owner, x, y int) func(*Editor) {
+		return func(e *Editor) {
+			e.EntityKind, e.EntityKey, e.EntityOwner = kind, key, owner
+			e.PlaceEntity(x, y)
+		}
+	}
+	remove := func(x, y int) func(*Editor) {
+		return func(e *Editor) { e.RemoveEntity(x, y) }
+	}
+	paint := func(x, y int) func(*Editor) {
+		return func(e *Editor) {
+			e.Tool, e.Brush = ToolPaint, maplib.TerrainSand
+			e.Paint(x, y)
+		}
+	}
+	gi := maplib.EntitySpawn{Kind: maplib.EntityUnit, Key: "gi", Owner: 0, X: 2, Y: 2}
+	grizzly := maplib.EntitySpawn{Kind: maplib.EntityUnit, Key: "grizzly", Owner: 0, X: 2, Y: 2}
+	rhino := maplib.EntitySpawn{Kind: maplib.EntityUnit, Key: "rhino", Owner: 1, X: 5, Y: 5}
+
+	tests := []struct {
+		name      string
+		ops       []func(*Editor)
+		wantSteps int // undo steps recorded
+		want      []maplib.EntitySpawn
+	}{
+		{"place", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2)}, 1, []maplib.EntitySpawn{gi}},
+		{"place two", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), place(rhino.Kind, rhino.Key, 1, 5, 5)}, 2,
+			[]maplib.EntitySpawn{gi, rhino}},
+		{"replace on the same tile", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), place(grizzly.Kind, grizzly.Key, 0, 2, 2)}, 2,
+			[]maplib.EntitySpawn{grizzly}},
+		{"placing the same marker again is not a step", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), place(gi.Kind, gi.Key, 0, 2, 2)}, 1,
+			[]maplib.EntitySpawn{gi}},
+		{"off the map is not a step", []func(*Editor){place(gi.Kind, gi.Key, 0, -1, 2)}, 0, nil},
+		{"remove", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), place(rhino.Kind, rhino.Key, 1, 5, 5), remove(2, 2)}, 3,
+			[]maplib.EntitySpawn{rhino}},
+		{"removing nothing is not a step", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), remove(4, 4)}, 1,
+			[]maplib.EntitySpawn{gi}},
+		{"mixed with terrain edits", []func(*Editor){place(gi.Kind, gi.Key, 0, 2, 2), paint(8, 8), remove(2, 2), paint(9, 9)}, 4, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEditor(16, 16)
+			type state struct {
+				entities []maplib.EntitySpawn
+				tiles    []maplib.Tile
+			}
+			snap := func() state {
+				return state{slices.Clone(e.TileMap.Entities), slices.Clone(e.TileMap.Tiles)}
+			}
+			equal := func(a, b state) bool {
+				return slices.Equal(a.entities, b.entities) && slices.Equal(a.tiles, b.tiles)
+			}
+			history := []state{snap()}
+			for _, op := range tc.ops {
+				op(e)
+				if s := snap(); !equal(s, history[len(history)-1]) {
+					history = append(history, s)
+				}
+			}
+			if len(e.UndoStack) != tc.wantSteps {
+				t.Fatalf("%d undo steps, want %d", len(e.UndoStack), tc.wantSteps)
+			}
+			if !slices.Equal(e.TileMap.Entities, tc.want) {
+				t.Errorf("entities = %v, want %v", e.TileMap.Entities, tc.want)
+			}
+
+			// each undo steps back exactly one edit
+			for i := len(history) - 2; i >= 0; i-- {
+				e.Undo()
+				if !equal(snap(), history[i]) {
+					t.Fatalf("after undoing to step %d: entities %v, want %v", i, e.TileMap.Entities, history[i].entities)
+				}
+			}
+			// and redo replays them in order
+			for i := 1; i < len(history); i++ {
+				e.Redo()
+				if !equal(snap(), history[i]) {
+					t.Fatalf("after redoing to step %d: entities %v, want %v", i, e.TileMap.Entities, history[i].entities)
+				}
+			}
+			if len(e.RedoStack) != 0 {
+				t.Errorf("%d redo steps left", len(e.RedoStack))
+			}
+		})
+	}
+}
+
+func TestEntityEditClearsRedo(t *testing.T) {
+	e := NewEditor(16, 16)
+	e.PlaceEntity(2, 2)
+	e.Undo()
+	e.PlaceEntity(3, 3)
+	if len(e.RedoStack) != 0 {
+		t.Errorf("%d redo steps after a new edit, want 0", len(e.RedoStack))
+	}
+	e.Redo()
+	if len(e.TileMap.Entities) != 1 || e.TileMap.Entities[0].X != 3 {
+		t.Errorf("entities = %v, want the marker at (3, 3) only", e.TileMap.Entities)
+	}
+}
@@ -0,0 +1,189 @@
+package editor
+
+import (
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// Rect is an inclusive rectangle of tiles
+type Rect struct {
+	X1, Y1, X2, Y2 int
+}
+
+// Normalize returns the rect with X1<=X2 and Y1<=Y2
+func (r Rect) Normalize() Rect {
+	if r.X1 > r.X2 {
+		r.X1, r.X2 = r.X2, r.X1
+	}
+	if r.Y1 > r.Y2 {
+		r.Y1, r.Y2 = r.Y2, r.Y1
+	}
+	return r
+}
+
+// Contains reports whether (x, y) lies inside the rect
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X1 && x <= r.X2 && y >= r.Y1 && y <= r.Y2
+}
+
+// Clipboard holds a copied block of tiles
+type Clipboard struct {
+	Width, Height int
+	Tiles         []maplib.Tile
+}
+
+// PreviewTile is a tile that an operation would write, for drawing previews
+type PreviewTile struct {
+	X, Y int
+	Tile maplib.Tile
+}
+
+// SetSelection sets the selection rectangle, clipped to the map
+func (e *Editor) SetSelection(x1, y1, x2, y2 int) {
+	r := Rect{x1, y1, x2, y2}.Normalize()
+	r.X1 = max(r.X1, 0)
+	r.Y1 = max(r.Y1, 0)
+	r.X2 = min(r.X2, e.TileMap.Width-1)
+	r.Y2 = min(r.Y2, e.TileMap.Height-1)
+	if r.X1 > r.X2 || r.Y1 > r.Y2 {
+		e.ClearSelection()
+		return
+	}
+	e.Selection = r
+	e.HasSelection = true
+}
+
+// ClearSelection removes the selection rectangle
+func (e *Editor) ClearSelection() {
+	e.HasSelection = false
+}
+
+// Copy copies the selected tiles (terrain, height, ore, passability) to the clipboard
+func (e *Editor) Copy() bool {
+	if !e.HasSelection {
+		return false
+	}
+	r := e.Selection
+	cb := &Clipboard{Width: r.X2 - r.X1 + 1, Height: r.Y2 - r.Y1 + 1}
+	for y := r.Y1; y <= r.Y2; y++ {
+		for x := r.X1; x <= r.X2; x++ {
+			t := *e.TileMap.At(x, y)
+			t.Occupied = false
+			cb.Tiles = append(cb.Tiles, t)
+		}
+	}
+	e.Clipboard = cb
+	return true
+}
+
+// PastePreview returns the tiles a paste at (x, y) would write, clipped to the map
+func (e *Editor) PastePreview(x, y int) []PreviewTile {
+	cb := e.Clipboard
+	if cb == nil {
+		return nil
+	}
+	var out []PreviewTile
+	for dy := 0; dy < cb.Height; dy++ {
+		for dx := 0; dx < cb.Width; dx++ {
+			if e.TileMap.InBounds(x+dx, y+dy) {
+				out = append(out, PreviewTile{X: x + dx, Y: y + dy, Tile: cb.Tiles[dy*cb.Width+dx]})
+			}
+		}
+	}
+	return out
+}
+
+// Paste writes the clipboard with its top-left corner at (x, y) as one undo step
+func (e *Editor) Paste(x, y int) {
+	e.apply(e.PastePreview(x, y))
+}
+
+// Fill flood-fills the contiguous region of matching terrain at (x, y)
+// with the current brush, staying inside the selection if there is one.
+func (e *Editor) Fill(x, y int) {
+	e.apply(e.FillPreview(x, y))
+}
+
+// FillPreview returns the tiles Fill(x, y) would change
+func (e *Editor) FillPreview(x, y int) []PreviewTile {
+	tm := e.TileMap
+	start := tm.At(x, y)
+	if start == nil || start.Terrain == e.Brush {
+		return nil
+	}
+	if e.HasSelection && !e.Selection.Contains(x, y) {
+		return nil
+	}
+	match := start.Terrain
+	visited := map[int]bool{y*tm.Width + x: true}
+	stack := [][2]int{{x, y}}
+	var out []PreviewTile
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		out = append(out, PreviewTile{X: p[0], Y: p[1], Tile: e.brushTile(p[0], p[1])})
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			nx, ny := p[0]+d[0], p[1]+d[1]
+			t := tm.At(nx, ny)
+			if t == nil || visited[ny*tm.Width+nx] || t.Terrain != match {
+				continue
+			}
+			if e.HasSelection && !e.Selection.Contains(nx, ny) {
+				continue
+			}
+			visited[ny*tm.Width+nx] = true
+			stack = append(stack, [2]int{nx, ny})
+		}
+	}
+	return out
+}
+
+// Line paints a straight line of the current brush from (x0, y0) to (x1, y1)
+func (e *Editor) Line(x0, y0, x1, y1 int) {
+	e.apply(e.LinePreview(x0, y0, x1, y1))
+}
+
+// LinePreview returns the tiles Line would change, honouring BrushSize
+func (e *Editor) LinePreview(x0, y0, x1, y1 int) []PreviewTile {
+	seen := make(map[[2]int]bool)
+	var out []PreviewTile
+	r := e.BrushSize / 2
+	maplib.WalkLine(x0, y0, x1, y1, func(x, y int) bool {
+		for by := -r; by <= r; by++ {
+			for bx := -r; bx <= r; bx++ {
+				p := [2]int{x + bx, y + by}
+				if seen[p] || !e.TileMap.InBounds(p[0], p[1]) {
+					continue
+				}
+				seen[p] = true
+				out = append(out, PreviewTile{X: p[0], Y: p[1], Tile: e.brushTile(p[0], p[1])})
+			}
+		}
+		return true
+	})
+	return out
+}
+
+// brushTile returns what the tile at (x, y) looks like after painting the brush
+func (e *Editor) brushTile(x, y int) maplib.Tile {
+	tmp := maplib.TileMap{Width: 1, Height: 1, Tiles: []maplib.Tile{*e.TileMap.At(x, y)}}
+	tmp.SetTerrain(0, 0, 0, 0, e.Brush)
+	return tmp.Tiles[0]
+}
+
+// apply writes a set of tiles to the map as a single undo step
+func (e *Editor) apply(tiles []PreviewTile) {
+	var actions []Action
+	for _, pt := range tiles {
+		t := e.TileMap.At(pt.X, pt.Y)
+		if t == nil || *t == pt.Tile {
+			continue
+		}
+		actions = append(actions, Action{X: pt.X, Y: pt.Y, OldTile: *t, NewTile: pt.Tile})
+		*t = pt.Tile
+	}
+	if len(actions) > 0 {
+		e.UndoStack = append(e.UndoStack, actions)
+		e.RedoStack = nil
+		e.Modified = true
+	}
+}
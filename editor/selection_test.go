@@ -0,0 +1,38 @@
+package editor
+
+import "testing"
+
+func TestLinePreview(t *testing.T) {
+	tests := []struct {
+		name           string
+		brush          int
+		x0, y0, x1, y1 int
+		want           int
+	}{
+		{"thin horizontal", 1, 2, 2, 6, 2, 5},
+		{"thin diagonal", 1, 0, 0, 3, 3, 4},
+		{"wide brush dedups overlap", 3, 5, 5, 8, 5, 3 * 6},
+		{"clipped at the map edge", 3, 0, 0, 2, 0, 2 * 4},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := NewEditor(16, 16)
+			e.BrushSize = tc.brush
+			tiles := e.LinePreview(tc.x0, tc.y0, tc.x1, tc.y1)
+			if len(tiles) != tc.want {
+				t.Errorf("LinePreview gave %d tiles, want %d", len(tiles), tc.want)
+			}
+			seen := map[[2]int]bool{}
+			for _, pt := range tiles {
+				p := [2]int{pt.X, pt.Y}
+				if seen[p] {
+					t.Errorf("tile %v listed twice", p)
+				}
+				seen[p] = true
+			}
+			if !seen[[2]int{tc.x0, tc.y0}] || !seen[[2]int{tc.x1, tc.y1}] {
+				t.Error("line is missing an endpoint")
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"math/rand"
 
 	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
 	"github.com/1siamBot/rts-engine/engine/pathfind"
 	"github.com/1siamBot/rts-engine/engine/systems"
 )
@@ -24,7 +25,8 @@ type AIController struct {
 	Difficulty Difficulty
 	TechTree   *systems.TechTree
 	NavGrid    *pathfind.NavGrid
-	TileMap    systems.TileMapOccupy
+	TileMap    *maplib.TileMap
+	EventBus   *core.EventBus // optional; receives building placements
 
 	tickTimer     float64
 	thinkInterval float64
@@ -33,7 +35,7 @@ type AIController struct {
 	buildOffset   int // offset for next building placement
 }
 
-func NewAIController(playerID int, diff Difficulty, tt *systems.TechTree, ng *pathfind.NavGrid, tm systems.TileMapOccupy) *AIController {
+func NewAIController(playerID int, diff Difficulty, tt *systems.TechTree, ng *pathfind.NavGrid, tm *maplib.TileMap) *AIController {
 	interval := 5.0
 	switch diff {
 	case DiffEasy:
@@ -136,7 +138,7 @@ func (ai *AIController) Think(w *core.World, pm *core.PlayerManager) {
 		}
 		if udef, ok := ai.TechTree.Units[unitType]; ok {
 			if player.Credits >= udef.Cost && ai.TechTree.HasPrereqs(w, ai.PlayerID, udef.Prereqs) {
-				player.Credits -= udef.Cost
+				player.Spend(udef.Cost)
 				prod.Queue = append(prod.Queue, unitType)
 			}
 		}
@@ -162,8 +164,7 @@ func (ai *AIController) Think(w *core.World, pm *core.PlayerManager) {
 func (ai *AIController) autoDeployMCV(w *core.World) {
 	for _, id := range w.Query(core.CompMCV, core.CompOwner) {
 		own := w.Get(id, core.CompOwner).(*core.Owner)
-		if own.PlayerID == ai.PlayerID {
-			systems.DeployMCV(w, id, nil)
+		if own.PlayerID == ai.PlayerID && systems.StartDeploy(w, ai.TileMap, id) {
 			return
 		}
 	}
@@ -225,10 +226,10 @@ func (ai *AIController) aiBuildBuilding(w *core.World, player *core.Player, key
 	for _, off := range offsets {
 		tx := int(cyX) + off[0]
 		ty := int(cyY) + off[1]
-		if ai.canAIPlace(w, tx, ty, bdef.SizeX, bdef.SizeY) {
-			player.Credits -= bdef.Cost
-			bid := systems.PlaceBuilding(w, key, ai.TechTree, ai.PlayerID, tx, ty, player.Faction, nil)
-			if bid != 0 && ai.TileMap != nil {
+		if ai.canAIPlace(w, tx, ty, bdef.SizeX, bdef.SizeY, player.Faction) {
+			player.Spend(bdef.Cost)
+			bid := systems.PlaceBuilding(w, key, ai.TechTree, ai.PlayerID, tx, ty, player.Faction, ai.EventBus)
+			if bid != 0 && ai.TileMap != nil && !bdef.Pad {
 				systems.OccupyTiles(ai.TileMap, tx, ty, bdef.SizeX, bdef.SizeY)
 			}
 			return
@@ -236,24 +237,13 @@ func (ai *AIController) aiBuildBuilding(w *core.World, player *core.Player, key
 	}
 }
 
-// canAIPlace checks if the AI can place a building at the given position
-func (ai *AIController) canAIPlace(w *core.World, tileX, tileY, sizeX, sizeY int) bool {
-	// Check near existing buildings
-	nearBuilding := false
-	for _, bid := range w.Query(core.CompBuilding, core.CompOwner, core.CompPosition) {
-		o := w.Get(bid, core.CompOwner).(*core.Owner)
-		if o.PlayerID != ai.PlayerID {
-			continue
-		}
-		bp := w.Get(bid, core.CompPosition).(*core.Position)
-		dx := float64(tileX) - bp.X
-		dy := float64(tileY) - bp.Y
-		if dx*dx+dy*dy < 100 {
-			nearBuilding = true
-			break
-		}
+// canAIPlace checks if the AI can place a building at the given position:
+// inside its build radius, with the whole footprint clear when the map is known
+func (ai *AIController) canAIPlace(w *core.World, tileX, tileY, sizeX, sizeY int, faction string) bool {
+	if ai.TileMap != nil && !systems.FootprintClear(w, ai.TileMap, tileX, tileY, sizeX, sizeY, 0) {
+		return false
 	}
-	return nearBuilding
+	return systems.InBuildRadius(w, ai.TechTree, ai.PlayerID, faction, tileX, tileY)
 }
 
 func (ai *AIController) countBuildings(w *core.World) int {
@@ -0,0 +1,52 @@
+package ai
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/systems"
+)
+
+func TestCanAIPlace(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(w *core.World, tm *maplib.TileMap)
+		x, y  int
+		noMap bool
+		want  bool
+	}{
+		{"open ground", func(*core.World, *maplib.TileMap) {}, 14, 10, false, true},
+		{"one tile taken", func(_ *core.World, tm *maplib.TileMap) { tm.SetOccupied(15, 11, true) }, 14, 10, false, false},
+		{"water under a corner", func(_ *core.World, tm *maplib.TileMap) {
+			tm.SetTerrain(15, 11, 15, 11, maplib.TerrainWater)
+		}, 14, 10, false, false},
+		{"unit in the way", func(w *core.World, _ *maplib.TileMap) {
+			id := w.Spawn()
+			w.Attach(id, &core.Position{X: 15.5, Y: 11.5})
+			w.Attach(id, &core.Movable{MoveType: core.MoveVehicle})
+		}, 14, 10, false, false},
+		{"outside build radius", func(*core.World, *maplib.TileMap) {}, 40, 40, false, false},
+		{"no map known", func(_ *core.World, tm *maplib.TileMap) { tm.SetOccupied(15, 11, true) }, 14, 10, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 64, 64)
+			tt := systems.NewTechTree()
+			if systems.PlaceBuilding(w, "construction_yard", tt, 1, 10, 10, "Soviet", nil) == 0 {
+				t.Fatal("PlaceBuilding failed")
+			}
+			systems.OccupyTiles(tm, 10, 10, 3, 3)
+			tc.setup(w, tm)
+			if tc.noMap {
+				tm = nil
+			}
+			ai := NewAIController(1, DiffMedium, tt, nil, tm)
+
+			if got := ai.canAIPlace(w, tc.x, tc.y, 2, 2, "Soviet"); got != tc.want {
+				t.Errorf("canAIPlace(%d, %d) = %v, want %v", tc.x, tc.y, got, tc.want)
+			}
+		})
+	}
+}
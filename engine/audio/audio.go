@@ -16,6 +16,29 @@ const (
 	SndClick     SoundID = "click"
 )
 
+// Cue identifies an announcer line
+type Cue string
+
+const (
+	CueConstructionComplete Cue = "construction_complete"
+	CueUnitReady            Cue = "unit_ready"
+	CueLowPower             Cue = "low_power"
+	CueBaseUnderAttack      Cue = "base_under_attack"
+	CueInsufficientFunds    Cue = "insufficient_funds"
+)
+
+// CueCooldown is the fewest seconds between two plays of the same cue;
+// cues not listed use DefaultCueCooldown
+var CueCooldown = map[Cue]float64{
+	CueUnitReady:         2,
+	CueLowPower:          15,
+	CueBaseUnderAttack:   20,
+	CueInsufficientFunds: 4,
+}
+
+// DefaultCueCooldown applies to cues missing from CueCooldown
+const DefaultCueCooldown = 3.0
+
 // AudioManager handles music and sound effects
 // Uses Ebitengine's audio package internally
 type AudioManager struct {
@@ -25,6 +48,9 @@ type AudioManager struct {
 	MusicPlaying bool
 	CameraX      float64
 	CameraY      float64
+
+	clock    float64         // seconds of Update, for cue cooldowns
+	cuePlays map[Cue]float64 // when each cue last played
 }
 
 func NewAudioManager() *AudioManager {
@@ -49,6 +75,30 @@ func (am *AudioManager) PlaySFX(id SoundID, worldX, worldY float64) {
 	// For now this is a stub that integrates into the architecture
 }
 
+// Update advances the clock announcer cooldowns run on
+func (am *AudioManager) Update(dt float64) {
+	am.clock += dt
+}
+
+// PlayAnnouncer plays a global, non-positional announcer cue. A cue still
+// cooling down from its last play is dropped rather than stacked; the
+// result reports whether it played.
+func (am *AudioManager) PlayAnnouncer(cue Cue) bool {
+	if am.cuePlays == nil {
+		am.cuePlays = make(map[Cue]float64)
+	}
+	cooldown, ok := CueCooldown[cue]
+	if !ok {
+		cooldown = DefaultCueCooldown
+	}
+	if last, played := am.cuePlays[cue]; played && am.clock-last < cooldown {
+		return false
+	}
+	am.cuePlays[cue] = am.clock
+	// Stub like PlaySFX: would play the voice line via ebiten/audio
+	return true
+}
+
 // PlayMusic starts background music
 func (am *AudioManager) PlayMusic(_ string) {
 	am.MusicPlaying = true
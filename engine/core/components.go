@@ -84,10 +84,20 @@ type Weapon struct {
 	Splash      float64 // AoE radius (0 = single target)
 	DamageType  DamageType
 	TargetType  TargetMask // what can this weapon target
+	Stance      Stance
+	Engaged     bool // had a target in range at the last shot check
 }
 
 func (w *Weapon) Type() ComponentType { return CompWeapon }
 
+// Stance controls whether a unit picks its own targets
+type Stance uint8
+
+const (
+	StanceAggressive Stance = iota // fire at any enemy in range
+	StanceHold                     // hold fire until given a new order
+)
+
 type DamageType uint8
 
 const (
@@ -96,6 +106,7 @@ const (
 	DmgFire
 	DmgElectric
 	DmgRadiation
+	DmgPsionic // takes control of the target instead of damaging it
 )
 
 type TargetMask uint8
@@ -135,6 +146,12 @@ type Movable struct {
 	Path     []TilePos // current path
 	PathIdx  int       // current position in path
 	MoveType MoveType
+
+	Stuck       float64 // seconds without getting closer to the waypoint
+	Closest     float64 // nearest approach to the waypoint so far
+	Retries     int     // unstick attempts on the current order
+	Unreachable bool    // the last order was given up on
+	AttackMove  bool    // halt to fight enemies met along the way
 }
 
 func (m *Movable) Type() ComponentType { return CompMovable }
@@ -184,6 +201,7 @@ type Production struct {
 	Progress float64  // 0.0 to 1.0
 	Rate     float64  // production speed multiplier
 	Rally    TilePos  // rally point
+	Primary  bool     // new units of its kind come out here first
 }
 
 func (p *Production) Type() ComponentType { return CompProduction }
@@ -201,6 +219,9 @@ type Building struct {
 	Prereqs      []string // required buildings
 	IsConYard    bool     // is this a Construction Yard?
 	Sellable     bool     // can be sold for 50% refund
+	PoweredDown  bool     // manually switched off: draws no power, stays offline
+	Pad          bool     // vehicles drive onto it; footprint tiles stay open
+	Storage      int      // ore capacity it adds for its owner
 }
 
 func (b *Building) Type() ComponentType { return CompBuilding }
@@ -214,6 +235,153 @@ type MCV struct {
 
 func (m *MCV) Type() ComponentType { return CompMCV }
 
+// Deploying marks an MCV unpacking into a Construction Yard, or a yard packing
+// back up (Undeploy). The entity can't move or produce until Timer reaches Duration.
+type Deploying struct {
+	Timer    float64
+	Duration float64 // seconds
+	Undeploy bool
+}
+
+func (d *Deploying) Type() ComponentType { return CompDeploying }
+
+// Progress returns how far along the (un)deploy is, 0-1
+func (d *Deploying) Progress() float64 {
+	if d.Duration <= 0 {
+		return 1
+	}
+	return math.Min(d.Timer/d.Duration, 1)
+}
+
+// ---- Transport ----
+
+// Transport carries passengers. Loaded units keep their components but lose
+// their Position, so they drop out of every spatial query until unloaded.
+type Transport struct {
+	Capacity   int
+	Passengers []EntityID
+}
+
+func (t *Transport) Type() ComponentType { return CompTransport }
+
+// Delivery flies a carrier in from the map edge at Entry to Drop along a
+// curve bent through Control, unloads its passengers, then flies back out
+type Delivery struct {
+	EntryX, EntryY     float64
+	ControlX, ControlY float64
+	DropX, DropY       float64
+	Speed              float64 // tiles per second
+	T                  float64 // progress along the current leg, 0-1
+	Unloaded           bool    // on the way out
+}
+
+func (d *Delivery) Type() ComponentType { return CompDelivery }
+
+// ---- Regeneration ----
+
+// Regen heals an entity over time. With NearBuilding set it only heals
+// within Radius of a completed building of that key owned by the same player.
+type Regen struct {
+	Rate         float64 // HP per second
+	NearBuilding string
+	Radius       float64
+	Carry        float64 // fractional HP not yet applied
+}
+
+func (r *Regen) Type() ComponentType { return CompRegen }
+
+// ---- Mind Control ----
+
+// MindControl lets a unit take over enemy units with a psionic weapon. A
+// captive returns to its owner when the controller dies or it strays beyond
+// LinkRange.
+type MindControl struct {
+	Capacity  int
+	LinkRange float64
+	Captives  []Captive
+}
+
+// Captive is a controlled unit and the owner it is returned to on release
+type Captive struct {
+	ID       EntityID
+	PlayerID int
+	Faction  string
+}
+
+func (m *MindControl) Type() ComponentType { return CompMindControl }
+
+// ---- Chrono ----
+
+// Chrono lets a unit teleport to a tile after a short warm-up, ignoring paths
+type Chrono struct {
+	WarmUp      float64 // seconds between order and jump
+	Cooldown    float64 // seconds between jumps
+	CooldownNow float64
+	Charging    bool
+	Timer       float64 // warm-up elapsed
+	Target      TilePos
+}
+
+func (c *Chrono) Type() ComponentType { return CompChrono }
+
+// ---- Gate ----
+
+// Gate is a wall section that opens for friendly units and stays shut to enemies
+type Gate struct {
+	Open float64 // 0 shut .. 1 fully open
+	Hold float64 // seconds left before it starts closing
+}
+
+func (g *Gate) Type() ComponentType { return CompGate }
+
+// ---- Siege ----
+
+// WeaponProfile is one set of weapon stats a unit can switch to
+type WeaponProfile struct {
+	Damage     int // 0 = can't fire
+	Range      float64
+	Cooldown   float64
+	Projectile string
+	Splash     float64
+	DamageType DamageType
+}
+
+// Siege is a unit that deploys in place for a heavier weapon. It can't move
+// while deployed and must pack up (a Deploying with Undeploy set) first.
+type Siege struct {
+	Deployed bool
+	Mobile   WeaponProfile
+	Sieged   WeaponProfile
+}
+
+func (s *Siege) Type() ComponentType { return CompSiege }
+
+// Active returns the weapon profile for the current mode
+func (s *Siege) Active() WeaponProfile {
+	if s.Deployed {
+		return s.Sieged
+	}
+	return s.Mobile
+}
+
+// Apply copies the active profile's stats onto a weapon. It runs when the
+// mode changes, so anything else that changes the weapon (veterancy) must
+// change both profiles too.
+func (s *Siege) Apply(wep *Weapon) {
+	p := s.Active()
+	wep.Damage, wep.Range, wep.Cooldown = p.Damage, p.Range, p.Cooldown
+	wep.Projectile, wep.Splash, wep.DamageType = p.Projectile, p.Splash, p.DamageType
+}
+
+// ---- Bridge Repair ----
+
+// BridgeRepair is an engineer's order to rebuild the wrecked span at Target
+type BridgeRepair struct {
+	Target TilePos
+}
+
+func (b *BridgeRepair) Type() ComponentType { return CompBridgeRepair }
+
 // ---- Building Construction Progress ----
 
 // BuildingConstruction tracks construction animation progress
@@ -234,6 +402,15 @@ type BuildingName struct {
 
 func (bn *BuildingName) Type() ComponentType { return CompBuildingName }
 
+// ---- Unit Type Tag ----
+
+// UnitType stores the tech-tree key for a unit
+type UnitType struct {
+	Key string
+}
+
+func (ut *UnitType) Type() ComponentType { return CompUnitType }
+
 // ---- Harvester ----
 
 // Harvester represents a resource-gathering unit
@@ -243,6 +420,8 @@ type Harvester struct {
 	Rate     float64 // harvest speed
 	Resource string  // "ore" or "gem"
 	State    HarvesterState
+	Field    TilePos // ore patch the player sent it to
+	HasField bool    // mine around Field before anywhere else
 }
 
 func (h *Harvester) Type() ComponentType { return CompHarvester }
@@ -255,6 +434,7 @@ const (
 	HarvHarvesting
 	HarvReturning
 	HarvUnloading
+	HarvStopped // halted by a Stop order; waits for a new order
 )
 
 // ---- Projectile ----
@@ -285,3 +465,36 @@ type FogVision struct {
 }
 
 func (f *FogVision) Type() ComponentType { return CompFogVision }
+
+// ---- Crate ----
+
+// CrateBonus is the reward granted by a pickup crate
+type CrateBonus uint8
+
+const (
+	CrateRandom CrateBonus = iota // rolled when collected
+	CrateCredits
+	CrateReveal
+	CrateVeterancy
+	CrateUnit
+)
+
+// Crate marks a neutral pickup collected by moving a unit onto its tile
+type Crate struct {
+	Bonus CrateBonus
+}
+
+func (c *Crate) Type() ComponentType { return CompCrate }
+
+// ---- Veterancy ----
+
+// MaxVeterancyRank is the elite rank
+const MaxVeterancyRank = 2
+
+// Veterancy tracks a unit's promotion rank
+type Veterancy struct {
+	Rank  int // 0 = rookie, 1 = veteran, 2 = elite
+	Kills int
+}
+
+func (v *Veterancy) Type() ComponentType { return CompVeterancy }
@@ -40,6 +40,18 @@ const (
 	CompMCV
 	CompBuildingConstruction
 	CompBuildingName
+	CompCrate
+	CompVeterancy
+	CompUnitType
+	CompDeploying
+	CompTransport
+	CompDelivery
+	CompRegen
+	CompMindControl
+	CompChrono
+	CompGate
+	CompSiege
+	CompBridgeRepair
 	CompMax
 )
 
@@ -58,6 +70,18 @@ type System interface {
 	Priority() int
 }
 
+// DespawnListener is implemented by systems that keep entity IDs between
+// ticks; OnDespawn runs while the entity's components are still readable
+type DespawnListener interface {
+	OnDespawn(w *World, id EntityID)
+}
+
+// DetachListener is implemented by systems that care when a component is
+// removed from a live entity
+type DetachListener interface {
+	OnDetach(w *World, id EntityID, ct ComponentType)
+}
+
 // NewWorld creates a new ECS world
 func NewWorld(tickRate float64) *World {
 	return &World{
@@ -80,11 +104,21 @@ func (w *World) Attach(id EntityID, c Component) {
 	}
 }
 
-// Detach removes a component from an entity
+// Detach removes a component from an entity and tells listening systems
 func (w *World) Detach(id EntityID, ct ComponentType) {
-	if comps, ok := w.entities[id]; ok {
-		delete(comps, ct)
+	comps, ok := w.entities[id]
+	if !ok {
+		return
 	}
+	if _, ok := comps[ct]; !ok {
+		return
+	}
+	for _, s := range w.systems {
+		if l, ok := s.(DetachListener); ok {
+			l.OnDetach(w, id, ct)
+		}
+	}
+	delete(comps, ct)
 }
 
 // Get returns a component for an entity, or nil
@@ -95,6 +129,14 @@ func (w *World) Get(id EntityID, ct ComponentType) Component {
 	return nil
 }
 
+// GetComponent returns an entity's component as T, e.g.
+// GetComponent[*Position](w, id). ok is false when the entity lacks it.
+func GetComponent[T Component](w *World, id EntityID) (T, bool) {
+	var zero T
+	c, ok := w.Get(id, zero.Type()).(T)
+	return c, ok
+}
+
 // Has checks if an entity has a component
 func (w *World) Has(id EntityID, ct ComponentType) bool {
 	if comps, ok := w.entities[id]; ok {
@@ -104,11 +146,31 @@ func (w *World) Has(id EntityID, ct ComponentType) bool {
 	return false
 }
 
-// Destroy marks an entity for removal
+// Destroy marks an entity for removal at the end of the tick
 func (w *World) Destroy(id EntityID) {
 	w.toRemove = append(w.toRemove, id)
 }
 
+// Despawn removes an entity and all its components right away, telling
+// listening systems first. Inside a tick, prefer Destroy.
+func (w *World) Despawn(id EntityID) {
+	if _, ok := w.entities[id]; !ok {
+		return
+	}
+	for _, s := range w.systems {
+		if l, ok := s.(DespawnListener); ok {
+			l.OnDespawn(w, id)
+		}
+	}
+	delete(w.entities, id)
+}
+
+// Alive reports whether an entity still exists
+func (w *World) Alive(id EntityID) bool {
+	_, ok := w.entities[id]
+	return ok
+}
+
 // Query returns all entity IDs that have ALL specified component types
 func (w *World) Query(types ...ComponentType) []EntityID {
 	var result []EntityID
@@ -145,7 +207,7 @@ func (w *World) Tick(dt float64) {
 	}
 	// Clean up destroyed entities
 	for _, id := range w.toRemove {
-		delete(w.entities, id)
+		w.Despawn(id)
 	}
 	w.toRemove = w.toRemove[:0]
 	w.TickCount++
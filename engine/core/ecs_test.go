@@ -0,0 +1,149 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// listener records the despawn and detach notifications it receives
+type listener struct {
+	despawned []EntityID
+	sawPos    []bool // whether the entity's Position was readable in OnDespawn
+	detached  []ComponentType
+}
+
+func (l *listener) Update(*World, float64) {}
+func (l *listener) Priority() int          { return 0 }
+
+func (l *listener) OnDespawn(w *World, id EntityID) {
+	l.despawned = append(l.despawned, id)
+	l.sawPos = append(l.sawPos, w.Has(id, CompPosition))
+}
+
+func (l *listener) OnDetach(_ *World, _ EntityID, ct ComponentType) {
+	l.detached = append(l.detached, ct)
+}
+
+func TestDespawnPurgesEntity(t *testing.T) {
+	tests := []struct {
+		name      string
+		remove    func(w *World, id EntityID)
+		wantAlive bool // before the next tick
+	}{
+		{"Despawn", func(w *World, id EntityID) { w.Despawn(id) }, false},
+		{"Destroy", func(w *World, id EntityID) { w.Destroy(id) }, true},
+		{"Destroy twice", func(w *World, id EntityID) { w.Destroy(id); w.Destroy(id) }, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWorld(20)
+			l := &listener{}
+			w.AddSystem(l)
+			id := w.Spawn()
+			w.Attach(id, &Position{X: 1, Y: 2})
+			w.Attach(id, &Health{Current: 10, Max: 10})
+			other := w.Spawn()
+			w.Attach(other, &Position{})
+
+			tc.remove(w, id)
+			if w.Alive(id) != tc.wantAlive {
+				t.Errorf("alive before tick = %v, want %v", w.Alive(id), tc.wantAlive)
+			}
+			w.Tick(0.05)
+
+			if w.Alive(id) || w.Has(id, CompPosition) || w.Get(id, CompHealth) != nil {
+				t.Error("entity still has components after removal")
+			}
+			if got := w.Query(CompPosition); !slices.Equal(got, []EntityID{other}) {
+				t.Errorf("Query(Position) = %v, want [%d]", got, other)
+			}
+			if !slices.Equal(l.despawned, []EntityID{id}) {
+				t.Errorf("OnDespawn calls = %v, want exactly [%d]", l.despawned, id)
+			}
+			if len(l.sawPos) > 0 && !l.sawPos[0] {
+				t.Error("components were gone before OnDespawn ran")
+			}
+			if w.EntityCount() != 1 {
+				t.Errorf("EntityCount = %d, want 1", w.EntityCount())
+			}
+		})
+	}
+}
+
+func TestDetachNotifies(t *testing.T) {
+	tests := []struct {
+		name   string
+		detach ComponentType
+		want   []ComponentType
+	}{
+		{"attached component", CompHealth, []ComponentType{CompHealth}},
+		{"missing component", CompWeapon, nil},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := NewWorld(20)
+			l := &listener{}
+			w.AddSystem(l)
+			id := w.Spawn()
+			w.Attach(id, &Position{})
+			w.Attach(id, &Health{Current: 10, Max: 10})
+
+			w.Detach(id, tc.detach)
+			if !slices.Equal(l.detached, tc.want) {
+				t.Errorf("OnDetach calls = %v, want %v", l.detached, tc.want)
+			}
+			if w.Has(id, tc.detach) {
+				t.Error("component still attached")
+			}
+			if !w.Alive(id) || !w.Has(id, CompPosition) {
+				t.Error("Detach removed more than the one component")
+			}
+		})
+	}
+}
+
+func TestDespawnUnknownEntity(t *testing.T) {
+	w := NewWorld(20)
+	l := &listener{}
+	w.AddSystem(l)
+	w.Despawn(12345)
+	w.Detach(12345, CompPosition)
+	if len(l.despawned) != 0 || len(l.detached) != 0 {
+		t.Errorf("listeners told about an entity that never existed: %v %v", l.despawned, l.detached)
+	}
+}
+
+func TestGetComponent(t *testing.T) {
+	w := NewWorld(20)
+	id := w.Spawn()
+	w.Attach(id, &Position{X: 3, Y: 4})
+	gone := w.Spawn()
+	w.Despawn(gone)
+
+	tests := []struct {
+		name   string
+		get    func() (any, bool)
+		wantOK bool
+	}{
+		{"present", func() (any, bool) { return GetComponent[*Position](w, id) }, true},
+		{"missing component", func() (any, bool) { return GetComponent[*Health](w, id) }, false},
+		{"despawned entity", func() (any, bool) { return GetComponent[*Position](w, gone) }, false},
+		{"never spawned", func() (any, bool) { return GetComponent[*Position](w, 99999) }, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok := tc.get(); ok != tc.wantOK {
+				t.Errorf("ok = %v, want %v", ok, tc.wantOK)
+			}
+		})
+	}
+
+	pos, _ := GetComponent[*Position](w, id)
+	if pos.X != 3 || pos.Y != 4 {
+		t.Errorf("position = %+v, want {3 4}", *pos)
+	}
+	pos.X = 7 // a pointer to the stored component, not a copy
+	if w.Get(id, CompPosition).(*Position).X != 7 {
+		t.Error("GetComponent returned a copy")
+	}
+}
@@ -10,17 +10,17 @@ type Event struct {
 type EventType uint16
 
 const (
-	EvtUnitCreated EventType = iota
-	EvtUnitDestroyed
-	EvtBuildingPlaced
-	EvtBuildingDestroyed
-	EvtBuildingComplete
+	EvtUnitCreated       EventType = iota // Payload: UnitEvent (produced units only)
+	EvtUnitDestroyed                      // Payload: KillEvent
+	EvtBuildingPlaced                     // Payload: UnitEvent
+	EvtBuildingDestroyed                  // Payload: KillEvent
+	EvtBuildingComplete                   // Payload: UnitEvent
 	EvtUnitAttack
-	EvtUnitDamaged
+	EvtUnitDamaged // Payload: DamageEvent
 	EvtUnitMoveOrder
 	EvtProjectileFired
 	EvtProjectileHit
-	EvtResourceHarvested
+	EvtResourceHarvested // Payload: HarvestEvent
 	EvtResourceSpent
 	EvtTechUnlocked
 	EvtPlayerDefeated
@@ -28,16 +28,93 @@ const (
 	EvtChatMessage
 	EvtGameStart
 	EvtGameEnd
+	EvtTriggerFired   // Payload: trigger name (string)
+	EvtMissionMessage // Payload: message text (string)
+	EvtCrateCollected // Payload: CratePickup
+	EvtChronoWarp     // Payload: ChronoWarp
+	EvtOreWasted      // Payload: OreWasted
+	EvtUnderAttack    // Payload: UnderAttack
+	EvtLowPower       // Payload: player ID (int)
+	EvtNoFunds        // Payload: player ID (int)
 )
 
-// EventBus dispatches events to listeners
+// UnitEvent identifies a unit or building a player produced
+type UnitEvent struct {
+	ID       EntityID
+	PlayerID int
+	Key      string
+}
+
+// KillEvent describes a unit or building destroyed by damage
+type KillEvent struct {
+	Victim      EntityID
+	PlayerID    int // owner of the victim
+	Killer      EntityID
+	KillerOwner int
+	Credited    bool // Killer and KillerOwner are known
+}
+
+// UnderAttack reports an enemy hitting one of a player's units or buildings
+type UnderAttack struct {
+	PlayerID int
+	Target   EntityID
+	Attacker EntityID
+	X, Y     float64
+	Building bool
+}
+
+// HarvestEvent reports ore credited to a player
+type HarvestEvent struct {
+	PlayerID int
+	Amount   int
+}
+
+// CratePickup describes a collected crate
+type CratePickup struct {
+	PlayerID int
+	Unit     EntityID
+	Bonus    CrateBonus
+	X, Y     int
+}
+
+// OreWasted reports harvested ore lost because storage was full
+type OreWasted struct {
+	PlayerID int
+	Amount   int // credits lost
+}
+
+// ChronoWarp describes a finished teleport
+type ChronoWarp struct {
+	Unit         EntityID
+	FromX, FromY float64
+	ToX, ToY     float64
+}
+
+// DamageEvent describes one hit after armor was applied
+type DamageEvent struct {
+	Target     EntityID
+	X, Y       float64 // target position
+	Amount     int
+	Multiplier float64 // damage-type vs armor multiplier; >1 effective, <1 reduced
+	Killed     bool
+}
+
+// EventBus dispatches events to listeners. Published events are queued and
+// delivered together by Dispatch, which the game loop runs after each tick.
 type EventBus struct {
-	listeners map[EventType][]EventHandler
-	queue     []Event
+	listeners   map[EventType][]EventHandler
+	queue       []Event
+	dispatching bool
+	pending     []subscription // added mid-dispatch; live from the next Dispatch
 }
 
 type EventHandler func(e Event)
 
+type subscription struct {
+	t EventType
+	h EventHandler
+}
+
 func NewEventBus() *EventBus {
 	return &EventBus{
 		listeners: make(map[EventType][]EventHandler),
@@ -46,22 +123,42 @@ func NewEventBus() *EventBus {
 
 // On registers a handler for an event type
 func (eb *EventBus) On(t EventType, h EventHandler) {
+	if eb.dispatching {
+		eb.pending = append(eb.pending, subscription{t, h})
+		return
+	}
 	eb.listeners[t] = append(eb.listeners[t], h)
 }
 
-// Emit queues an event for dispatch
-func (eb *EventBus) Emit(e Event) {
+// Subscribe registers a handler that receives the payload as T. Events of
+// type t carrying some other payload are skipped.
+func Subscribe[T any](eb *EventBus, t EventType, h func(T)) {
+	eb.On(t, func(e Event) {
+		if p, ok := e.Payload.(T); ok {
+			h(p)
+		}
+	})
+}
+
+// Publish queues an event for the next Dispatch
+func (eb *EventBus) Publish(e Event) {
 	eb.queue = append(eb.queue, e)
 }
 
-// Dispatch processes all queued events
+// Dispatch delivers every queued event once. Events published by handlers
+// wait for the next Dispatch, as do handlers subscribed during this one.
 func (eb *EventBus) Dispatch() {
-	for _, e := range eb.queue {
-		if handlers, ok := eb.listeners[e.Type]; ok {
-			for _, h := range handlers {
-				h(e)
-			}
+	queue := eb.queue
+	eb.queue = nil
+	eb.dispatching = true
+	for _, e := range queue {
+		for _, h := range eb.listeners[e.Type] {
+			h(e)
 		}
 	}
-	eb.queue = eb.queue[:0]
+	eb.dispatching = false
+	for _, sub := range eb.pending {
+		eb.listeners[sub.t] = append(eb.listeners[sub.t], sub.h)
+	}
+	eb.pending = nil
 }
@@ -0,0 +1,69 @@
+package core
+
+import "testing"
+
+func TestDispatchDeliversOncePerSubscriber(t *testing.T) {
+	tests := []struct {
+		name      string
+		published []Event
+		wantA     int // deliveries to each of the two UnitDamaged subscribers
+		wantKills int
+	}{
+		{"nothing published", nil, 0, 0},
+		{"one event", []Event{{Type: EvtUnitDamaged, Payload: DamageEvent{Amount: 5}}}, 1, 0},
+		{"mixed events", []Event{
+			{Type: EvtUnitDamaged, Payload: DamageEvent{Amount: 5}},
+			{Type: EvtUnitDestroyed, Payload: KillEvent{Victim: 3}},
+			{Type: EvtUnitDamaged, Payload: DamageEvent{Amount: 7}},
+		}, 2, 1},
+		{"wrong payload type skipped", []Event{{Type: EvtUnitDamaged, Payload: "oops"}}, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			bus := NewEventBus()
+			var a, b, kills int
+			Subscribe(bus, EvtUnitDamaged, func(DamageEvent) { a++ })
+			Subscribe(bus, EvtUnitDamaged, func(DamageEvent) { b++ })
+			Subscribe(bus, EvtUnitDestroyed, func(KillEvent) { kills++ })
+
+			for _, e := range tc.published {
+				bus.Publish(e)
+			}
+			if a != 0 || b != 0 || kills != 0 {
+				t.Fatal("events delivered before Dispatch")
+			}
+			bus.Dispatch()
+			bus.Dispatch() // a second dispatch has nothing left to deliver
+			if a != tc.wantA || b != tc.wantA || kills != tc.wantKills {
+				t.Errorf("deliveries %d/%d/%d, want %d/%d/%d", a, b, kills, tc.wantA, tc.wantA, tc.wantKills)
+			}
+		})
+	}
+}
+
+func TestDispatchReentrancy(t *testing.T) {
+	bus := NewEventBus()
+	var late, chained int
+	Subscribe(bus, EvtUnitDamaged, func(DamageEvent) {
+		// A handler that subscribes and publishes mid-dispatch
+		Subscribe(bus, EvtUnitDamaged, func(DamageEvent) { late++ })
+		bus.Publish(Event{Type: EvtUnderAttack, Payload: UnderAttack{PlayerID: 1}})
+	})
+	Subscribe(bus, EvtUnderAttack, func(UnderAttack) { chained++ })
+
+	bus.Publish(Event{Type: EvtUnitDamaged, Payload: DamageEvent{}})
+	bus.Dispatch()
+	if late != 0 || chained != 0 {
+		t.Fatalf("mid-dispatch changes took effect at once: late %d, chained %d", late, chained)
+	}
+
+	bus.Dispatch()
+	if chained != 1 {
+		t.Errorf("event published by a handler delivered %d times, want 1", chained)
+	}
+	bus.Publish(Event{Type: EvtUnitDamaged, Payload: DamageEvent{}})
+	bus.Dispatch()
+	if late != 1 {
+		t.Errorf("handler subscribed mid-dispatch ran %d times on the next event, want 1", late)
+	}
+}
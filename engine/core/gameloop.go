@@ -17,9 +17,14 @@ const (
 type GameLoop struct {
 	World       *World
 	State       GameState
-	TickRate    float64 // fixed ticks per second
+	TickRate    float64   // fixed ticks per second
+	Events      *EventBus // optional; dispatched after every tick
 	accumulator float64
 	lastTime    time.Time
+
+	// Profiling: ticks run and wall time spent in World.Tick during the last Update
+	TicksRun int
+	TickTime time.Duration
 }
 
 // NewGameLoop creates a game loop with fixed tick rate
@@ -47,12 +52,16 @@ func (gl *GameLoop) Update() float64 {
 	dt := 1.0 / gl.TickRate
 	gl.accumulator += frameTime
 
+	gl.TicksRun = 0
+	tickStart := now
 	for gl.accumulator >= dt {
 		if gl.State == StatePlaying {
-			gl.World.Tick(dt)
+			gl.tick(dt)
+			gl.TicksRun++
 		}
 		gl.accumulator -= dt
 	}
+	gl.TickTime = time.Since(tickStart)
 
 	// Return interpolation alpha for smooth rendering
 	return gl.accumulator / dt
@@ -69,6 +78,19 @@ func (gl *GameLoop) Pause() {
 	gl.State = StatePaused
 }
 
+// Step runs exactly one tick, ignoring wall-clock time and the pause state
+func (gl *GameLoop) Step() {
+	gl.tick(1.0 / gl.TickRate)
+}
+
+// tick advances the world once, then delivers the events it published
+func (gl *GameLoop) tick(dt float64) {
+	gl.World.Tick(dt)
+	if gl.Events != nil {
+		gl.Events.Dispatch()
+	}
+}
+
 // CurrentTick returns the current simulation tick
 func (gl *GameLoop) CurrentTick() uint64 {
 	return gl.World.TickCount
@@ -1,17 +1,26 @@
 package core
 
+import "image/color"
+
 // Player represents a game player
 type Player struct {
-	ID       int
-	Name     string
-	TeamID   int
-	Faction  string
-	Color    uint32 // RGBA
-	Credits  int    // money
-	Power    int    // current power generation
-	PowerUse int    // current power consumption
-	IsAI     bool
-	Defeated bool
+	ID          int
+	Name        string
+	TeamID      int
+	Faction     string
+	Color       uint32 // RGBA
+	Credits     int    // money
+	Ore         int    // part of Credits held as harvested ore in storage
+	OreCapacity int    // how much ore refineries and silos can hold
+	Spent       int    // credits spent over the game
+	Power       int    // current power generation
+	PowerUse    int    // current power consumption
+	IsAI        bool
+	Defeated    bool
+	Radar       bool // owns a working radar with enough power
+
+	WarMode     bool    // new combat units attack-move to AttackPoint
+	AttackPoint TilePos // staging point for war mode
 }
 
 // PowerRatio returns the power ratio (>= 1.0 means enough power)
@@ -22,11 +31,43 @@ func (p *Player) PowerRatio() float64 {
 	return float64(p.Power) / float64(p.PowerUse)
 }
 
+// RGBA returns the player's team color
+func (p *Player) RGBA() color.RGBA {
+	return color.RGBA{uint8(p.Color >> 24), uint8(p.Color >> 16), uint8(p.Color >> 8), uint8(p.Color)}
+}
+
+// StoreOre banks harvested ore worth value, as far as storage allows, and
+// returns the amount lost for want of room. Banked ore counts as credits.
+func (p *Player) StoreOre(value int) int {
+	room := max(p.OreCapacity-p.Ore, 0)
+	stored := min(value, room)
+	p.Ore += stored
+	p.Credits += stored
+	return value - stored
+}
+
+// Spend deducts credits and records them as spent. Stored ore is spent
+// first, freeing room for more.
+func (p *Player) Spend(amount int) {
+	p.Credits -= amount
+	p.Spent += amount
+	p.Ore = max(min(p.Ore-amount, p.Credits), 0)
+}
+
+// Refund returns credits from a cancelled purchase
+func (p *Player) Refund(amount int) {
+	p.Credits += amount
+	p.Spent -= amount
+}
+
 // HasPower returns true if power is sufficient
 func (p *Player) HasPower() bool {
 	return p.Power >= p.PowerUse
 }
 
+// NeutralPlayerID owns civilian buildings and props; it is never auto-targeted
+const NeutralPlayerID = -1
+
 // PlayerManager manages all players in a game
 type PlayerManager struct {
 	Players []*Player
@@ -0,0 +1,71 @@
+package core
+
+import "testing"
+
+func TestStoreOre(t *testing.T) {
+	tests := []struct {
+		name        string
+		credits     int
+		ore         int
+		capacity    int
+		value       int
+		wantCredits int
+		wantOre     int
+		wantWasted  int
+	}{
+		{"room to spare", 10000, 0, 4000, 500, 10500, 500, 0},
+		{"fills storage", 10000, 3800, 4000, 500, 10200, 4000, 300},
+		{"storage full", 10000, 4000, 4000, 500, 10000, 4000, 500},
+		{"no storage", 10000, 0, 0, 500, 10000, 0, 500},
+		{"broke but room", 0, 0, 7000, 500, 500, 500, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Player{Credits: tc.credits, Ore: tc.ore, OreCapacity: tc.capacity}
+			wasted := p.StoreOre(tc.value)
+			if p.Credits != tc.wantCredits || p.Ore != tc.wantOre || wasted != tc.wantWasted {
+				t.Errorf("credits %d, ore %d, wasted %d; want %d, %d, %d",
+					p.Credits, p.Ore, wasted, tc.wantCredits, tc.wantOre, tc.wantWasted)
+			}
+		})
+	}
+}
+
+func TestSpendDrawsStoredOreFirst(t *testing.T) {
+	tests := []struct {
+		name    string
+		credits int
+		ore     int
+		spend   int
+		wantOre int
+	}{
+		{"less than stored", 10000, 3000, 1000, 2000},
+		{"more than stored", 10000, 3000, 5000, 0},
+		{"nothing stored", 10000, 0, 1000, 0},
+		{"everything", 3000, 3000, 3000, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := &Player{Credits: tc.credits, Ore: tc.ore, OreCapacity: 4000}
+			p.Spend(tc.spend)
+			if p.Ore != tc.wantOre {
+				t.Errorf("ore = %d, want %d", p.Ore, tc.wantOre)
+			}
+			if p.Credits != tc.credits-tc.spend {
+				t.Errorf("credits = %d, want %d", p.Credits, tc.credits-tc.spend)
+			}
+		})
+	}
+}
+
+func TestSpendingMakesRoomForOre(t *testing.T) {
+	p := &Player{Credits: 10000, OreCapacity: 4000}
+	p.StoreOre(4000)
+	if wasted := p.StoreOre(500); wasted != 500 {
+		t.Fatalf("stored ore past capacity, wasted %d", wasted)
+	}
+	p.Spend(1000)
+	if wasted := p.StoreOre(500); wasted != 0 {
+		t.Errorf("wasted %d after spending freed room", wasted)
+	}
+}
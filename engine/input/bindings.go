@@ -0,0 +1,155 @@
+package input
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Gameplay action names used in key binding files
+const (
+	ActionScrollUp      = "scroll_up"
+	ActionScrollDown    = "scroll_down"
+	ActionScrollLeft    = "scroll_left"
+	ActionScrollRight   = "scroll_right"
+	ActionMenu          = "menu" // pause menu / cancel placement
+	ActionToggleGrid    = "toggle_grid"
+	ActionToggleMinimap = "toggle_minimap"
+	ActionPerfOverlay   = "perf_overlay"
+	ActionFullscreen    = "fullscreen"
+	ActionDeploy        = "deploy"
+	ActionRelocate      = "relocate" // pack up the Construction Yard and pick a new site
+	ActionSell          = "sell"
+	ActionPowerToggle   = "power_toggle"
+	ActionQueueInfantry = "queue_infantry"
+	ActionCycleSubGroup = "cycle_subgroup"
+	ActionStop          = "stop"           // clear orders and hold fire
+	ActionScatter       = "scatter"        // spread selected units onto free tiles
+	ActionFlare         = "flare"          // light up the area under the cursor at night
+	ActionChrono        = "chrono"         // teleport selected units to the cursor
+	ActionWarMode       = "war_mode"       // send new combat units to the attack point
+	ActionAttackPoint   = "attack_point"   // stage war mode at the cursor
+	ActionShowHealth    = "show_health"    // held: show every health bar
+	ActionAddModifier   = "add_modifier"   // held: add to selection, box walls
+	ActionGroupModifier = "group_modifier" // held: assign control group
+	ActionBoxAll        = "box_all"        // held: box-select buildings and support units too
+	ActionBuildModifier = "build_modifier" // held: number keys build from the sidebar tab
+)
+
+// ActionControlGroup returns the action name for control group n (0-9)
+func ActionControlGroup(n int) string {
+	return fmt.Sprintf("control_group_%d", n)
+}
+
+// KeyBindings maps action names to the keys that trigger them
+type KeyBindings map[string][]ebiten.Key
+
+// DefaultKeyBindings returns the built-in bindings
+func DefaultKeyBindings() KeyBindings {
+	kb := KeyBindings{
+		ActionScrollUp:      {ebiten.KeyW, ebiten.KeyArrowUp},
+		ActionScrollDown:    {ebiten.KeyS, ebiten.KeyArrowDown},
+		ActionScrollLeft:    {ebiten.KeyA, ebiten.KeyArrowLeft},
+		ActionScrollRight:   {ebiten.KeyD, ebiten.KeyArrowRight},
+		ActionMenu:          {ebiten.KeyEscape},
+		ActionToggleGrid:    {ebiten.KeyG},
+		ActionToggleMinimap: {ebiten.KeyM},
+		ActionPerfOverlay:   {ebiten.KeyF3},
+		ActionFullscreen:    {ebiten.KeyF11},
+		ActionDeploy:        {ebiten.KeyH},
+		ActionRelocate:      {ebiten.KeyR},
+		ActionSell:          {ebiten.KeyDelete},
+		ActionPowerToggle:   {ebiten.KeyP},
+		ActionQueueInfantry: {ebiten.KeyQ},
+		ActionCycleSubGroup: {ebiten.KeyTab},
+		ActionStop:          {ebiten.KeyZ},
+		ActionScatter:       {ebiten.KeyX},
+		ActionFlare:         {ebiten.KeyF},
+		ActionChrono:        {ebiten.KeyC},
+		ActionWarMode:       {ebiten.KeyV},
+		ActionAttackPoint:   {ebiten.KeyT},
+		ActionShowHealth:    {ebiten.KeyAlt},
+		ActionAddModifier:   {ebiten.KeyShift},
+		ActionGroupModifier: {ebiten.KeyControl},
+		ActionBoxAll:        {ebiten.KeyControl},
+		ActionBuildModifier: {ebiten.KeyB},
+	}
+	for i := 0; i <= 9; i++ {
+		kb[ActionControlGroup(i)] = []ebiten.Key{ebiten.Key0 + ebiten.Key(i)}
+	}
+	return kb
+}
+
+// LoadKeyBindings reads a JSON file of {"action": ["Key", ...]} and applies
+// it over the defaults. Unknown actions and key names are rejected.
+func LoadKeyBindings(path string) (KeyBindings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	kb := DefaultKeyBindings()
+	for action, names := range raw {
+		if _, ok := kb[action]; !ok {
+			return nil, fmt.Errorf("%s: unknown action %q", path, action)
+		}
+		keys := make([]ebiten.Key, 0, len(names))
+		for _, name := range names {
+			var k ebiten.Key
+			if err := k.UnmarshalText([]byte(name)); err != nil {
+				return nil, fmt.Errorf("%s: action %q: unknown key %q", path, action, name)
+			}
+			keys = append(keys, k)
+		}
+		kb[action] = keys
+	}
+	return kb, nil
+}
+
+// Save writes the bindings as JSON
+func (kb KeyBindings) Save(path string) error {
+	data, err := json.MarshalIndent(kb, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// KeyNames returns the keys bound to an action for display, e.g. "W/ArrowUp"
+func (kb KeyBindings) KeyNames(action string) string {
+	names := make([]string, len(kb[action]))
+	for i, k := range kb[action] {
+		names[i] = k.String()
+	}
+	if len(names) == 0 {
+		return "-"
+	}
+	return strings.Join(names, "/")
+}
+
+// Action returns true if a key bound to the action was just pressed
+func (s *InputState) Action(name string) bool {
+	for _, k := range s.Bindings[name] {
+		if inpututil.IsKeyJustPressed(k) {
+			return true
+		}
+	}
+	return false
+}
+
+// ActionHeld returns true while any key bound to the action is down
+func (s *InputState) ActionHeld(name string) bool {
+	for _, k := range s.Bindings[name] {
+		if ebiten.IsKeyPressed(k) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package input
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// sharedKeys lists actions deliberately bound to the same key: they are
+// held modifiers used in different gestures
+var sharedKeys = [][2]string{
+	{ActionGroupModifier, ActionBoxAll}, // Ctrl+number vs Ctrl+drag
+}
+
+func shareable(a, b string) bool {
+	for _, p := range sharedKeys {
+		if (p[0] == a && p[1] == b) || (p[0] == b && p[1] == a) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestDefaultBindingsDontClash(t *testing.T) {
+	owner := map[ebiten.Key][]string{}
+	for action, keys := range DefaultKeyBindings() {
+		for _, k := range keys {
+			owner[k] = append(owner[k], action)
+		}
+	}
+	for k, actions := range owner {
+		slices.Sort(actions)
+		for i := 0; i < len(actions); i++ {
+			for j := i + 1; j < len(actions); j++ {
+				if !shareable(actions[i], actions[j]) {
+					t.Errorf("key %v is bound to both %q and %q", k, actions[i], actions[j])
+				}
+			}
+		}
+	}
+}
+
+func TestWASDScrolls(t *testing.T) {
+	kb := DefaultKeyBindings()
+	tests := []struct {
+		action string
+		key    ebiten.Key
+	}{
+		{ActionScrollUp, ebiten.KeyW},
+		{ActionScrollLeft, ebiten.KeyA},
+		{ActionScrollDown, ebiten.KeyS},
+		{ActionScrollRight, ebiten.KeyD},
+	}
+	for _, tc := range tests {
+		if !slices.Contains(kb[tc.action], tc.key) {
+			t.Errorf("%s isn't bound to %v", tc.action, tc.key)
+		}
+	}
+}
+
+func TestBuildModifierHasOwnKey(t *testing.T) {
+	kb := DefaultKeyBindings()
+	for _, k := range kb[ActionBuildModifier] {
+		for action, keys := range kb {
+			if action != ActionBuildModifier && slices.Contains(keys, k) {
+				t.Errorf("build modifier key %v is also bound to %q", k, action)
+			}
+		}
+	}
+}
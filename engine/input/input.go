@@ -20,6 +20,14 @@ type InputState struct {
 	RightJustReleased  bool
 	ScrollY            float64
 
+	// Double click: set when a press lands close to the previous one, and
+	// kept until the next press
+	DoubleClick    bool
+	frame          int
+	lastClickFrame int
+	lastClickX     int
+	lastClickY     int
+
 	// Drag
 	DragStartX, DragStartY int
 	Dragging               bool
@@ -27,12 +35,17 @@ type InputState struct {
 
 	// Keyboard
 	KeysPressed map[ebiten.Key]bool
+	Bindings    KeyBindings
 }
 
+// DoubleClickFrames is the most frames apart two presses count as a double click
+const DoubleClickFrames = 18
+
 func NewInputState() *InputState {
 	return &InputState{
 		DragThreshold: 5,
 		KeysPressed:   make(map[ebiten.Key]bool),
+		Bindings:      DefaultKeyBindings(),
 	}
 }
 
@@ -60,6 +73,15 @@ func (s *InputState) Update() {
 	_, scrollY := ebiten.Wheel()
 	s.ScrollY = scrollY
 
+	// Double click tracking
+	s.frame++
+	if s.LeftJustPressed {
+		dx, dy := s.MouseX-s.lastClickX, s.MouseY-s.lastClickY
+		s.DoubleClick = s.frame-s.lastClickFrame <= DoubleClickFrames && dx*dx+dy*dy <= s.DragThreshold*s.DragThreshold
+		s.lastClickFrame = s.frame
+		s.lastClickX, s.lastClickY = s.MouseX, s.MouseY
+	}
+
 	// Drag tracking
 	if s.LeftJustPressed {
 		s.DragStartX = s.MouseX
@@ -0,0 +1,335 @@
+package maplib
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Symmetry selects how a generated map is balanced between players
+type Symmetry int
+
+const (
+	SymmetryRotational Symmetry = iota // point/90° rotation around the centre
+	SymmetryMirror                     // reflection across the centre lines
+)
+
+// GenOptions configures procedural map generation
+type GenOptions struct {
+	Width, Height   int
+	Players         int     // 2 or 4
+	ResourceDensity float64 // 1.0 = normal amount of ore
+	Symmetry        Symmetry
+	Seed            int64
+
+	// Connected reports whether a vehicle can drive from (sx, sy) to
+	// (gx, gy). pathfind.GenerateMap answers it with the real pathfinder;
+	// left nil, start positions aren't checked.
+	Connected func(tm *TileMap, sx, sy, gx, gy int) bool
+}
+
+// GenerateMap builds a balanced skirmish map: mirrored start positions,
+// ore near each base, a river with bridges between bases and scattered
+// forests and cliffs. When opts.Connected is set, corridors are carved
+// until every base can reach the others by ground.
+func GenerateMap(opts GenOptions) (*TileMap, error) {
+	if opts.Width <= 0 {
+		opts.Width = 64
+	}
+	if opts.Height <= 0 {
+		opts.Height = opts.Width
+	}
+	if opts.Players <= 0 {
+		opts.Players = 2
+	}
+	if opts.Players != 2 && opts.Players != 4 {
+		return nil, fmt.Errorf("maplib: unsupported player count %d", opts.Players)
+	}
+	if opts.Width < 32 || opts.Height < 32 {
+		return nil, errors.New("maplib: generated maps must be at least 32x32")
+	}
+	if opts.ResourceDensity <= 0 {
+		opts.ResourceDensity = 1.0
+	}
+	// 90° rotation only works on square maps
+	if opts.Players == 4 && opts.Width != opts.Height {
+		opts.Symmetry = SymmetryMirror
+	}
+
+	g := &mapGen{
+		tm:   NewTileMap(fmt.Sprintf("Generated %d", opts.Seed), opts.Width, opts.Height),
+		opts: opts,
+		rng:  rand.New(rand.NewSource(opts.Seed)),
+	}
+	g.tm.MaxPlayers = opts.Players
+	g.tm.Author = "GenerateMap"
+	g.tm.Description = fmt.Sprintf("Procedural %d-player map (seed %d)", opts.Players, opts.Seed)
+
+	g.placeStarts()
+	g.placeRiver()
+	g.placeForests()
+	g.placeCliffs()
+	g.placeOre()
+	g.clearBases()
+	g.placeBridges()
+	g.symmetrize()
+
+	for attempt := 0; ; attempt++ {
+		if g.connected() {
+			return g.tm, nil
+		}
+		if attempt == 3 {
+			return nil, errors.New("maplib: could not connect start positions")
+		}
+		g.carveCorridors()
+		g.symmetrize()
+	}
+}
+
+type mapGen struct {
+	tm     *TileMap
+	opts   GenOptions
+	rng    *rand.Rand
+	starts [][2]int
+}
+
+// images returns every symmetric counterpart of (x, y), including itself
+func (g *mapGen) images(x, y int) [][2]int {
+	w, h := g.tm.Width-1, g.tm.Height-1
+	if g.opts.Players == 2 {
+		if g.opts.Symmetry == SymmetryMirror {
+			return [][2]int{{x, y}, {w - x, y}}
+		}
+		return [][2]int{{x, y}, {w - x, h - y}}
+	}
+	if g.opts.Symmetry == SymmetryMirror {
+		return [][2]int{{x, y}, {w - x, y}, {w - x, h - y}, {x, h - y}}
+	}
+	return [][2]int{{x, y}, {w - y, x}, {w - x, h - y}, {y, w - x}}
+}
+
+// symmetrize copies each tile's canonical counterpart over it so the
+// finished map is exactly symmetric regardless of how features landed.
+func (g *mapGen) symmetrize() {
+	tm := g.tm
+	for y := 0; y < tm.Height; y++ {
+		for x := 0; x < tm.Width; x++ {
+			best := y*tm.Width + x
+			for _, p := range g.images(x, y) {
+				if i := p[1]*tm.Width + p[0]; i < best {
+					best = i
+				}
+			}
+			tm.Tiles[y*tm.Width+x] = tm.Tiles[best]
+		}
+	}
+}
+
+func (g *mapGen) placeStarts() {
+	margin := g.tm.Width / 6
+	sx, sy := margin, margin
+	if g.opts.Players == 2 && g.opts.Symmetry == SymmetryMirror {
+		sy = g.tm.Height / 2
+	}
+	g.starts = g.images(sx, sy)
+	for i, p := range g.starts {
+		g.tm.StartPositions = append(g.tm.StartPositions, StartPos{PlayerSlot: i, X: p[0], Y: p[1]})
+	}
+}
+
+// nearestStarts returns the distances to the closest and second closest start
+func (g *mapGen) nearestStarts(x, y int) (d1, d2 float64) {
+	d1, d2 = math.MaxFloat64, math.MaxFloat64
+	for _, s := range g.starts {
+		d := math.Hypot(float64(x-s[0]), float64(y-s[1]))
+		if d < d1 {
+			d1, d2 = d, d1
+		} else if d < d2 {
+			d2 = d
+		}
+	}
+	return
+}
+
+// placeRiver floods the boundary between base territories with water
+func (g *mapGen) placeRiver() {
+	ph1, ph2 := g.rng.Float64()*math.Pi*2, g.rng.Float64()*math.Pi*2
+	for y := 0; y < g.tm.Height; y++ {
+		for x := 0; x < g.tm.Width; x++ {
+			d1, d2 := g.nearestStarts(x, y)
+			wobble := 1.5 * math.Sin(float64(x)*0.21+ph1) * math.Cos(float64(y)*0.17+ph2)
+			edge := d2 - d1 + wobble
+			switch {
+			case math.Abs(edge) < 1.0:
+				g.tm.SetTerrain(x, y, x, y, TerrainDeepWater)
+			case math.Abs(edge) < 2.8:
+				g.tm.SetTerrain(x, y, x, y, TerrainWater)
+			}
+		}
+	}
+}
+
+// placeBridges spans the river along the straight line between each pair of bases
+func (g *mapGen) placeBridges() {
+	for i := range g.starts {
+		for j := i + 1; j < len(g.starts); j++ {
+			g.walkLine(g.starts[i], g.starts[j], func(x, y int) {
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if t := g.tm.At(x+dx, y+dy); t != nil && isWater(t.Terrain) {
+							g.tm.SetTerrain(x+dx, y+dy, x+dx, y+dy, TerrainBridge)
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+func (g *mapGen) placeForests() {
+	count := g.tm.Width * g.tm.Height / 300
+	for i := 0; i < count; i++ {
+		cx, cy := g.rng.Intn(g.tm.Width), g.rng.Intn(g.tm.Height)
+		r := 1 + g.rng.Intn(3)
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				if dx*dx+dy*dy <= r*r && g.rng.Float64() < 0.8 {
+					g.paintIfOpen(cx+dx, cy+dy, TerrainForest)
+				}
+			}
+		}
+	}
+}
+
+// placeCliffs scatters short cliff ridges with a rock apron
+func (g *mapGen) placeCliffs() {
+	count := g.tm.Width * g.tm.Height / 800
+	for i := 0; i < count; i++ {
+		x, y := g.rng.Intn(g.tm.Width), g.rng.Intn(g.tm.Height)
+		dx, dy := 1, 0
+		if g.rng.Intn(2) == 0 {
+			dx, dy = 0, 1
+		}
+		n := 3 + g.rng.Intn(4)
+		for k := 0; k < n; k++ {
+			g.paintIfOpen(x+dx*k, y+dy*k, TerrainCliff)
+			g.paintIfOpen(x+dx*k+dy, y+dy*k+dx, TerrainRock)
+		}
+	}
+}
+
+// placeOre puts a field near each base plus a contested field in the middle
+func (g *mapGen) placeOre() {
+	cx, cy := g.tm.Width/2, g.tm.Height/2
+	s := g.starts[0]
+	dx, dy := float64(cx-s[0]), float64(cy-s[1])
+	l := math.Hypot(dx, dy)
+	ox := s[0] + int(math.Round(dx/l*7))
+	oy := s[1] + int(math.Round(dy/l*7))
+	r := int(math.Round(1.5 + g.opts.ResourceDensity))
+	g.oreField(ox, oy, r, 1000)
+
+	mid := int(math.Round(2 * g.opts.ResourceDensity))
+	for i := 0; i < mid; i++ {
+		px := (s[0] + cx) / 2
+		py := (s[1] + cy) / 2
+		px += g.rng.Intn(7) - 3
+		py += g.rng.Intn(7) - 3
+		g.oreField(px, py, 1, 1500)
+	}
+}
+
+func (g *mapGen) oreField(cx, cy, r, amount int) {
+	for dy := -r; dy <= r; dy++ {
+		for dx := -r; dx <= r; dx++ {
+			if dx*dx+dy*dy > r*r {
+				continue
+			}
+			t := g.tm.At(cx+dx, cy+dy)
+			if t == nil || isWater(t.Terrain) || (t.Passable&PassVehicle == 0 && t.Terrain != TerrainForest) {
+				continue
+			}
+			if t.Terrain == TerrainForest {
+				g.tm.SetTerrain(cx+dx, cy+dy, cx+dx, cy+dy, TerrainGrass)
+			}
+			g.tm.PlaceOre(cx+dx, cy+dy, amount)
+		}
+	}
+}
+
+// clearBases resets the area around every start to open grass
+func (g *mapGen) clearBases() {
+	for _, s := range g.starts {
+		for dy := -5; dy <= 5; dy++ {
+			for dx := -5; dx <= 5; dx++ {
+				if dx*dx+dy*dy > 25 {
+					continue
+				}
+				if t := g.tm.At(s[0]+dx, s[1]+dy); t != nil && t.OreAmount == 0 {
+					g.tm.SetTerrain(s[0]+dx, s[1]+dy, s[0]+dx, s[1]+dy, TerrainGrass)
+				}
+			}
+		}
+	}
+}
+
+// carveCorridors opens a ground route along each base-to-base line
+func (g *mapGen) carveCorridors() {
+	for i := range g.starts {
+		for j := i + 1; j < len(g.starts); j++ {
+			g.walkLine(g.starts[i], g.starts[j], func(x, y int) {
+				for _, p := range [][2]int{{x, y}, {x + 1, y}, {x, y + 1}} {
+					t := g.tm.At(p[0], p[1])
+					if t == nil || t.Passable&PassVehicle != 0 {
+						continue
+					}
+					if isWater(t.Terrain) {
+						g.tm.SetTerrain(p[0], p[1], p[0], p[1], TerrainBridge)
+					} else {
+						g.tm.SetTerrain(p[0], p[1], p[0], p[1], TerrainDirt)
+					}
+				}
+			})
+		}
+	}
+}
+
+// connected reports whether every start can reach every other by vehicle
+func (g *mapGen) connected() bool {
+	if g.opts.Connected == nil {
+		return true
+	}
+	s0 := g.starts[0]
+	for _, s := range g.starts[1:] {
+		if !g.opts.Connected(g.tm, s0[0], s0[1], s[0], s[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (g *mapGen) paintIfOpen(x, y int, terrain TerrainType) {
+	t := g.tm.At(x, y)
+	if t == nil || t.Terrain != TerrainGrass {
+		return
+	}
+	for _, s := range g.starts {
+		if dx, dy := x-s[0], y-s[1]; dx*dx+dy*dy < 64 {
+			return
+		}
+	}
+	g.tm.SetTerrain(x, y, x, y, terrain)
+}
+
+// walkLine calls fn for each tile on the straight line between two starts
+func (g *mapGen) walkLine(a, b [2]int, fn func(x, y int)) {
+	WalkLine(a[0], a[1], b[0], b[1], func(x, y int) bool {
+		fn(x, y)
+		return true
+	})
+}
+
+func isWater(t TerrainType) bool {
+	return t == TerrainWater || t == TerrainDeepWater
+}
@@ -0,0 +1,60 @@
+package maplib
+
+import "testing"
+
+func TestGenerateMapOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    GenOptions
+		wantErr bool
+	}{
+		{"defaults", GenOptions{Seed: 1}, false},
+		{"three players", GenOptions{Players: 3}, true},
+		{"too small", GenOptions{Width: 16, Height: 16}, true},
+		{"four players non-square", GenOptions{Width: 64, Height: 48, Players: 4, Symmetry: SymmetryRotational}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := GenerateMap(tc.opts)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerateMapSymmetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   GenOptions
+		mirror func(tm *TileMap, x, y int) (int, int)
+	}{
+		{"2p rotational", GenOptions{Width: 64, Height: 64, Players: 2, Symmetry: SymmetryRotational, Seed: 7},
+			func(tm *TileMap, x, y int) (int, int) { return tm.Width - 1 - x, tm.Height - 1 - y }},
+		{"2p mirror", GenOptions{Width: 64, Height: 64, Players: 2, Symmetry: SymmetryMirror, Seed: 7},
+			func(tm *TileMap, x, y int) (int, int) { return tm.Width - 1 - x, y }},
+		{"4p rotational", GenOptions{Width: 64, Height: 64, Players: 4, Symmetry: SymmetryRotational, Seed: 7},
+			func(tm *TileMap, x, y int) (int, int) { return tm.Width - 1 - y, x }},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm, err := GenerateMap(tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ore := 0
+			for y := 0; y < tm.Height; y++ {
+				for x := 0; x < tm.Width; x++ {
+					mx, my := tc.mirror(tm, x, y)
+					if a, b := tm.At(x, y), tm.At(mx, my); a.Terrain != b.Terrain || a.OreAmount != b.OreAmount {
+						t.Fatalf("tile (%d,%d) and its image (%d,%d) differ", x, y, mx, my)
+					}
+					ore += tm.At(x, y).OreAmount
+				}
+			}
+			if ore == 0 {
+				t.Error("map has no ore")
+			}
+		})
+	}
+}
@@ -23,6 +23,8 @@ const (
 	TerrainSnow
 	TerrainUrban
 	TerrainForest
+	TerrainRamp        // slope connecting two elevation levels
+	TerrainBridgeWreck // collapsed bridge span; a gap until an engineer repairs it
 )
 
 // Passability flags
@@ -39,11 +41,17 @@ const (
 // Tile represents a single map tile
 type Tile struct {
 	Terrain    TerrainType `json:"terrain"`
-	Height     int8        `json:"height"`     // elevation level (0-7)
+	Height     int8        `json:"height"`     // elevation level (0-3)
 	Passable   PassFlag    `json:"passable"`
 	TileVariant uint8      `json:"variant"`    // visual variant index
 	OreAmount  int         `json:"ore"`        // resource amount (0 = none)
 	Occupied   bool        `json:"-"`          // runtime: building placed here
+
+	// Runtime forest state
+	ForestDamage int     `json:"-"` // damage taken; cleared to dirt at ForestHP
+	Burning      float64 `json:"-"` // seconds of fire left
+
+	BridgeDamage int `json:"-"` // runtime: damage taken; the span collapses at BridgeHP
 }
 
 // TileMap represents the game map
@@ -55,15 +63,41 @@ type TileMap struct {
 	Tiles   []Tile `json:"tiles"`
 
 	// Map metadata
-	StartPositions []StartPos `json:"start_positions"`
-	Description    string     `json:"description"`
-	MaxPlayers     int        `json:"max_players"`
+	StartPositions []StartPos    `json:"start_positions"`
+	Entities       []EntitySpawn `json:"entities,omitempty"`
+	Triggers       []TriggerDef  `json:"triggers,omitempty"`
+	Description    string        `json:"description"`
+	MaxPlayers     int           `json:"max_players"`
+
+	// Crates: a new random crate appears every CrateInterval seconds while
+	// fewer than MaxCrates are on the map (0 disables respawning)
+	CrateInterval float64 `json:"crate_interval,omitempty"`
+	MaxCrates     int     `json:"max_crates,omitempty"`
+
+	// Environment: a day/night cycle and standing weather
+	DayNight bool    `json:"day_night,omitempty"`
+	Weather  Weather `json:"weather,omitempty"`
 
 	// Isometric rendering constants
 	TileWidth  int `json:"tile_width"`  // pixel width of a tile (default 64)
 	TileHeight int `json:"tile_height"` // pixel height of a tile (default 32)
+
+	// Revision counts terrain changes so renderers know to rebuild caches
+	Revision int `json:"-"`
+	// Footprints counts building placements and removals so nav grids know
+	// to re-block tiles
+	Footprints int `json:"-"`
 }
 
+// Weather is a map's standing weather, which cuts sight ranges
+type Weather string
+
+const (
+	WeatherClear Weather = ""
+	WeatherRain  Weather = "rain"
+	WeatherFog   Weather = "fog"
+)
+
 // StartPos defines a player start position
 type StartPos struct {
 	PlayerSlot int `json:"player_slot"`
@@ -71,6 +105,38 @@ type StartPos struct {
 	Y          int `json:"y"`
 }
 
+// EntityKind classifies a pre-placed map object
+type EntityKind string
+
+const (
+	EntityUnit     EntityKind = "unit"
+	EntityBuilding EntityKind = "building"
+	EntityProp     EntityKind = "prop"
+	EntityCrate    EntityKind = "crate" // Key is the bonus name, or "random"
+)
+
+// NeutralOwner is the owner slot for civilian/neutral objects
+const NeutralOwner = -1
+
+// EntitySpawn is an object placed in the editor and created when the map loads
+type EntitySpawn struct {
+	Kind  EntityKind `json:"kind"`
+	Key   string     `json:"key"`   // tech tree key or prop name
+	Owner int        `json:"owner"` // player slot, or NeutralOwner
+	X     int        `json:"x"`
+	Y     int        `json:"y"`
+}
+
+// EntityAt returns the index of the entity spawn at (x, y), or -1
+func (tm *TileMap) EntityAt(x, y int) int {
+	for i, e := range tm.Entities {
+		if e.X == x && e.Y == y {
+			return i
+		}
+	}
+	return -1
+}
+
 // NewTileMap creates a new empty map
 func NewTileMap(name string, width, height int) *TileMap {
 	tm := &TileMap{
@@ -153,11 +219,17 @@ func LoadJSON(path string) (*TileMap, error) {
 	if err := json.Unmarshal(data, &tm); err != nil {
 		return nil, err
 	}
+	for _, d := range tm.Triggers {
+		if err := d.Validate(); err != nil {
+			return nil, err
+		}
+	}
 	return &tm, nil
 }
 
 // SetTerrain sets terrain for a rectangular region
 func (tm *TileMap) SetTerrain(x1, y1, x2, y2 int, terrain TerrainType) {
+	tm.Revision++
 	for y := y1; y <= y2; y++ {
 		for x := x1; x <= x2; x++ {
 			if t := tm.At(x, y); t != nil {
@@ -168,8 +240,10 @@ func (tm *TileMap) SetTerrain(x1, y1, x2, y2 int, terrain TerrainType) {
 					t.Passable = PassNaval | PassAir
 				case TerrainCliff:
 					t.Passable = PassAir
-				case TerrainRock:
+				case TerrainRock, TerrainForest:
 					t.Passable = PassInfantry | PassAir
+				case TerrainBridgeWreck:
+					t.Passable = PassNaval | PassAir
 				default:
 					t.Passable = PassAll
 				}
@@ -178,11 +252,154 @@ func (tm *TileMap) SetTerrain(x1, y1, x2, y2 int, terrain TerrainType) {
 	}
 }
 
+// BlocksShots reports whether a tile stops direct fire: cliffs and buildings
+func (t *Tile) BlocksShots() bool {
+	return t.Terrain == TerrainCliff || t.Occupied
+}
+
+// WalkLine visits the tiles on the Bresenham line from (x0, y0) to (x1, y1),
+// both ends included, until visit returns false. It reports whether the
+// whole line was walked.
+func WalkLine(x0, y0, x1, y1 int, visit func(x, y int) bool) bool {
+	dx, dy := x1-x0, y1-y0
+	if dx < 0 {
+		dx = -dx
+	}
+	if dy < 0 {
+		dy = -dy
+	}
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx - dy
+	x, y := x0, y0
+	for {
+		if !visit(x, y) {
+			return false
+		}
+		if x == x1 && y == y1 {
+			return true
+		}
+		e2 := err * 2
+		if e2 > -dy {
+			err -= dy
+			x += sx
+		}
+		if e2 < dx {
+			err += dx
+			y += sy
+		}
+	}
+}
+
+// ForestHP is how much damage a forest tile takes before it is cleared
+const ForestHP = 300
+
+// DamageForest wears down a forest tile and clears it to dirt once it has
+// taken ForestHP. It reports whether the tile was cleared.
+func (tm *TileMap) DamageForest(x, y, dmg int) bool {
+	t := tm.At(x, y)
+	if t == nil || t.Terrain != TerrainForest {
+		return false
+	}
+	t.ForestDamage += dmg
+	if t.ForestDamage < ForestHP {
+		return false
+	}
+	tm.SetTerrain(x, y, x, y, TerrainDirt)
+	t.ForestDamage, t.Burning = 0, 0
+	return true
+}
+
+// BridgeHP is how much damage one bridge tile takes before the span collapses
+const BridgeHP = 600
+
+// DamageBridge wears down a bridge tile. Once it has taken BridgeHP the
+// whole connected span collapses into a wreck; it reports whether it did.
+func (tm *TileMap) DamageBridge(x, y, dmg int) bool {
+	t := tm.At(x, y)
+	if t == nil || t.Terrain != TerrainBridge {
+		return false
+	}
+	t.BridgeDamage += dmg
+	if t.BridgeDamage < BridgeHP {
+		return false
+	}
+	for _, p := range tm.ConnectedTiles(x, y, TerrainBridge) {
+		tm.SetTerrain(p[0], p[1], p[0], p[1], TerrainBridgeWreck)
+		tm.At(p[0], p[1]).BridgeDamage = 0
+	}
+	return true
+}
+
+// RepairBridge rebuilds the whole wrecked span containing (x, y) and
+// reports whether there was one
+func (tm *TileMap) RepairBridge(x, y int) bool {
+	span := tm.ConnectedTiles(x, y, TerrainBridgeWreck)
+	for _, p := range span {
+		tm.SetTerrain(p[0], p[1], p[0], p[1], TerrainBridge)
+	}
+	return len(span) > 0
+}
+
+// ConnectedTiles returns the tiles of the given terrain 4-connected to
+// (x, y), including it, or nil if (x, y) is another terrain
+func (tm *TileMap) ConnectedTiles(x, y int, terrain TerrainType) [][2]int {
+	if t := tm.At(x, y); t == nil || t.Terrain != terrain {
+		return nil
+	}
+	seen := map[[2]int]bool{{x, y}: true}
+	tiles := [][2]int{{x, y}}
+	for i := 0; i < len(tiles); i++ {
+		for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+			n := [2]int{tiles[i][0] + d[0], tiles[i][1] + d[1]}
+			if t := tm.At(n[0], n[1]); t != nil && t.Terrain == terrain && !seen[n] {
+				seen[n] = true
+				tiles = append(tiles, n)
+			}
+		}
+	}
+	return tiles
+}
+
+// MaxHeightLevel is the highest discrete elevation level
+const MaxHeightLevel = 3
+
+// HeightAt returns the elevation level at (x, y), clamped to 0..MaxHeightLevel
+func (tm *TileMap) HeightAt(x, y int) int {
+	t := tm.At(x, y)
+	if t == nil || t.Height < 0 {
+		return 0
+	}
+	if t.Height > MaxHeightLevel {
+		return MaxHeightLevel
+	}
+	return int(t.Height)
+}
+
+// SetHeight sets the elevation level of a tile, clamped to 0..MaxHeightLevel
+func (tm *TileMap) SetHeight(x, y, h int) {
+	if h < 0 {
+		h = 0
+	}
+	if h > MaxHeightLevel {
+		h = MaxHeightLevel
+	}
+	if t := tm.At(x, y); t != nil {
+		t.Height = int8(h)
+	}
+}
+
 // PlaceOre places ore resources at a position
 // SetOccupied marks a tile as occupied/unoccupied by a building
 func (tm *TileMap) SetOccupied(x, y int, occupied bool) {
-	if t := tm.At(x, y); t != nil {
+	if t := tm.At(x, y); t != nil && t.Occupied != occupied {
 		t.Occupied = occupied
+		tm.Footprints++
 	}
 }
 
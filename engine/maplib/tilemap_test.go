@@ -0,0 +1,53 @@
+package maplib
+
+import "testing"
+
+func TestWalkLine(t *testing.T) {
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 int
+		want           int // tiles visited
+	}{
+		{"single tile", 3, 3, 3, 3, 1},
+		{"horizontal", 0, 0, 5, 0, 6},
+		{"vertical up", 2, 7, 2, 1, 7},
+		{"diagonal", 0, 0, 4, 4, 5},
+		{"shallow", 0, 0, 7, 2, 8},
+		{"steep backwards", 5, 9, 3, 0, 10},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var pts [][2]int
+			done := WalkLine(tc.x0, tc.y0, tc.x1, tc.y1, func(x, y int) bool {
+				pts = append(pts, [2]int{x, y})
+				return true
+			})
+			if !done {
+				t.Fatal("WalkLine reported an early stop")
+			}
+			if len(pts) != tc.want {
+				t.Errorf("visited %d tiles, want %d", len(pts), tc.want)
+			}
+			if pts[0] != [2]int{tc.x0, tc.y0} || pts[len(pts)-1] != [2]int{tc.x1, tc.y1} {
+				t.Errorf("line runs %v to %v, want both endpoints", pts[0], pts[len(pts)-1])
+			}
+			for i := 1; i < len(pts); i++ {
+				dx, dy := pts[i][0]-pts[i-1][0], pts[i][1]-pts[i-1][1]
+				if dx < -1 || dx > 1 || dy < -1 || dy > 1 || (dx == 0 && dy == 0) {
+					t.Errorf("step %d jumps from %v to %v", i, pts[i-1], pts[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWalkLineStops(t *testing.T) {
+	n := 0
+	done := WalkLine(0, 0, 10, 0, func(x, y int) bool {
+		n++
+		return x < 3
+	})
+	if done || n != 4 {
+		t.Errorf("done = %v after %d tiles, want false after 4", done, n)
+	}
+}
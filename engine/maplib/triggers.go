@@ -0,0 +1,76 @@
+package maplib
+
+import "fmt"
+
+// TriggerCondition is what a mission trigger waits for
+type TriggerCondition string
+
+const (
+	CondEnterArea         TriggerCondition = "enter_area"         // a unit of Player is inside Area
+	CondBuildingDestroyed TriggerCondition = "building_destroyed" // Player no longer owns a Key building (any building if Key is empty)
+	CondTimer             TriggerCondition = "timer"              // Seconds of game time have passed
+)
+
+// TriggerActionType is what a mission trigger does when it fires
+type TriggerActionType string
+
+const (
+	ActSpawnUnits TriggerActionType = "spawn_units" // spawn Units for Player at the centre of Area
+	ActRevealArea TriggerActionType = "reveal_area" // reveal Area to Player (for Seconds, or permanently if 0)
+	ActAirdrop    TriggerActionType = "airdrop"     // fly Units in from the map edge and drop them for Player at the centre of Area
+	ActMessage    TriggerActionType = "message"     // show Text to the player
+	ActVictory    TriggerActionType = "victory"     // Player wins, every enemy is defeated
+	ActDefeat     TriggerActionType = "defeat"      // Player is defeated
+)
+
+// Area is an inclusive rectangle of tiles
+type Area struct {
+	X1 int `json:"x1"`
+	Y1 int `json:"y1"`
+	X2 int `json:"x2"`
+	Y2 int `json:"y2"`
+}
+
+// Contains reports whether world position (x, y) lies inside the area
+func (a Area) Contains(x, y float64) bool {
+	return x >= float64(a.X1) && x < float64(a.X2+1) && y >= float64(a.Y1) && y < float64(a.Y2+1)
+}
+
+// Center returns the centre of the area in world coordinates
+func (a Area) Center() (float64, float64) {
+	return float64(a.X1+a.X2+1) / 2, float64(a.Y1+a.Y2+1) / 2
+}
+
+// TriggerDef is a mission trigger stored in the map file
+type TriggerDef struct {
+	Name      string           `json:"name"`
+	Condition TriggerCondition `json:"condition"`
+	Player    int              `json:"player"` // player slot the condition watches
+	Area      Area             `json:"area"`
+	Key       string           `json:"key,omitempty"`
+	Seconds   float64          `json:"seconds,omitempty"`
+	Repeat    bool             `json:"repeat,omitempty"`
+	Actions   []TriggerAction  `json:"actions"`
+}
+
+// Validate reports a trigger that can't run as written. A timer needs a
+// positive interval to repeat on, or it would fire on every tick.
+func (d TriggerDef) Validate() error {
+	if d.Condition != CondTimer {
+		return nil
+	}
+	if d.Seconds < 0 || (d.Repeat && d.Seconds == 0) {
+		return fmt.Errorf("maplib: trigger %q: timer needs a positive interval, got %v seconds", d.Name, d.Seconds)
+	}
+	return nil
+}
+
+// TriggerAction is one effect of a fired trigger
+type TriggerAction struct {
+	Type    TriggerActionType `json:"type"`
+	Player  int               `json:"player"`
+	Units   []string          `json:"units,omitempty"`
+	Area    Area              `json:"area"`
+	Text    string            `json:"text,omitempty"`
+	Seconds float64           `json:"seconds,omitempty"`
+}
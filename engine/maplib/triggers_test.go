@@ -0,0 +1,48 @@
+package maplib
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestTriggerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		def     TriggerDef
+		wantErr bool
+	}{
+		{"one-shot timer", TriggerDef{Condition: CondTimer, Seconds: 30}, false},
+		{"one-shot timer at the start", TriggerDef{Condition: CondTimer}, false},
+		{"repeating timer", TriggerDef{Condition: CondTimer, Seconds: 30, Repeat: true}, false},
+		{"repeating timer with no interval", TriggerDef{Condition: CondTimer, Repeat: true}, true},
+		{"negative interval", TriggerDef{Condition: CondTimer, Seconds: -5}, true},
+		{"seconds ignored off timers", TriggerDef{Condition: CondEnterArea, Repeat: true}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := tc.def.Validate(); (err != nil) != tc.wantErr {
+				t.Errorf("Validate() = %v, want error %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadJSONRejectsBadTriggers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "map.json")
+	tm := NewTileMap("t", 4, 4)
+	tm.Triggers = []TriggerDef{{Name: "spam", Condition: CondTimer, Repeat: true}}
+	if err := tm.SaveJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJSON(path); err == nil {
+		t.Error("LoadJSON accepted a repeating timer with no interval")
+	}
+
+	tm.Triggers[0].Seconds = 10
+	if err := tm.SaveJSON(path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadJSON(path); err != nil {
+		t.Errorf("LoadJSON: %v", err)
+	}
+}
@@ -12,7 +12,17 @@ type Point struct{ X, Y int }
 
 // FindPath finds a path from start to goal using A*
 func FindPath(ng *NavGrid, sx, sy, gx, gy int, flag maplib.PassFlag) []Point {
-	if !ng.Passable(gx, gy, flag) {
+	return findPath(ng, sx, sy, gx, gy, func(x, y int) bool { return ng.Passable(x, y, flag) })
+}
+
+// FindPathFor finds a path for a unit owned by player, routing around
+// gates that won't open for it
+func FindPathFor(ng *NavGrid, sx, sy, gx, gy int, flag maplib.PassFlag, player int) []Point {
+	return findPath(ng, sx, sy, gx, gy, func(x, y int) bool { return ng.PassableFor(x, y, flag, player) })
+}
+
+func findPath(ng *NavGrid, sx, sy, gx, gy int, passable func(x, y int) bool) []Point {
+	if !passable(gx, gy) {
 		return nil
 	}
 
@@ -40,12 +50,12 @@ func FindPath(ng *NavGrid, sx, sy, gx, gy int, flag maplib.PassFlag) []Point {
 
 		for _, d := range dirs {
 			nx, ny := cur.p.X+d[0], cur.p.Y+d[1]
-			if !ng.Passable(nx, ny, flag) {
+			if !passable(nx, ny) {
 				continue
 			}
 			// Prevent diagonal cutting through walls
 			if d[0] != 0 && d[1] != 0 {
-				if !ng.Passable(cur.p.X+d[0], cur.p.Y, flag) || !ng.Passable(cur.p.X, cur.p.Y+d[1], flag) {
+				if !passable(cur.p.X+d[0], cur.p.Y) || !passable(cur.p.X, cur.p.Y+d[1]) {
 					continue
 				}
 			}
@@ -68,6 +78,16 @@ func FindPath(ng *NavGrid, sx, sy, gx, gy int, flag maplib.PassFlag) []Point {
 
 // SmoothPath removes unnecessary waypoints using line-of-sight checks
 func SmoothPath(ng *NavGrid, path []Point, flag maplib.PassFlag) []Point {
+	return smoothPath(path, func(x, y int) bool { return ng.Passable(x, y, flag) })
+}
+
+// SmoothPathFor smooths a path for a unit owned by player without cutting
+// through gates that won't open for it
+func SmoothPathFor(ng *NavGrid, path []Point, flag maplib.PassFlag, player int) []Point {
+	return smoothPath(path, func(x, y int) bool { return ng.PassableFor(x, y, flag, player) })
+}
+
+func smoothPath(path []Point, passable func(x, y int) bool) []Point {
 	if len(path) <= 2 {
 		return path
 	}
@@ -76,7 +96,7 @@ func SmoothPath(ng *NavGrid, path []Point, flag maplib.PassFlag) []Point {
 	for cur < len(path)-1 {
 		farthest := cur + 1
 		for i := len(path) - 1; i > cur+1; i-- {
-			if lineOfSight(ng, path[cur], path[i], flag) {
+			if lineOfSight(path[cur], path[i], passable) {
 				farthest = i
 				break
 			}
@@ -87,42 +107,10 @@ func SmoothPath(ng *NavGrid, path []Point, flag maplib.PassFlag) []Point {
 	return smooth
 }
 
-func lineOfSight(ng *NavGrid, a, b Point, flag maplib.PassFlag) bool {
-	dx := abs(b.X - a.X)
-	dy := abs(b.Y - a.Y)
-	sx, sy := 1, 1
-	if a.X > b.X {
-		sx = -1
-	}
-	if a.Y > b.Y {
-		sy = -1
-	}
-	err := dx - dy
-	x, y := a.X, a.Y
-	for {
-		if !ng.Passable(x, y, flag) {
-			return false
-		}
-		if x == b.X && y == b.Y {
-			return true
-		}
-		e2 := err * 2
-		if e2 > -dy {
-			err -= dy
-			x += sx
-		}
-		if e2 < dx {
-			err += dx
-			y += sy
-		}
-	}
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
+// lineOfSight reports whether every tile on the straight line from a to b
+// is passable
+func lineOfSight(a, b Point, passable func(x, y int) bool) bool {
+	return maplib.WalkLine(a.X, a.Y, b.X, b.Y, passable)
 }
 
 func heuristic(a, b Point) float64 {
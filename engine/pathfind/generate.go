@@ -0,0 +1,13 @@
+package pathfind
+
+import "github.com/1siamBot/rts-engine/engine/maplib"
+
+// GenerateMap generates a skirmish map whose start positions are checked
+// for a ground route with the same NavGrid and A* units path with, so
+// bridges and diagonal corner rules count the way they do in play
+func GenerateMap(opts maplib.GenOptions) (*maplib.TileMap, error) {
+	opts.Connected = func(tm *maplib.TileMap, sx, sy, gx, gy int) bool {
+		return FindPath(NewNavGrid(tm), sx, sy, gx, gy, maplib.PassVehicle) != nil
+	}
+	return maplib.GenerateMap(opts)
+}
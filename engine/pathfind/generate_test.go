@@ -0,0 +1,62 @@
+package pathfind
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestGenerateMapConnectsStarts(t *testing.T) {
+	tests := []struct {
+		players  int
+		size     int
+		symmetry maplib.Symmetry
+	}{
+		{2, 64, maplib.SymmetryRotational},
+		{2, 64, maplib.SymmetryMirror},
+		{4, 64, maplib.SymmetryRotational},
+		{4, 96, maplib.SymmetryMirror},
+	}
+	for _, tc := range tests {
+		for seed := int64(1); seed <= 8; seed++ {
+			name := fmt.Sprintf("%dp_%d_sym%d_seed%d", tc.players, tc.size, tc.symmetry, seed)
+			t.Run(name, func(t *testing.T) {
+				tm, err := GenerateMap(maplib.GenOptions{
+					Width: tc.size, Height: tc.size, Players: tc.players,
+					Symmetry: tc.symmetry, Seed: seed,
+				})
+				if err != nil {
+					t.Fatalf("GenerateMap: %v", err)
+				}
+				if len(tm.StartPositions) != tc.players {
+					t.Fatalf("got %d start positions, want %d", len(tm.StartPositions), tc.players)
+				}
+				ng := NewNavGrid(tm)
+				s0 := tm.StartPositions[0]
+				for _, s := range tm.StartPositions[1:] {
+					if FindPath(ng, s0.X, s0.Y, s.X, s.Y, maplib.PassVehicle) == nil {
+						t.Errorf("no vehicle path from start (%d,%d) to (%d,%d)", s0.X, s0.Y, s.X, s.Y)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestGenerateMapIsDeterministic(t *testing.T) {
+	opts := maplib.GenOptions{Width: 64, Height: 64, Players: 2, Seed: 42}
+	a, err := GenerateMap(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateMap(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range a.Tiles {
+		if a.Tiles[i].Terrain != b.Tiles[i].Terrain || a.Tiles[i].OreAmount != b.Tiles[i].OreAmount {
+			t.Fatalf("tile %d differs between runs with the same seed", i)
+		}
+	}
+}
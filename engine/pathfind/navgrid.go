@@ -7,15 +7,24 @@ type NavGrid struct {
 	Width, Height int
 	Costs         []float64 // movement cost per cell (0 = impassable)
 	passFlags     []maplib.PassFlag
+	revision      int // TileMap.Revision this grid was built from
+	footprints    int // TileMap.Footprints this grid was built from
+
+	// Friendly decides whether a gate owned by one player opens for another;
+	// nil opens gates for their owner only
+	Friendly func(owner, player int) bool
+	gates    map[int]int // cell index -> owning player
 }
 
 // NewNavGrid builds a navigation grid from a tile map
 func NewNavGrid(tm *maplib.TileMap) *NavGrid {
 	ng := &NavGrid{
-		Width:     tm.Width,
-		Height:    tm.Height,
-		Costs:     make([]float64, tm.Width*tm.Height),
-		passFlags: make([]maplib.PassFlag, tm.Width*tm.Height),
+		Width:      tm.Width,
+		Height:     tm.Height,
+		Costs:      make([]float64, tm.Width*tm.Height),
+		passFlags:  make([]maplib.PassFlag, tm.Width*tm.Height),
+		revision:   tm.Revision,
+		footprints: tm.Footprints,
 	}
 	for i, t := range tm.Tiles {
 		ng.passFlags[i] = t.Passable
@@ -29,6 +38,8 @@ func NewNavGrid(tm *maplib.TileMap) *NavGrid {
 				ng.Costs[i] = 1.5
 			case maplib.TerrainSand:
 				ng.Costs[i] = 1.3
+			case maplib.TerrainRamp:
+				ng.Costs[i] = 1.2
 			case maplib.TerrainRock:
 				ng.Costs[i] = 2.0
 			default:
@@ -69,7 +80,51 @@ func (ng *NavGrid) SetCost(x, y int, cost float64) {
 	}
 }
 
-// Refresh rebuilds the nav grid from a tile map
+// Refresh rebuilds the nav grid from a tile map, keeping its gates
 func (ng *NavGrid) Refresh(tm *maplib.TileMap) {
+	friendly, gates := ng.Friendly, ng.gates
 	*ng = *NewNavGrid(tm)
+	ng.Friendly, ng.gates = friendly, gates
+}
+
+// SetGate marks (x, y) as a gate that only its owner's side may pass
+func (ng *NavGrid) SetGate(x, y, owner int) {
+	if x < 0 || y < 0 || x >= ng.Width || y >= ng.Height {
+		return
+	}
+	if ng.gates == nil {
+		ng.gates = make(map[int]int)
+	}
+	ng.gates[y*ng.Width+x] = owner
+}
+
+// ClearGate removes a gate from (x, y)
+func (ng *NavGrid) ClearGate(x, y int) {
+	delete(ng.gates, y*ng.Width+x)
+}
+
+// GateOpensFor reports whether player may cross (x, y): true unless the cell
+// holds a gate belonging to someone unfriendly
+func (ng *NavGrid) GateOpensFor(x, y, player int) bool {
+	if x < 0 || y < 0 || x >= ng.Width || y >= ng.Height {
+		return true
+	}
+	owner, ok := ng.gates[y*ng.Width+x]
+	if !ok || owner == player {
+		return true
+	}
+	return ng.Friendly != nil && ng.Friendly(owner, player)
+}
+
+// PassableFor is Passable for a unit owned by player, with hostile gates shut
+func (ng *NavGrid) PassableFor(x, y int, flag maplib.PassFlag, player int) bool {
+	return ng.Passable(x, y, flag) && ng.GateOpensFor(x, y, player)
+}
+
+// Sync rebuilds the nav grid if the tile map's terrain or building
+// footprints changed since the last build
+func (ng *NavGrid) Sync(tm *maplib.TileMap) {
+	if ng.revision != tm.Revision || ng.footprints != tm.Footprints {
+		ng.Refresh(tm)
+	}
 }
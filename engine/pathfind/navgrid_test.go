@@ -0,0 +1,36 @@
+package pathfind
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestSyncBlocksFootprints(t *testing.T) {
+	tests := []struct {
+		name    string
+		change  func(tm *maplib.TileMap)
+		blocked bool // (4, 4) after Sync
+	}{
+		{"building placed", func(tm *maplib.TileMap) { tm.SetOccupied(4, 4, true) }, true},
+		{"building removed", func(tm *maplib.TileMap) {
+			tm.SetOccupied(4, 4, true)
+			tm.SetOccupied(4, 4, false)
+		}, false},
+		{"terrain changed elsewhere", func(tm *maplib.TileMap) {
+			tm.SetOccupied(4, 4, true)
+			tm.SetTerrain(10, 10, 10, 10, maplib.TerrainDirt)
+		}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := maplib.NewTileMap("t", 16, 16)
+			ng := NewNavGrid(tm)
+			tc.change(tm)
+			ng.Sync(tm)
+			if got := !ng.Passable(4, 4, maplib.PassVehicle); got != tc.blocked {
+				t.Errorf("(4, 4) blocked = %v, want %v", got, tc.blocked)
+			}
+		})
+	}
+}
@@ -82,6 +82,22 @@ func (c *Camera) CenterOn(wx, wy float64) {
 	c.clamp()
 }
 
+// EdgeDir returns the edge-scroll direction (-1, 0 or 1 per axis) for a
+// cursor at (mx, my): non-zero within EdgeSize pixels of a screen edge
+func (c *Camera) EdgeDir(mx, my int) (dx, dy int) {
+	if mx < c.EdgeSize {
+		dx = -1
+	} else if mx >= c.ScreenW-c.EdgeSize {
+		dx = 1
+	}
+	if my < c.EdgeSize {
+		dy = -1
+	} else if my >= c.ScreenH-c.EdgeSize {
+		dy = 1
+	}
+	return dx, dy
+}
+
 // WorldToScreen converts world iso position to screen pixel position
 func (c *Camera) WorldToScreen(wx, wy float64) (int, int) {
 	tw := float64(c.TileWidth)
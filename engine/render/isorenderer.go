@@ -2,7 +2,9 @@ package render
 
 import (
 	"image/color"
+	"sort"
 
+	"github.com/1siamBot/rts-engine/engine/core"
 	"github.com/1siamBot/rts-engine/engine/maplib"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
@@ -24,6 +26,8 @@ var TerrainColors = map[maplib.TerrainType]color.RGBA{
 	maplib.TerrainSnow:      {245, 245, 255, 255},    // white
 	maplib.TerrainUrban:     {192, 192, 192, 255},    // silver
 	maplib.TerrainForest:    {0, 100, 0, 255},        // dark green
+	maplib.TerrainRamp:      {160, 130, 90, 255},     // packed earth
+	maplib.TerrainBridgeWreck: {70, 55, 40, 255},     // charred timber
 }
 
 // IsoRenderer handles isometric map rendering
@@ -44,6 +48,62 @@ func NewIsoRenderer(screenW, screenH int) *IsoRenderer {
 	return r
 }
 
+// View returns the camera
+func (r *IsoRenderer) View() View { return r.Camera }
+
+// Project maps a tile position and height to the screen; each height level
+// lifts a point by a quarter tile
+func (r *IsoRenderer) Project(wx, wy, height float64) (int, int) {
+	sx, sy := r.Camera.WorldToScreen(wx, wy)
+	return sx, sy - int(height*float64(r.Camera.TileHeight)/4*r.Camera.Zoom)
+}
+
+// ScreenToWorld converts a screen pixel to tile coordinates
+func (r *IsoRenderer) ScreenToWorld(sx, sy int) (float64, float64) {
+	return r.Camera.ScreenToWorld(sx, sy)
+}
+
+// Resize matches the viewport to a new screen size
+func (r *IsoRenderer) Resize(w, h int) {
+	r.Camera.ScreenW, r.Camera.ScreenH = w, h
+}
+
+// DrawScene draws the map, then buildings and units back to front. Entities
+// without a sprite are drawn as a dot in their owner's colour.
+func (r *IsoRenderer) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, w *core.World, localPlayerID int) {
+	r.DrawMap(screen, tm)
+	if w == nil {
+		return
+	}
+	ids := w.Query(core.CompPosition, core.CompOwner)
+	sort.Slice(ids, func(i, j int) bool {
+		pi := w.Get(ids[i], core.CompPosition).(*core.Position)
+		pj := w.Get(ids[j], core.CompPosition).(*core.Position)
+		if di, dj := pi.X+pi.Y, pj.X+pj.Y; di != dj {
+			return di < dj
+		}
+		return ids[i] < ids[j]
+	})
+	for _, id := range ids {
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		own := w.Get(id, core.CompOwner).(*core.Owner)
+		sx, sy := r.Camera.WorldToScreen(pos.X, pos.Y)
+		drawn := false
+		if w.Has(id, core.CompBuilding) {
+			drawn = r.DrawBuildingSprite(screen, w, id, sx, sy)
+		} else if w.Has(id, core.CompMovable) {
+			drawn = r.DrawUnitSprite(screen, w, id, sx, sy, own.PlayerID)
+		}
+		if !drawn {
+			clr := color.RGBA{200, 60, 60, 255}
+			if own.PlayerID == localPlayerID {
+				clr = color.RGBA{60, 120, 220, 255}
+			}
+			vector.DrawFilledCircle(screen, float32(sx), float32(sy), float32(4*r.Camera.Zoom), clr, false)
+		}
+	}
+}
+
 // GetTileImage returns (or creates) a cached tile image for a terrain type (default variant)
 func (r *IsoRenderer) GetTileImage(terrain maplib.TerrainType, tw, th int) *ebiten.Image {
 	if img, ok := r.TileCache[terrain]; ok {
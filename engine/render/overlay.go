@@ -0,0 +1,40 @@
+package render
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Screen-space overlays drawn over either renderer's scene
+
+// DrawHealthBar draws a health bar centred above screen position (sx, sy)
+func DrawHealthBar(screen *ebiten.Image, sx, sy int, ratio float64, width int) {
+	barH := float32(4)
+	barW := float32(width)
+	bx := float32(sx) - barW/2
+	by := float32(sy) - 5
+
+	// Background
+	vector.DrawFilledRect(screen, bx, by, barW, barH, color.RGBA{40, 40, 40, 200}, false)
+
+	// Health fill
+	var hc color.RGBA
+	if ratio > 0.6 {
+		hc = color.RGBA{0, 200, 0, 255}
+	} else if ratio > 0.3 {
+		hc = color.RGBA{255, 200, 0, 255}
+	} else {
+		hc = color.RGBA{255, 0, 0, 255}
+	}
+	vector.DrawFilledRect(screen, bx, by, barW*float32(ratio), barH, hc, false)
+}
+
+// DrawTeamPip draws a small team-color square at the left end of a health bar
+func DrawTeamPip(screen *ebiten.Image, sx, sy, width int, clr color.RGBA) {
+	bx := float32(sx) - float32(width)/2 - 7
+	by := float32(sy) - 6
+	vector.DrawFilledRect(screen, bx, by, 6, 6, color.RGBA{0, 0, 0, 200}, false)
+	vector.DrawFilledRect(screen, bx+1, by+1, 4, 4, clr, false)
+}
@@ -0,0 +1,35 @@
+package render
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// View is the camera control every renderer offers. Positions are in tile
+// coordinates on the ground plane.
+type View interface {
+	Pan(dx, dy float64)
+	ZoomAt(delta float64, screenX, screenY int)
+	CenterOn(wx, wy float64)
+	WorldToScreen(wx, wy float64) (int, int)
+	ScreenToWorld(sx, sy int) (float64, float64)
+	VisibleTileRange(mapW, mapH int) (minX, minY, maxX, maxY int)
+	// EdgeDir is the edge-scroll direction for a cursor near the screen edge
+	EdgeDir(mx, my int) (dx, dy int)
+}
+
+// Renderer draws a map and the entities on it. IsoRenderer (2D sprites) and
+// render3d.Renderer3D both implement it, so a front end can pick either.
+type Renderer interface {
+	View() View
+	// Project maps a tile position at a height above the ground to the screen
+	Project(wx, wy, height float64) (int, int)
+	ScreenToWorld(sx, sy int) (float64, float64)
+	DrawScene(screen *ebiten.Image, tm *maplib.TileMap, w *core.World, localPlayerID int)
+	DrawGrid(screen *ebiten.Image, tm *maplib.TileMap)
+	DrawSelectionBox(screen *ebiten.Image, x1, y1, x2, y2 int)
+	Resize(w, h int)
+}
+
+var _ Renderer = (*IsoRenderer)(nil)
@@ -0,0 +1,36 @@
+package render3d
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const rainStreaks = 120
+
+// drawAtmosphere tints the scene for the time of day and draws the weather
+func (r *Renderer3D) drawAtmosphere(screen *ebiten.Image) {
+	w, h := float32(r.Camera.ScreenW), float32(r.Camera.ScreenH)
+	if r.Darkness > 0 {
+		vector.DrawFilledRect(screen, 0, 0, w, h, color.RGBA{4, 8, 30, uint8(150 * r.Darkness)}, false)
+	}
+
+	switch r.Weather {
+	case maplib.WeatherFog:
+		vector.DrawFilledRect(screen, 0, 0, w, h, color.RGBA{150, 155, 160, 80}, false)
+	case maplib.WeatherRain:
+		vector.DrawFilledRect(screen, 0, 0, w, h, color.RGBA{20, 24, 32, 50}, false)
+		streak := color.RGBA{150, 170, 200, 90}
+		for i := 0; i < rainStreaks; i++ {
+			// Each streak falls on its own column and phase, wrapping at the bottom
+			seed := float64(i) * 12.9898
+			x := float32(math.Mod(math.Abs(math.Sin(seed))*43758.5453, 1)) * w
+			phase := math.Mod(math.Abs(math.Cos(seed))*9631.17, 1)
+			y := float32(math.Mod(phase+r.time*1.6, 1))*(h+40) - 20
+			vector.StrokeLine(screen, x, y, x-4, y+16, 1, streak, false)
+		}
+	}
+}
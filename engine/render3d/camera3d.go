@@ -32,6 +32,7 @@ type Camera3D struct {
 
 	// Map bounds for clamping (0 = unclamped)
 	MapWidth, MapHeight int
+	BoundsMargin        float64 // tiles of void allowed past the map edge
 }
 
 const (
@@ -40,6 +41,8 @@ const (
 	ZoomDefault = 38.0 // ~25-30 tiles visible across, RA2-like
 	ZoomMin     = 15.0 // closest — see unit details (~10-12 tiles)
 	ZoomMax     = 60.0 // farthest — large area overview (~45-50 tiles)
+
+	DefaultBoundsMargin = 2.0
 )
 
 // NewCamera3D creates an isometric camera with RA2-like defaults
@@ -54,14 +57,57 @@ func NewCamera3D(screenW, screenH int) *Camera3D {
 		EdgeScroll: true,
 		EdgeSize:   20,
 		dirty:      true,
+
+		BoundsMargin: DefaultBoundsMargin,
 	}
 	return c
 }
 
+// Resize sets the screen size the projection fills
+func (c *Camera3D) Resize(w, h int) {
+	c.ScreenW, c.ScreenH = w, h
+	c.dirty = true
+}
+
+// EdgeDir returns the edge-scroll direction (-1, 0 or 1 per axis) for a
+// cursor at (mx, my): non-zero within EdgeSize pixels of a screen edge
+func (c *Camera3D) EdgeDir(mx, my int) (dx, dy int) {
+	if mx < c.EdgeSize {
+		dx = -1
+	} else if mx >= c.ScreenW-c.EdgeSize {
+		dx = 1
+	}
+	if my < c.EdgeSize {
+		dy = -1
+	} else if my >= c.ScreenH-c.EdgeSize {
+		dy = 1
+	}
+	return dx, dy
+}
+
 // SetMapSize stores map dimensions for camera clamping
 func (c *Camera3D) SetMapSize(w, h int) {
 	c.MapWidth = w
 	c.MapHeight = h
+	c.zoomTarget = c.clampZoom(c.zoomTarget)
+	c.Zoom = c.clampZoom(c.Zoom)
+	c.dirty = true
+	c.clampTarget()
+}
+
+// MaxZoom returns the farthest zoom allowed; on small maps it is limited
+// so the whole screen width never spans more than the map plus margins.
+func (c *Camera3D) MaxZoom() float64 {
+	if c.MapWidth <= 0 || c.MapHeight <= 0 {
+		return ZoomMax
+	}
+	mw, mh := float64(c.MapWidth), float64(c.MapHeight)
+	fit := math.Sqrt(mw*mw+mh*mh) + 2*c.BoundsMargin
+	return math.Max(ZoomMin, math.Min(ZoomMax, fit))
+}
+
+func (c *Camera3D) clampZoom(z float64) float64 {
+	return math.Max(ZoomMin, math.Min(c.MaxZoom(), z))
 }
 
 // CenterOn centers camera on world position (clamped to map)
@@ -91,8 +137,7 @@ func (c *Camera3D) ZoomAt(delta float64, screenX, screenY int) {
 	// Smooth zoom factor (3% per scroll notch)
 	factor := 1.0 - delta*0.03
 	factor = math.Max(0.85, math.Min(1.15, factor)) // clamp per-frame change
-	c.zoomTarget *= factor
-	c.zoomTarget = math.Max(ZoomMin, math.Min(ZoomMax, c.zoomTarget))
+	c.zoomTarget = c.clampZoom(c.zoomTarget * factor)
 
 	// Apply zoom (interpolated toward target for smoothness)
 	c.Zoom = c.zoomTarget
@@ -111,43 +156,47 @@ func (c *Camera3D) SmoothUpdate(dt float64) {
 	if math.Abs(c.Zoom-c.zoomTarget) > 0.01 {
 		t := 1.0 - math.Exp(-10.0*dt) // exponential ease
 		c.Zoom += (c.zoomTarget - c.Zoom) * t
-		c.Zoom = math.Max(ZoomMin, math.Min(ZoomMax, c.Zoom))
+		c.Zoom = c.clampZoom(c.Zoom)
 		c.dirty = true
+		c.clampTarget() // zooming out changes how much of the map is visible
 	}
 }
 
-// clampTarget keeps the camera within map boundaries
+// clampTarget keeps the camera within map boundaries. The midpoints of the
+// four screen edges are projected onto the ground and must stay within
+// BoundsMargin tiles of the map, so the view never drifts into empty void;
+// if the map is narrower than the view along an axis it is centred instead.
 func (c *Camera3D) clampTarget() {
 	if c.MapWidth <= 0 || c.MapHeight <= 0 {
 		return
 	}
 	mw := float64(c.MapWidth)
 	mh := float64(c.MapHeight)
+	m := c.BoundsMargin
 
-	// Visible half-extents in world space
-	aspect := float64(c.ScreenW) / float64(c.ScreenH)
-	halfW := c.Zoom / 2
-	halfH := halfW / aspect
-	// The isometric view means the visible area on XZ is rotated.
-	// Use a conservative margin based on max of halfW, halfH.
-	margin := math.Max(halfW, halfH) * 0.6
-
-	minX := margin
-	minY := margin
-	maxX := mw - margin
-	maxY := mh - margin
-
-	// If map is smaller than view, center it
-	if maxX < minX {
-		c.TargetX = mw / 2
-	} else {
-		c.TargetX = math.Max(minX, math.Min(maxX, c.TargetX))
+	c.dirty = true
+	c.update()
+	minOffX, minOffY := math.MaxFloat64, math.MaxFloat64
+	maxOffX, maxOffY := -math.MaxFloat64, -math.MaxFloat64
+	mids := [][2]int{{c.ScreenW / 2, 0}, {c.ScreenW / 2, c.ScreenH}, {0, c.ScreenH / 2}, {c.ScreenW, c.ScreenH / 2}}
+	for _, p := range mids {
+		wx, wy := c.ScreenToWorld(p[0], p[1])
+		ox, oy := wx-c.TargetX, wy-c.TargetY
+		minOffX, maxOffX = math.Min(minOffX, ox), math.Max(maxOffX, ox)
+		minOffY, maxOffY = math.Min(minOffY, oy), math.Max(maxOffY, oy)
 	}
-	if maxY < minY {
-		c.TargetY = mh / 2
-	} else {
-		c.TargetY = math.Max(minY, math.Min(maxY, c.TargetY))
+
+	c.TargetX = clampCentered(c.TargetX, -m-minOffX, mw+m-maxOffX, mw/2)
+	c.TargetY = clampCentered(c.TargetY, -m-minOffY, mh+m-maxOffY, mh/2)
+	c.dirty = true
+}
+
+// clampCentered clamps v to [lo, hi], or returns mid if the range is empty
+func clampCentered(v, lo, hi, mid float64) float64 {
+	if hi < lo {
+		return mid
 	}
+	return math.Max(lo, math.Min(hi, v))
 }
 
 func (c *Camera3D) update() {
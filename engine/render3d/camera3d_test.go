@@ -0,0 +1,141 @@
+package render3d
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEdgeDir(t *testing.T) {
+	c := NewCamera3D(800, 600)
+	c.EdgeSize = 20
+	tests := []struct {
+		name   string
+		mx, my int
+		dx, dy int
+	}{
+		{"centre", 400, 300, 0, 0},
+		{"left", 0, 300, -1, 0},
+		{"right edge", 799, 300, 1, 0},
+		{"just inside right band", 780, 300, 1, 0},
+		{"just outside right band", 779, 300, 0, 0},
+		{"top", 400, 19, 0, -1},
+		{"bottom", 400, 599, 0, 1},
+		{"top-right corner", 790, 5, 1, -1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dx, dy := c.EdgeDir(tc.mx, tc.my)
+			if dx != tc.dx || dy != tc.dy {
+				t.Errorf("EdgeDir(%d, %d) = (%d, %d), want (%d, %d)", tc.mx, tc.my, dx, dy, tc.dx, tc.dy)
+			}
+		})
+	}
+}
+
+// edgeExtents projects the midpoints of the screen edges onto the ground
+// and returns the ground bounding box they span
+func edgeExtents(c *Camera3D) (minX, minY, maxX, maxY float64) {
+	minX, minY = math.MaxFloat64, math.MaxFloat64
+	maxX, maxY = -math.MaxFloat64, -math.MaxFloat64
+	mids := [][2]int{{c.ScreenW / 2, 0}, {c.ScreenW / 2, c.ScreenH}, {0, c.ScreenH / 2}, {c.ScreenW, c.ScreenH / 2}}
+	for _, p := range mids {
+		wx, wy := c.ScreenToWorld(p[0], p[1])
+		minX, maxX = math.Min(minX, wx), math.Max(maxX, wx)
+		minY, maxY = math.Min(minY, wy), math.Max(maxY, wy)
+	}
+	return
+}
+
+func TestPanStopsAtMapEdge(t *testing.T) {
+	const eps = 1e-6
+	tests := []struct {
+		name   string
+		dx, dy float64
+	}{
+		{"left", -1, 0},
+		{"right", 1, 0},
+		{"up", 0, -1},
+		{"down", 0, 1},
+		{"up-left", -1, -1},
+		{"down-right", 1, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCamera3D(1280, 720)
+			c.SetMapSize(64, 64)
+			c.CenterOn(32, 32)
+			for i := 0; i < 500; i++ {
+				c.Pan(tc.dx*50, tc.dy*50)
+			}
+			tx, ty := c.TargetX, c.TargetY
+			minX, minY, maxX, maxY := edgeExtents(c)
+			m := c.BoundsMargin
+			if minX < -m-eps || minY < -m-eps || maxX > 64+m+eps || maxY > 64+m+eps {
+				t.Errorf("view spans (%.2f,%.2f)-(%.2f,%.2f), beyond the map plus %.0f tiles", minX, minY, maxX, maxY, m)
+			}
+			c.Pan(tc.dx*50, tc.dy*50)
+			if math.Abs(c.TargetX-tx) > eps || math.Abs(c.TargetY-ty) > eps {
+				t.Errorf("panning past the clamp moved the target from (%.3f,%.3f) to (%.3f,%.3f)", tx, ty, c.TargetX, c.TargetY)
+			}
+		})
+	}
+}
+
+func TestClampCentersSmallMap(t *testing.T) {
+	c := NewCamera3D(1280, 720)
+	c.SetMapSize(6, 4)
+	c.CenterOn(100, -100)
+	if math.Abs(c.TargetX-3) > 1e-9 || math.Abs(c.TargetY-2) > 1e-9 {
+		t.Errorf("target = (%.3f, %.3f), want map centre (3, 2)", c.TargetX, c.TargetY)
+	}
+}
+
+func TestClampUnboundedWithoutMap(t *testing.T) {
+	c := NewCamera3D(1280, 720)
+	c.CenterOn(-500, 900)
+	if c.TargetX != -500 || c.TargetY != 900 {
+		t.Errorf("target = (%.1f, %.1f), want (-500, 900) with no map size set", c.TargetX, c.TargetY)
+	}
+}
+
+func TestZoomClamped(t *testing.T) {
+	tests := []struct {
+		name  string
+		w, h  int
+		delta float64
+		want  float64
+	}{
+		{"zoom in stops at min", 128, 128, 10, ZoomMin},
+		{"zoom out stops at max", 128, 128, -10, ZoomMax},
+		{"small map limits zoom out", 10, 10, -10, math.Sqrt(200) + 2*DefaultBoundsMargin},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewCamera3D(1280, 720)
+			c.SetMapSize(tc.w, tc.h)
+			c.CenterOn(float64(tc.w)/2, float64(tc.h)/2)
+			for i := 0; i < 200; i++ {
+				c.ZoomAt(tc.delta, 640, 360)
+			}
+			if math.Abs(c.Zoom-tc.want) > 1e-9 {
+				t.Errorf("Zoom = %.3f, want %.3f", c.Zoom, tc.want)
+			}
+		})
+	}
+}
+
+func TestClampCentered(t *testing.T) {
+	tests := []struct {
+		v, lo, hi, mid, want float64
+	}{
+		{5, 0, 10, 99, 5},
+		{-3, 0, 10, 99, 0},
+		{12, 0, 10, 99, 10},
+		{5, 10, 0, 7, 7},
+	}
+	for _, tc := range tests {
+		if got := clampCentered(tc.v, tc.lo, tc.hi, tc.mid); got != tc.want {
+			t.Errorf("clampCentered(%v, %v, %v, %v) = %v, want %v", tc.v, tc.lo, tc.hi, tc.mid, got, tc.want)
+		}
+	}
+}
@@ -65,6 +65,23 @@ func (ps *ParticleSystem) AddMuzzleFlash(wx, wy, wz float64) {
 	})
 }
 
+// AddWarp spawns a column of blue sparks where a unit teleports in or out
+func (ps *ParticleSystem) AddWarp(wx, wz float64) {
+	for i := 0; i < 24; i++ {
+		angle := float64(i) / 24.0 * 2 * math.Pi
+		r := 0.3 + float64(i%3)*0.1
+		ps.Particles = append(ps.Particles, Particle{
+			Pos:     V3(wx+math.Cos(angle)*r, 0.1, wz+math.Sin(angle)*r),
+			Vel:     V3(-math.Cos(angle)*0.3, 2.5+float64(i%4)*0.4, -math.Sin(angle)*0.3),
+			Color:   Color3{0.4, 0.7 + float64(i%3)*0.1, 1.0},
+			Alpha:   1.0,
+			Size:    0.12,
+			Life:    0,
+			MaxLife: 0.6 + float64(i%4)*0.1,
+		})
+	}
+}
+
 // Update advances particles
 func (ps *ParticleSystem) Update(dt float64) {
 	alive := ps.Particles[:0]
@@ -242,6 +242,65 @@ func MakeRefinery(faction string) *Mesh3D {
 
 // --- Unit Models ---
 
+// Wall neighbour bits used to pick connected wall pieces
+const (
+	WallN = 1 << iota // -Y
+	WallE             // +X
+	WallS             // +Y
+	WallW             // -X
+)
+
+// MakeWallModel builds a wall post with a segment reaching toward each
+// connected neighbour in mask, so adjacent walls join into one run.
+func MakeWallModel(faction string, mask int) *Mesh3D {
+	fc := FactionColor(faction)
+	m := NewMesh()
+
+	post := MakeBox(0.42, 0.6, 0.42, concreteDark)
+	m.Append(post.Transform(Mat4Translate(0, 0.3, 0)))
+	top := MakeBox(0.46, 0.06, 0.46, fc)
+	m.Append(top.Transform(Mat4Translate(0, 0.63, 0)))
+
+	arm := MakeBox(0.32, 0.5, 0.5, concreteColor)
+	if mask&WallN != 0 {
+		m.Append(arm.Transform(Mat4Translate(0, 0.25, -0.25)))
+	}
+	if mask&WallS != 0 {
+		m.Append(arm.Transform(Mat4Translate(0, 0.25, 0.25)))
+	}
+	armX := MakeBox(0.5, 0.5, 0.32, concreteColor)
+	if mask&WallE != 0 {
+		m.Append(armX.Transform(Mat4Translate(0.25, 0.25, 0)))
+	}
+	if mask&WallW != 0 {
+		m.Append(armX.Transform(Mat4Translate(-0.25, 0.25, 0)))
+	}
+	return m
+}
+
+// MakeGateModel builds a gate between two posts. The panel runs along X
+// (else along Z) and sinks into the ground as open goes from 0 to 1.
+func MakeGateModel(faction string, alongX bool, open float64) *Mesh3D {
+	fc := FactionColor(faction)
+	m := NewMesh()
+
+	panelClr := Color3{fc.R * 0.7, fc.G * 0.7, fc.B * 0.7}
+	post := MakeBox(0.16, 0.7, 0.16, concreteDark)
+	py := 0.25 - 0.45*open
+	if alongX {
+		m.Append(post.Transform(Mat4Translate(-0.42, 0.35, 0)))
+		m.Append(post.Transform(Mat4Translate(0.42, 0.35, 0)))
+		m.Append(MakeBox(0.7, 0.45, 0.12, panelClr).Transform(Mat4Translate(0, py, 0)))
+		m.Append(MakeBox(0.7, 0.05, 0.13, fc).Transform(Mat4Translate(0, py+0.15, 0)))
+	} else {
+		m.Append(post.Transform(Mat4Translate(0, 0.35, -0.42)))
+		m.Append(post.Transform(Mat4Translate(0, 0.35, 0.42)))
+		m.Append(MakeBox(0.12, 0.45, 0.7, panelClr).Transform(Mat4Translate(0, py, 0)))
+		m.Append(MakeBox(0.13, 0.05, 0.7, fc).Transform(Mat4Translate(0, py+0.15, 0)))
+	}
+	return m
+}
+
 func MakeTankModel(faction string) *Mesh3D {
 	fc := FactionColor(faction)
 	m := NewMesh()
@@ -6,9 +6,11 @@ import (
 	"math"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/1siamBot/rts-engine/engine/core"
 	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/render"
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
@@ -21,6 +23,14 @@ type Renderer3D struct {
 	Sprites   *SpriteAtlas // RA2-style sprite billboards (optional)
 	TerrainTex *TerrainTextureAtlas // RA2-style terrain textures
 
+	// Atmosphere, set by the game each frame
+	Darkness float64        // 0 = full day, 1 = midnight
+	Weather  maplib.Weather // rain streaks or a fog haze over the scene
+
+	// Explored reports tiles the local player has uncovered; crates under
+	// shroud aren't drawn. Nil shows everything.
+	Explored func(x, y int) bool
+
 	// Internal
 	whiteImg *ebiten.Image
 	time     float64
@@ -32,12 +42,25 @@ type Renderer3D struct {
 
 	// Terrain cache (fallback for untextured)
 	terrainCache      *Mesh3D
-	terrainCacheKey   string // "minX,minY,maxX,maxY"
+	terrainCacheKey   string // "minX,minY,maxX,maxY,revision"
 	waterCache        *Mesh3D
 	waterCacheKey     string
 	waterCacheTime    float64
+
+	// Profiling
+	Stats     FrameStats
+	drawCalls int
+}
+
+// FrameStats records the cost of the last DrawScene call
+type FrameStats struct {
+	DrawCalls int
+	SceneTime time.Duration
 }
 
+// PoweredDownShade darkens buildings that have been switched off
+const PoweredDownShade = 0.45
+
 // NewRenderer3D creates the 3D renderer
 func NewRenderer3D(screenW, screenH int) *Renderer3D {
 	r := &Renderer3D{
@@ -63,6 +86,27 @@ func NewRenderer3D(screenW, screenH int) *Renderer3D {
 	return r
 }
 
+var _ render.Renderer = (*Renderer3D)(nil)
+
+// View returns the camera
+func (r *Renderer3D) View() render.View { return r.Camera }
+
+// Project maps a tile position at a height above the ground to the screen
+func (r *Renderer3D) Project(wx, wy, height float64) (int, int) {
+	sx, sy, _ := r.Camera.Project3DToScreen(wx, height, wy)
+	return sx, sy
+}
+
+// ScreenToWorld converts a screen pixel to tile coordinates on the ground
+func (r *Renderer3D) ScreenToWorld(sx, sy int) (float64, float64) {
+	return r.Camera.ScreenToWorld(sx, sy)
+}
+
+// Resize matches the camera to a new screen size
+func (r *Renderer3D) Resize(w, h int) {
+	r.Camera.Resize(w, h)
+}
+
 // Update advances time-based effects
 func (r *Renderer3D) Update(dt float64) {
 	r.time += dt
@@ -96,6 +140,13 @@ func (r *Renderer3D) DrawSkyGradient(screen *ebiten.Image) {
 
 // DrawScene renders the complete 3D scene
 func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *core.World, localPlayerID int) {
+	start := time.Now()
+	r.drawCalls, r.TerrainTex.DrawCalls, r.Sprites.DrawCalls = 0, 0, 0
+	defer func() {
+		r.Stats.DrawCalls = r.drawCalls + r.TerrainTex.DrawCalls + r.Sprites.DrawCalls
+		r.Stats.SceneTime = time.Since(start)
+	}()
+
 	// 0. Sky gradient background
 	r.DrawSkyGradient(screen)
 
@@ -109,7 +160,7 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 		r.TerrainTex.RenderTreeBillboards(screen, r.Camera, tm, r.Sprites, minX, minY, maxX, maxY)
 	} else {
 		// Fallback: colored mesh terrain
-		cacheKey := fmt.Sprintf("%d,%d,%d,%d", minX, minY, maxX, maxY)
+		cacheKey := fmt.Sprintf("%d,%d,%d,%d,%d", minX, minY, maxX, maxY, tm.Revision)
 		if r.terrainCache == nil || r.terrainCacheKey != cacheKey {
 			r.terrainCache = GenerateTerrainMeshStatic(tm, minX, minY, maxX, maxY)
 			r.terrainCacheKey = cacheKey
@@ -136,9 +187,12 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 	type spriteDraw struct {
 		sprite *ebiten.Image
 		wx, wy, wz, scale, depth float64
+		dim    bool
 	}
 	var spriteDraws []spriteDraw
 
+	walls := wallOwners(world)
+
 	for _, id := range world.Query(core.CompBuilding, core.CompPosition, core.CompOwner) {
 		pos := world.Get(id, core.CompPosition).(*core.Position)
 		own := world.Get(id, core.CompOwner).(*core.Owner)
@@ -149,16 +203,48 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 			buildingKey = bn.(*core.BuildingName).Key
 		}
 
+		if buildingKey == "wall" {
+			tx, ty := int(pos.X), int(pos.Y)
+			mask := wallMask(walls, tx, ty, own.PlayerID)
+			gy := GroundHeight(tm, pos.X+0.5, pos.Y+0.5)
+			_, _, depth := r.Camera.Project3DToScreen(pos.X+0.5, gy, pos.Y+0.5)
+			if r.Sprites.IsLoaded() {
+				if spr := r.Sprites.GetWallSprite(mask, own.Faction); spr != nil {
+					spriteDraws = append(spriteDraws, spriteDraw{
+						sprite: spr, wx: pos.X + 0.5, wy: gy + 0.1, wz: pos.Y + 0.5,
+						scale: 1.0, depth: depth,
+					})
+					continue
+				}
+			}
+			mesh := r.getWallMesh(own.Faction, mask)
+			entities = append(entities, entityDraw{mesh: mesh.Transform(Mat4Translate(pos.X+0.5, gy, pos.Y+0.5)), depth: depth})
+			continue
+		}
+
+		if g := world.Get(id, core.CompGate); g != nil {
+			// The gate runs along the wall it sits in
+			mask := wallMask(walls, int(pos.X), int(pos.Y), own.PlayerID)
+			alongX := mask&(WallE|WallW) != 0 || mask == 0
+			gy := GroundHeight(tm, pos.X+0.5, pos.Y+0.5)
+			_, _, depth := r.Camera.Project3DToScreen(pos.X+0.5, gy, pos.Y+0.5)
+			mesh := r.getGateMesh(own.Faction, alongX, g.(*core.Gate).Open)
+			entities = append(entities, entityDraw{mesh: mesh.Transform(Mat4Translate(pos.X+0.5, gy, pos.Y+0.5)), depth: depth})
+			continue
+		}
+
 		cx := pos.X + float64(bldg.SizeX)/2.0
 		cz := pos.Y + float64(bldg.SizeY)/2.0
+		gy := GroundHeight(tm, pos.X, pos.Y)
 
 		// Try sprite billboard first
 		if r.Sprites.IsLoaded() {
 			if spr := r.Sprites.GetBuildingSprite(buildingKey, own.Faction); spr != nil {
-				_, _, depth := r.Camera.Project3DToScreen(cx, 0, cz)
+				_, _, depth := r.Camera.Project3DToScreen(cx, gy, cz)
+				xz, _ := deployScale(world, id)
 				spriteDraws = append(spriteDraws, spriteDraw{
-					sprite: spr, wx: cx, wy: 0.1, wz: cz,
-					scale: float64(bldg.SizeX) * 1.8, depth: depth,
+					sprite: spr, wx: cx, wy: gy + 0.1, wz: cz,
+					scale: float64(bldg.SizeX) * 1.8 * xz, depth: depth, dim: bldg.PoweredDown,
 				})
 				continue
 			}
@@ -171,7 +257,8 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 			mesh = MakeBox(float64(bldg.SizeX)*0.8, 0.8, float64(bldg.SizeY)*0.8, fc)
 		}
 
-		placed := mesh.Transform(Mat4Translate(cx, 0, cz))
+		xz, sy := deployScale(world, id)
+		placed := mesh.Transform(Mat4Translate(cx, gy, cz).Mul(Mat4Scale(xz, sy, xz)))
 
 		// Damage tint
 		if h := world.Get(id, core.CompHealth); h != nil {
@@ -188,7 +275,19 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 			}
 		}
 
-		_, _, depth := r.Camera.Project3DToScreen(cx, 0, cz)
+		// Powered-down buildings are drawn dark
+		if bldg.PoweredDown {
+			for i := range placed.Triangles {
+				for j := 0; j < 3; j++ {
+					c := &placed.Triangles[i].V[j].Color
+					c.R *= PoweredDownShade
+					c.G *= PoweredDownShade
+					c.B *= PoweredDownShade
+				}
+			}
+		}
+
+		_, _, depth := r.Camera.Project3DToScreen(cx, gy, cz)
 		entities = append(entities, entityDraw{mesh: placed, depth: depth})
 	}
 
@@ -199,12 +298,13 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 		}
 		pos := world.Get(id, core.CompPosition).(*core.Position)
 		own := world.Get(id, core.CompOwner).(*core.Owner)
+		wy := pos.Z + GroundHeight(tm, pos.X, pos.Y)
 
 		// Try sprite billboard for units
 		if r.Sprites.IsLoaded() {
 			unitType := r.getUnitType(world, id)
 			if spr := r.Sprites.GetUnitSprite(unitType, own.Faction); spr != nil {
-				_, _, depth := r.Camera.Project3DToScreen(pos.X, pos.Z, pos.Y)
+				_, _, depth := r.Camera.Project3DToScreen(pos.X, wy, pos.Y)
 				// Scale: MCV/harvester ~2.5 tiles, tanks ~1.8, infantry ~1.0
 				unitScale := 1.0
 				switch unitType {
@@ -217,9 +317,10 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 				case "infantry":
 					unitScale = 1.5
 				}
+				xz, _ := deployScale(world, id)
 				spriteDraws = append(spriteDraws, spriteDraw{
-					sprite: spr, wx: pos.X, wy: pos.Z + 0.1, wz: pos.Y,
-					scale: unitScale, depth: depth,
+					sprite: spr, wx: pos.X, wy: wy + 0.1, wz: pos.Y,
+					scale: unitScale * xz, depth: depth,
 				})
 				continue
 			}
@@ -232,9 +333,36 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 
 		// Rotate to facing direction
 		rotated := RotateModelY(mesh, -pos.Facing)
-		placed := rotated.Transform(Mat4Translate(pos.X, pos.Z, pos.Y))
+		xz, sy := deployScale(world, id)
+		placed := rotated.Transform(Mat4Translate(pos.X, wy, pos.Y).Mul(Mat4Scale(xz, sy, xz)))
 
-		_, _, depth := r.Camera.Project3DToScreen(pos.X, pos.Z, pos.Y)
+		_, _, depth := r.Camera.Project3DToScreen(pos.X, wy, pos.Y)
+		entities = append(entities, entityDraw{mesh: placed, depth: depth})
+	}
+
+	// Crates: small bobbing boxes
+	for _, id := range world.Query(core.CompCrate, core.CompPosition) {
+		pos := world.Get(id, core.CompPosition).(*core.Position)
+		if r.Explored != nil && !r.Explored(int(pos.X), int(pos.Y)) {
+			continue
+		}
+		wy := GroundHeight(tm, pos.X, pos.Y) + 0.25 + 0.05*math.Sin(r.time*3+pos.X)
+		box := MakeBox(0.45, 0.45, 0.45, Color3{0.85, 0.7, 0.25})
+		placed := RotateModelY(box, r.time).Transform(Mat4Translate(pos.X, wy, pos.Y))
+		_, _, depth := r.Camera.Project3DToScreen(pos.X, wy, pos.Y)
+		entities = append(entities, entityDraw{mesh: placed, depth: depth})
+	}
+
+	// Delivery carriers flying in and out
+	for _, id := range world.Query(core.CompDelivery, core.CompPosition, core.CompOwner) {
+		pos := world.Get(id, core.CompPosition).(*core.Position)
+		own := world.Get(id, core.CompOwner).(*core.Owner)
+		wy := pos.Z + GroundHeight(tm, pos.X, pos.Y)
+		hull := MakeBox(1.6, 0.3, 0.5, FactionColor(own.Faction))
+		wings := MakeBox(0.4, 0.08, 1.8, Color3{0.45, 0.47, 0.5})
+		hull.Triangles = append(hull.Triangles, wings.Triangles...)
+		placed := RotateModelY(hull, -pos.Facing).Transform(Mat4Translate(pos.X, wy, pos.Y))
+		_, _, depth := r.Camera.Project3DToScreen(pos.X, wy, pos.Y)
 		entities = append(entities, entityDraw{mesh: placed, depth: depth})
 	}
 
@@ -252,18 +380,25 @@ func (r *Renderer3D) DrawScene(screen *ebiten.Image, tm *maplib.TileMap, world *
 		return spriteDraws[i].depth > spriteDraws[j].depth
 	})
 	for _, sd := range spriteDraws {
-		r.Sprites.DrawBillboard(screen, r.Camera, sd.sprite, sd.wx, sd.wy, sd.wz, sd.scale)
+		shade := 1.0
+		if sd.dim {
+			shade = PoweredDownShade
+		}
+		r.Sprites.DrawBillboardShaded(screen, r.Camera, sd.sprite, sd.wx, sd.wy, sd.wz, sd.scale, shade)
 	}
 
 	// 3. Projectiles
-	r.drawProjectiles3D(screen, world)
+	r.drawProjectiles3D(screen, tm, world)
 
 	// 4. Particles
 	particleMesh := r.Particles.GenerateParticleMeshes()
 	r.renderMesh(screen, particleMesh)
 
-	// 5. Selection circles
-	r.drawSelectionCircles(screen, world, localPlayerID)
+	// 5. Night tint and weather, under the selection circles so those stay readable
+	r.drawAtmosphere(screen)
+
+	// 6. Selection circles
+	r.drawSelectionCircles(screen, tm, world, localPlayerID)
 }
 
 func (r *Renderer3D) getBuildingMesh(key, faction string) *Mesh3D {
@@ -292,6 +427,67 @@ func (r *Renderer3D) getBuildingMesh(key, faction string) *Mesh3D {
 	return m
 }
 
+func (r *Renderer3D) getWallMesh(faction string, mask int) *Mesh3D {
+	cacheKey := fmt.Sprintf("wall%d_%s", mask, faction)
+	if m, ok := r.buildingModels[cacheKey]; ok {
+		return m
+	}
+	m := MakeWallModel(faction, mask)
+	r.buildingModels[cacheKey] = m
+	return m
+}
+
+// getGateMesh returns a cached gate model, its opening quantised to tenths
+func (r *Renderer3D) getGateMesh(faction string, alongX bool, open float64) *Mesh3D {
+	step := int(open*10 + 0.5)
+	cacheKey := fmt.Sprintf("gate%d_%t_%s", step, alongX, faction)
+	if m, ok := r.buildingModels[cacheKey]; ok {
+		return m
+	}
+	m := MakeGateModel(faction, alongX, float64(step)/10)
+	r.buildingModels[cacheKey] = m
+	return m
+}
+
+// wallOwners maps each wall and gate tile to the player that owns it
+func wallOwners(world *core.World) map[[2]int]int {
+	walls := make(map[[2]int]int)
+	for _, id := range world.Query(core.CompBuildingName, core.CompPosition, core.CompOwner) {
+		if key := world.Get(id, core.CompBuildingName).(*core.BuildingName).Key; key != "wall" && key != "gate" {
+			continue
+		}
+		pos := world.Get(id, core.CompPosition).(*core.Position)
+		walls[[2]int{int(pos.X), int(pos.Y)}] = world.Get(id, core.CompOwner).(*core.Owner).PlayerID
+	}
+	return walls
+}
+
+// wallMask returns which neighbours of a wall tile are walls of the same owner
+func wallMask(walls map[[2]int]int, x, y, owner int) int {
+	mask := 0
+	for bit, d := range map[int][2]int{WallN: {0, -1}, WallE: {1, 0}, WallS: {0, 1}, WallW: {-1, 0}} {
+		if o, ok := walls[[2]int{x + d[0], y + d[1]}]; ok && o == owner {
+			mask |= bit
+		}
+	}
+	return mask
+}
+
+// deployScale returns the horizontal and vertical scale of an entity that is
+// unpacking (spreads out flat) or packing up (folds down)
+func deployScale(world *core.World, id core.EntityID) (xz, y float64) {
+	dep := world.Get(id, core.CompDeploying)
+	if dep == nil {
+		return 1, 1
+	}
+	d := dep.(*core.Deploying)
+	t := d.Progress()
+	if d.Undeploy {
+		return 1 - 0.3*t, 1 - 0.6*t
+	}
+	return 1 + 0.4*t, 1 - 0.5*t
+}
+
 func (r *Renderer3D) getUnitType(world *core.World, id core.EntityID) string {
 	if world.Has(id, core.CompMCV) {
 		return "mcv"
@@ -345,10 +541,10 @@ func (r *Renderer3D) getUnitMesh(world *core.World, id core.EntityID, faction st
 	return m
 }
 
-func (r *Renderer3D) drawProjectiles3D(screen *ebiten.Image, world *core.World) {
+func (r *Renderer3D) drawProjectiles3D(screen *ebiten.Image, tm *maplib.TileMap, world *core.World) {
 	for _, id := range world.Query(core.CompPosition, core.CompProjectile) {
 		pos := world.Get(id, core.CompPosition).(*core.Position)
-		sx, sy, _ := r.Camera.Project3DToScreen(pos.X, 0.3, pos.Y)
+		sx, sy, _ := r.Camera.Project3DToScreen(pos.X, GroundHeight(tm, pos.X, pos.Y)+0.3, pos.Y)
 
 		// Glow
 		vector.DrawFilledCircle(screen, float32(sx), float32(sy), 6, color.RGBA{255, 200, 50, 80}, false)
@@ -356,7 +552,7 @@ func (r *Renderer3D) drawProjectiles3D(screen *ebiten.Image, world *core.World)
 	}
 }
 
-func (r *Renderer3D) drawSelectionCircles(screen *ebiten.Image, world *core.World, localPlayerID int) {
+func (r *Renderer3D) drawSelectionCircles(screen *ebiten.Image, tm *maplib.TileMap, world *core.World, localPlayerID int) {
 	// Selection circles are drawn as projected ellipses
 	for _, id := range world.Query(core.CompPosition, core.CompSelectable, core.CompOwner) {
 		own := world.Get(id, core.CompOwner).(*core.Owner)
@@ -365,10 +561,11 @@ func (r *Renderer3D) drawSelectionCircles(screen *ebiten.Image, world *core.Worl
 		}
 		pos := world.Get(id, core.CompPosition).(*core.Position)
 		sel := world.Get(id, core.CompSelectable).(*core.Selectable)
+		gy := GroundHeight(tm, pos.X, pos.Y) + 0.01
 
 		// Project circle center and a point on the circumference
-		cx, cy, _ := r.Camera.Project3DToScreen(pos.X, 0.01, pos.Y)
-		rx, ry, _ := r.Camera.Project3DToScreen(pos.X+sel.Radius, 0.01, pos.Y)
+		cx, cy, _ := r.Camera.Project3DToScreen(pos.X, gy, pos.Y)
+		rx, ry, _ := r.Camera.Project3DToScreen(pos.X+sel.Radius, gy, pos.Y)
 		radius := math.Sqrt(float64((rx-cx)*(rx-cx) + (ry-cy)*(ry-cy)))
 
 		if radius < 2 {
@@ -386,8 +583,8 @@ func (r *Renderer3D) drawSelectionCircles(screen *ebiten.Image, world *core.Worl
 			wx1 := pos.X + sel.Radius*math.Cos(a1)
 			wz1 := pos.Y + sel.Radius*math.Sin(a1)
 
-			sx0, sy0, _ := r.Camera.Project3DToScreen(wx0, 0.01, wz0)
-			sx1, sy1, _ := r.Camera.Project3DToScreen(wx1, 0.01, wz1)
+			sx0, sy0, _ := r.Camera.Project3DToScreen(wx0, gy, wz0)
+			sx1, sy1, _ := r.Camera.Project3DToScreen(wx1, gy, wz1)
 			_ = radius
 			vector.StrokeLine(screen, float32(sx0), float32(sy0), float32(sx1), float32(sy1), 2, color.RGBA{0, 255, 0, 180}, false)
 		}
@@ -458,6 +655,7 @@ func (r *Renderer3D) renderMesh(screen *ebiten.Image, mesh *Mesh3D) {
 		// Flush if approaching uint16 limit
 		if len(vertices) >= 65000 {
 			screen.DrawTriangles(vertices, indices, r.whiteImg, nil)
+			r.drawCalls++
 			vertices = vertices[:0]
 			indices = indices[:0]
 		}
@@ -465,6 +663,7 @@ func (r *Renderer3D) renderMesh(screen *ebiten.Image, mesh *Mesh3D) {
 
 	if len(vertices) > 0 {
 		screen.DrawTriangles(vertices, indices, r.whiteImg, nil)
+		r.drawCalls++
 	}
 }
 
@@ -546,25 +745,3 @@ func (r *Renderer3D) DrawMinimap(screen *ebiten.Image, tm *maplib.TileMap, posX,
 	op.GeoM.Translate(float64(posX), float64(posY))
 	screen.DrawImage(minimap, op)
 }
-
-// DrawHealthBar draws a health bar at screen position
-func (r *Renderer3D) DrawHealthBar(screen *ebiten.Image, sx, sy int, ratio float64, width int) {
-	barH := float32(4)
-	barW := float32(width)
-	bx := float32(sx) - barW/2
-	by := float32(sy) - 5
-
-	// Background
-	vector.DrawFilledRect(screen, bx, by, barW, barH, color.RGBA{40, 40, 40, 200}, false)
-
-	// Health fill
-	var hc color.RGBA
-	if ratio > 0.6 {
-		hc = color.RGBA{0, 200, 0, 255}
-	} else if ratio > 0.3 {
-		hc = color.RGBA{255, 200, 0, 255}
-	} else {
-		hc = color.RGBA{255, 0, 0, 255}
-	}
-	vector.DrawFilledRect(screen, bx, by, barW*float32(ratio), barH, hc, false)
-}
@@ -0,0 +1,58 @@
+package render3d
+
+import (
+	"math"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/render"
+)
+
+// TestRenderers drives the 2D and 3D renderers through the shared Renderer
+// interface only, the way a front end that can swap them would
+func TestRenderers(t *testing.T) {
+	renderers := []struct {
+		name string
+		r    render.Renderer
+	}{
+		{"iso", render.NewIsoRenderer(1280, 720)},
+		{"3d", NewRenderer3D(1280, 720)},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 0.1 }
+
+	for _, tc := range renderers {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.r
+			r.View().CenterOn(20, 20)
+
+			// a ground point projects to the screen and picks back to itself
+			for _, p := range [][2]float64{{20, 20}, {18, 23}, {24, 17}} {
+				sx, sy := r.Project(p[0], p[1], 0)
+				wx, wy := r.ScreenToWorld(sx, sy)
+				if !near(wx, p[0]) || !near(wy, p[1]) {
+					t.Errorf("ScreenToWorld(Project(%v, %v)) = (%.2f, %.2f)", p[0], p[1], wx, wy)
+				}
+				vx, vy := r.View().WorldToScreen(p[0], p[1])
+				if vx != sx || vy != sy {
+					t.Errorf("Project(%v, %v, 0) = (%d, %d), View().WorldToScreen = (%d, %d)", p[0], p[1], sx, sy, vx, vy)
+				}
+			}
+
+			// height lifts a point up the screen
+			_, ground := r.Project(20, 20, 0)
+			_, raised := r.Project(20, 20, 2)
+			if raised >= ground {
+				t.Errorf("Project at height 2 gave y=%d, want above ground y=%d", raised, ground)
+			}
+
+			// after a resize the view centres on the new screen
+			for _, size := range [][2]int{{1920, 1080}, {800, 600}} {
+				r.Resize(size[0], size[1])
+				r.View().CenterOn(20, 20)
+				wx, wy := r.ScreenToWorld(size[0]/2, size[1]/2)
+				if !near(wx, 20) || !near(wy, 20) {
+					t.Errorf("at %dx%d the screen centre picks (%.2f, %.2f), want (20, 20)", size[0], size[1], wx, wy)
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,8 @@ type SpriteAtlas struct {
 	sprites  map[string]*ebiten.Image
 	basePath string
 	loaded   bool
+
+	DrawCalls int // draw calls issued since last reset (profiling)
 }
 
 // NewSpriteAtlas creates a new sprite atlas
@@ -112,6 +114,16 @@ func (sa *SpriteAtlas) GetBuildingSprite(buildingKey, faction string) *ebiten.Im
 	return nil
 }
 
+// GetWallSprite returns the wall piece for a neighbour mask (see WallN..WallW), if extracted
+func (sa *SpriteAtlas) GetWallSprite(mask int, faction string) *ebiten.Image {
+	if faction == "Soviet" || faction == "soviet" {
+		if img := sa.Get(fmt.Sprintf("buildings/ra2_soviet_wall_%d", mask)); img != nil {
+			return img
+		}
+	}
+	return sa.Get(fmt.Sprintf("buildings/wall_%d", mask))
+}
+
 // GetUnitSprite returns the sprite for a unit type and faction
 func (sa *SpriteAtlas) GetUnitSprite(unitType, faction string) *ebiten.Image {
 	switch unitType {
@@ -164,6 +176,11 @@ func (sa *SpriteAtlas) GetUnitSprite(unitType, faction string) *ebiten.Image {
 
 // DrawBillboard renders a sprite as a billboard (camera-facing quad) at a 3D position
 func (sa *SpriteAtlas) DrawBillboard(screen *ebiten.Image, cam *Camera3D, sprite *ebiten.Image, worldX, worldY, worldZ, scale float64) {
+	sa.DrawBillboardShaded(screen, cam, sprite, worldX, worldY, worldZ, scale, 1)
+}
+
+// DrawBillboardShaded draws a billboard with its colour scaled by shade (1 = unchanged)
+func (sa *SpriteAtlas) DrawBillboardShaded(screen *ebiten.Image, cam *Camera3D, sprite *ebiten.Image, worldX, worldY, worldZ, scale, shade float64) {
 	if sprite == nil {
 		return
 	}
@@ -187,8 +204,12 @@ func (sa *SpriteAtlas) DrawBillboard(screen *ebiten.Image, cam *Camera3D, sprite
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(scaleF, scaleF)
 	op.GeoM.Translate(float64(sx)-targetW/2, float64(sy)-targetH)
+	if shade != 1 {
+		op.ColorScale.Scale(float32(shade), float32(shade), float32(shade), 1)
+	}
 
 	screen.DrawImage(sprite, op)
+	sa.DrawCalls++
 }
 
 func loadEbitenImage(path string) *ebiten.Image {
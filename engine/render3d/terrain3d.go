@@ -22,6 +22,19 @@ var TerrainBaseColors = map[maplib.TerrainType]Color3{
 	maplib.TerrainSnow:      {0.82, 0.82, 0.85},
 	maplib.TerrainUrban:     {0.58, 0.56, 0.54},
 	maplib.TerrainForest:    {0.16, 0.48, 0.12},
+	maplib.TerrainRamp:      {0.52, 0.46, 0.34},
+	maplib.TerrainBridgeWreck: {0.28, 0.22, 0.16},
+}
+
+// HeightStep is the world-space height of one elevation level
+const HeightStep = 0.15
+
+// GroundHeight returns the world-space terrain height under a world position
+func GroundHeight(tm *maplib.TileMap, wx, wz float64) float64 {
+	if tm == nil {
+		return 0
+	}
+	return float64(tm.HeightAt(int(math.Floor(wx)), int(math.Floor(wz)))) * HeightStep
 }
 
 // perlinNoise simple hash-based noise for height variation
@@ -77,7 +90,7 @@ func GenerateTerrainMeshStatic(tm *maplib.TileMap, minX, minY, maxX, maxY int) *
 			baseColor.B = math.Max(0, math.Min(1, baseColor.B+variation*0.5))
 
 			// Height
-			h := float64(tile.Height) * 0.15
+			h := float64(tm.HeightAt(x, y)) * HeightStep
 			noiseH := smoothNoise(x, y) * 0.06
 			h += noiseH
 
@@ -101,7 +114,7 @@ func GenerateTerrainMeshStatic(tm *maplib.TileMap, minX, minY, maxX, maxY int) *
 			mesh.AddQuad(v0, v1, v2, v3)
 
 			// Cliff/elevation side faces
-			if tile.Height > 0 {
+			if tm.HeightAt(x, y) > 0 {
 				sideColor := Color3{baseColor.R * 0.65, baseColor.G * 0.65, baseColor.B * 0.65}
 				addTerrainSides(mesh, tm, x, y, h, sideColor)
 			}
@@ -156,8 +169,8 @@ func addTerrainSides(mesh *Mesh3D, tm *maplib.TileMap, x, y int, h float64, c Co
 	for di, d := range dirs {
 		nx, ny := x+d[0], y+d[1]
 		adjH := 0.0
-		if adj := tm.At(nx, ny); adj != nil {
-			adjH = float64(adj.Height) * 0.15
+		if tm.InBounds(nx, ny) {
+			adjH = float64(tm.HeightAt(nx, ny)) * HeightStep
 		}
 		if h > adjH+0.01 {
 			n := normals[di]
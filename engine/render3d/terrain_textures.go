@@ -28,6 +28,8 @@ type TerrainTextureAtlas struct {
 	// Cached static terrain batches (rebuilt only when camera/viewport changes)
 	staticCache    []cachedBatch
 	staticCacheKey string // "minX,minY,maxX,maxY,sw,sh,vpHash"
+
+	DrawCalls int // draw calls issued since last reset (profiling)
 }
 
 // NewTerrainTextureAtlas creates a new atlas
@@ -143,6 +145,10 @@ func terrainTypeToPrefix(t maplib.TerrainType) string {
 		return "urban"
 	case maplib.TerrainForest:
 		return "grass_dark"
+	case maplib.TerrainRamp:
+		return "dirt"
+	case maplib.TerrainBridgeWreck:
+		return "rock"
 	default:
 		return "grass"
 	}
@@ -165,8 +171,8 @@ func (ta *TerrainTextureAtlas) RenderTexturedTerrain(
 	vp := cam.ViewProj()
 
 	// Cache key based on viewport and camera state
-	cacheKey := fmt.Sprintf("%d,%d,%d,%d,%.2f,%.2f,%.2f,%.2f,%.2f,%d,%d",
-		minX, minY, maxX, maxY, cam.TargetX, cam.TargetY, cam.Zoom, cam.Pitch, cam.Yaw, cam.ScreenW, cam.ScreenH)
+	cacheKey := fmt.Sprintf("%d,%d,%d,%d,%.2f,%.2f,%.2f,%.2f,%.2f,%d,%d,%d",
+		minX, minY, maxX, maxY, cam.TargetX, cam.TargetY, cam.Zoom, cam.Pitch, cam.Yaw, cam.ScreenW, cam.ScreenH, tm.Revision)
 
 	// Rebuild static cache if viewport changed
 	if ta.staticCacheKey != cacheKey {
@@ -180,6 +186,7 @@ func (ta *TerrainTextureAtlas) RenderTexturedTerrain(
 			op := &ebiten.DrawTrianglesOptions{}
 			op.AntiAlias = false
 			screen.DrawTriangles(batch.vertices, batch.indices, batch.tex, op)
+			ta.DrawCalls++
 		}
 	}
 
@@ -211,7 +218,7 @@ func (ta *TerrainTextureAtlas) rebuildStaticCache(
 				continue
 			}
 
-			h := float64(tile.Height) * 0.15
+			h := float64(tm.HeightAt(x, y)) * HeightStep
 			fx, fz := float64(x), float64(y)
 
 			p0 := vp.TransformPoint(V3(fx, h, fz))
@@ -362,6 +369,7 @@ func (ta *TerrainTextureAtlas) renderWaterTiles(
 			op := &ebiten.DrawTrianglesOptions{}
 			op.AntiAlias = false
 			screen.DrawTriangles(batch.vertices, batch.indices, tex, op)
+			ta.DrawCalls++
 		}
 	}
 }
@@ -381,9 +389,11 @@ func (ta *TerrainTextureAtlas) RenderTreeBillboards(
 	}
 
 	type treeDraw struct {
-		x, y  float64
-		depth float64
-		hash  uint32
+		x, y    float64
+		h       float64
+		depth   float64
+		hash    uint32
+		burning bool
 	}
 	var trees []treeDraw
 
@@ -393,10 +403,10 @@ func (ta *TerrainTextureAtlas) RenderTreeBillboards(
 			if tile == nil || tile.Terrain != maplib.TerrainForest {
 				continue
 			}
-			h := float64(tile.Height) * 0.15
+			h := float64(tm.HeightAt(x, y)) * HeightStep
 			_, _, depth := cam.Project3DToScreen(float64(x)+0.5, h+0.4, float64(y)+0.5)
 			hash := uint32(x*73856093 ^ y*19349663)
-			trees = append(trees, treeDraw{float64(x) + 0.5, float64(y) + 0.5, depth, hash})
+			trees = append(trees, treeDraw{float64(x) + 0.5, float64(y) + 0.5, h, depth, hash, tile.Burning > 0})
 		}
 	}
 
@@ -407,7 +417,7 @@ func (ta *TerrainTextureAtlas) RenderTreeBillboards(
 
 	for _, t := range trees {
 		// Draw a tree billboard: trunk color block + canopy
-		h := 0.0 // base height
+		h := t.h // base height
 		treeH := 0.3 + float64(t.hash%100)/400.0
 		canopyScale := 0.8 + float64(t.hash%80)/200.0
 
@@ -445,9 +455,14 @@ func (ta *TerrainTextureAtlas) RenderTreeBillboards(
 		targetH := float64(canopyR) * 1.5 // slightly squished for iso perspective
 		op.GeoM.Scale(targetW/tw, targetH/th)
 		op.GeoM.Translate(float64(sx)-targetW/2, float64(sy)-targetH-float64(trunkH)/2)
-		// Green tint
-		op.ColorScale.Scale(0.7, 1.1, 0.6, 1.0)
+		// Green tint, orange while on fire
+		if t.burning {
+			op.ColorScale.Scale(1.6, 0.7, 0.3, 1.0)
+		} else {
+			op.ColorScale.Scale(0.7, 1.1, 0.6, 1.0)
+		}
 		screen.DrawImage(treeTex, op)
+		ta.DrawCalls++
 		_ = trunkW
 	}
 }
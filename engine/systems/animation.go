@@ -50,6 +50,37 @@ func (s *VeterancySystem) Update(_ *core.World, _ float64) {
 	// Veterancy is tracked via events; this is a placeholder for tick-based checks
 }
 
+// PromoteUnit raises a unit one veterancy rank, granting +20% max health
+// and damage. Returns false if the unit is already elite.
+func PromoteUnit(w *core.World, id core.EntityID) bool {
+	var vet *core.Veterancy
+	if c := w.Get(id, core.CompVeterancy); c != nil {
+		vet = c.(*core.Veterancy)
+	} else {
+		vet = &core.Veterancy{}
+		w.Attach(id, vet)
+	}
+	if vet.Rank >= core.MaxVeterancyRank {
+		return false
+	}
+	vet.Rank++
+	if h := w.Get(id, core.CompHealth); h != nil {
+		hp := h.(*core.Health)
+		bonus := hp.Max / 5
+		hp.Max += bonus
+		hp.Current += bonus
+	}
+	if wc := w.Get(id, core.CompWeapon); wc != nil {
+		wpn := wc.(*core.Weapon)
+		wpn.Damage += wpn.Damage / 5
+	}
+	if sg, ok := core.GetComponent[*core.Siege](w, id); ok {
+		sg.Mobile.Damage += sg.Mobile.Damage / 5
+		sg.Sieged.Damage += sg.Sieged.Damage / 5
+	}
+	return true
+}
+
 // GameOverSystem checks if any player has lost all buildings
 type GameOverSystem struct {
 	Players *core.PlayerManager
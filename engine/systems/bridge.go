@@ -0,0 +1,147 @@
+package systems
+
+import (
+	"sort"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+// BridgeSystem drops ground units off collapsed bridges, lets engineers
+// ordered to a wreck repair the span and keeps the nav grid in step with both
+type BridgeSystem struct {
+	TileMap  *maplib.TileMap
+	NavGrid  *pathfind.NavGrid // optional: refreshed when a span falls or is rebuilt
+	EventBus *core.EventBus
+
+	revision int // TileMap.Revision last seen
+}
+
+func (s *BridgeSystem) Priority() int { return 46 }
+
+func (s *BridgeSystem) Update(w *core.World, dt float64) {
+	tm := s.TileMap
+	for _, id := range w.Query(core.CompPosition, core.CompMovable) {
+		mov := w.Get(id, core.CompMovable).(*core.Movable)
+		if mov.MoveType == core.MoveAir || mov.MoveType == core.MoveNaval {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		tx, ty := int(pos.X), int(pos.Y)
+
+		// Anything still on a span when it collapses goes down with it
+		if t := tm.At(tx, ty); t != nil && t.Terrain == maplib.TerrainBridgeWreck {
+			Kill(w, id, s.EventBus)
+			continue
+		}
+
+		// An engineer ordered to a wreck goes in and rebuilds the span once
+		// it gets there; an order it can't carry out lapses
+		order, ok := core.GetComponent[*core.BridgeRepair](w, id)
+		if !ok || mov.PathIdx < len(mov.Path) {
+			continue
+		}
+		w.Detach(id, core.CompBridgeRepair)
+		if besideSpan(tm, order.Target, tx, ty) && tm.RepairBridge(order.Target.X, order.Target.Y) {
+			w.Destroy(id)
+		}
+	}
+
+	if tm.Revision != s.revision && s.NavGrid != nil {
+		s.NavGrid.Sync(tm)
+		s.repathBlocked(w)
+	}
+	s.revision = tm.Revision
+}
+
+// repathBlocked re-routes units whose remaining path crosses a tile that is
+// no longer passable, so they take the long way round. Only the route
+// changes: stance, attack-move and harvester state are left alone.
+func (s *BridgeSystem) repathBlocked(w *core.World) {
+	for _, id := range w.Query(core.CompMovable, core.CompPosition) {
+		mov := w.Get(id, core.CompMovable).(*core.Movable)
+		if mov.PathIdx >= len(mov.Path) {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		flag := MovePassFlag(mov.MoveType)
+		owner := ownerOf(w, id)
+		passable := func(x, y int) bool { return s.NavGrid.PassableFor(x, y, flag, owner) }
+		// Smoothed paths skip tiles, so walk each leg rather than checking waypoints
+		fx, fy := int(pos.X), int(pos.Y)
+		for _, p := range mov.Path[mov.PathIdx:] {
+			if !maplib.WalkLine(fx, fy, p.X, p.Y, passable) {
+				goal := mov.Path[len(mov.Path)-1]
+				if !routeTo(w, s.NavGrid, id, mov, int(pos.X), int(pos.Y), goal.X, goal.Y) {
+					// No way round: stop short rather than walk into the gap
+					mov.Path, mov.PathIdx = nil, 0
+					mov.Unreachable = true
+				}
+				break
+			}
+			fx, fy = p.X, p.Y
+		}
+	}
+}
+
+// OrderBridgeRepair sends an engineer to rebuild the wrecked span at (x, y).
+// It walks to the nearest reachable tile beside the span and goes in when it
+// arrives. It reports false if id isn't an engineer, there is no wreck at
+// (x, y) or the span can't be reached.
+func OrderBridgeRepair(w *core.World, ng *pathfind.NavGrid, tm *maplib.TileMap, id core.EntityID, x, y int) bool {
+	ut, ok := core.GetComponent[*core.UnitType](w, id)
+	if !ok || ut.Key != "engineer" {
+		return false
+	}
+	pos, ok := core.GetComponent[*core.Position](w, id)
+	mov, ok2 := core.GetComponent[*core.Movable](w, id)
+	if !ok || !ok2 {
+		return false
+	}
+	flag := MovePassFlag(mov.MoveType)
+	owner := ownerOf(w, id)
+	seen := make(map[core.TilePos]bool)
+	var spots []core.TilePos
+	for _, p := range tm.ConnectedTiles(x, y, maplib.TerrainBridgeWreck) {
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				c := core.TilePos{X: p[0] + dx, Y: p[1] + dy}
+				if !seen[c] && ng.PassableFor(c.X, c.Y, flag, owner) {
+					seen[c] = true
+					spots = append(spots, c)
+				}
+			}
+		}
+	}
+	dist := func(c core.TilePos) float64 {
+		return pos.DistanceTo(&core.Position{X: float64(c.X) + 0.5, Y: float64(c.Y) + 0.5})
+	}
+	sort.Slice(spots, func(i, j int) bool { return dist(spots[i]) < dist(spots[j]) })
+	for _, c := range spots {
+		OrderMove(w, ng, id, c.X, c.Y)
+		if !mov.Unreachable {
+			w.Attach(id, &core.BridgeRepair{Target: core.TilePos{X: x, Y: y}})
+			return true
+		}
+	}
+	return false
+}
+
+// besideSpan reports whether (x, y) touches the wrecked span containing target
+func besideSpan(tm *maplib.TileMap, target core.TilePos, x, y int) bool {
+	for _, p := range tm.ConnectedTiles(target.X, target.Y, maplib.TerrainBridgeWreck) {
+		if abs(p[0]-x) <= 1 && abs(p[1]-y) <= 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// hitBridge wears down a bridge tile hit by an explosive
+func hitBridge(tm *maplib.TileMap, x, y, dmg int, dmgType core.DamageType) {
+	if tm == nil || dmgType != core.DmgExplosive {
+		return
+	}
+	tm.DamageBridge(x, y, dmg)
+}
@@ -0,0 +1,223 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+// riverMap returns a map split by a river down x = 8 with bridges at y = 4
+// and y = 12
+func riverMap() *maplib.TileMap {
+	tm := maplib.NewTileMap("t", 16, 16)
+	tm.SetTerrain(8, 0, 8, 15, maplib.TerrainWater)
+	tm.SetTerrain(8, 4, 8, 4, maplib.TerrainBridge)
+	tm.SetTerrain(8, 12, 8, 12, maplib.TerrainBridge)
+	return tm
+}
+
+func TestCollapsedBridgeKillsUnitsOnIt(t *testing.T) {
+	tests := []struct {
+		name  string
+		armor *core.Armor
+		hp    int
+	}{
+		{"unarmored", nil, 100},
+		{"heavily armored", &core.Armor{ArmorType: core.ArmorHeavy, Value: 5000}, 5000},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := riverMap()
+			w := core.NewWorld(20)
+			bus := core.NewEventBus()
+			w.AddSystem(&BridgeSystem{TileMap: tm, EventBus: bus})
+			id := spawnGroundUnit(w, 8.5, 4.5, core.MoveVehicle)
+			w.Attach(id, &core.Health{Current: tc.hp, Max: tc.hp})
+			if tc.armor != nil {
+				w.Attach(id, tc.armor)
+			}
+			bystander := spawnGroundUnit(w, 8.5, 12.5, core.MoveVehicle)
+			w.Attach(bystander, &core.Health{Current: 100, Max: 100})
+
+			var deaths int
+			core.Subscribe(bus, core.EvtUnitDestroyed, func(core.KillEvent) { deaths++ })
+
+			tm.DamageBridge(8, 4, maplib.BridgeHP)
+			w.Tick(0.05)
+			bus.Dispatch()
+
+			if w.Alive(id) {
+				t.Error("unit on the collapsed span survived")
+			}
+			if deaths != 1 {
+				t.Errorf("%d destroyed events, want 1", deaths)
+			}
+			if !w.Alive(bystander) {
+				t.Error("unit on the other bridge died")
+			}
+		})
+	}
+}
+
+func TestCollapsedBridgeReroutesUnits(t *testing.T) {
+	tm := riverMap()
+	ng := pathfind.NewNavGrid(tm)
+	w := core.NewWorld(20)
+	sys := &BridgeSystem{TileMap: tm, NavGrid: ng}
+	sys.Update(w, 0)
+
+	id := spawnGroundUnit(w, 2.5, 4.5, core.MoveVehicle)
+	OrderMove(w, ng, id, 14, 4)
+	if !pathCrosses(w, id, 8, 4) {
+		t.Fatal("the first order should take the near bridge")
+	}
+
+	tm.DamageBridge(8, 4, maplib.BridgeHP)
+	sys.Update(w, 0.05)
+
+	m, _ := core.GetComponent[*core.Movable](w, id)
+	if len(m.Path) == 0 || m.Path[len(m.Path)-1] != (core.TilePos{X: 14, Y: 4}) {
+		t.Fatalf("unit lost its goal: path %v", m.Path)
+	}
+	if pathCrosses(w, id, 8, 4) {
+		t.Error("the new path still crosses the wrecked span")
+	}
+	if !pathCrosses(w, id, 8, 12) {
+		t.Error("the new path doesn't take the far bridge")
+	}
+}
+
+// pathCrosses reports whether a unit's remaining path walks over (x, y)
+func pathCrosses(w *core.World, id core.EntityID, x, y int) bool {
+	m, _ := core.GetComponent[*core.Movable](w, id)
+	pos, _ := core.GetComponent[*core.Position](w, id)
+	fx, fy := int(pos.X), int(pos.Y)
+	hit := false
+	for _, p := range m.Path[m.PathIdx:] {
+		maplib.WalkLine(fx, fy, p.X, p.Y, func(tx, ty int) bool {
+			hit = hit || (tx == x && ty == y)
+			return true
+		})
+		fx, fy = p.X, p.Y
+	}
+	return hit
+}
+
+func TestRerouteKeepsOrders(t *testing.T) {
+	tests := []struct {
+		name      string
+		farGate   int // owner of a gate on the far bridge, -1 for none
+		wantRoute bool
+	}{
+		{"far bridge open", -1, true},
+		{"own gate on the far bridge", 0, true},
+		{"enemy gate on the far bridge", 1, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := riverMap()
+			ng := pathfind.NewNavGrid(tm)
+			if tc.farGate >= 0 {
+				ng.SetGate(8, 12, tc.farGate)
+			}
+			w := core.NewWorld(20)
+			sys := &BridgeSystem{TileMap: tm, NavGrid: ng}
+			sys.Update(w, 0)
+
+			id := spawnGroundUnit(w, 2.5, 4.5, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			wep := &core.Weapon{}
+			w.Attach(id, wep)
+			OrderAttackMove(w, ng, id, 14, 4)
+			wep.Stance = core.StanceHold
+
+			tm.DamageBridge(8, 4, maplib.BridgeHP)
+			sys.Update(w, 0.05)
+
+			m, _ := core.GetComponent[*core.Movable](w, id)
+			if wep.Stance != core.StanceHold {
+				t.Error("rerouting reset the unit's stance")
+			}
+			if !m.AttackMove {
+				t.Error("rerouting dropped attack-move")
+			}
+			if tc.wantRoute {
+				if len(m.Path) == 0 || !pathCrosses(w, id, 8, 12) {
+					t.Errorf("no route over the far bridge: path %v", m.Path)
+				}
+				return
+			}
+			if len(m.Path) != 0 || !m.Unreachable {
+				t.Errorf("path %v, Unreachable %v; want the unit stopped short of the gap", m.Path, m.Unreachable)
+			}
+		})
+	}
+}
+
+func TestEngineerRepairsOnlyWhenOrdered(t *testing.T) {
+	tests := []struct {
+		name        string
+		order       bool
+		countermand bool // a move order after the repair order
+		wantBridge  bool
+	}{
+		{"idle beside the wreck", false, false, false},
+		{"ordered to repair", true, false, true},
+		{"repair order replaced by a move", true, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := riverMap()
+			tm.DamageBridge(8, 4, maplib.BridgeHP)
+			ng := pathfind.NewNavGrid(tm)
+			w := core.NewWorld(20)
+			w.AddSystem(&MovementSystem{NavGrid: ng, TileMap: tm})
+			w.AddSystem(&BridgeSystem{TileMap: tm, NavGrid: ng})
+
+			// starts right beside the wreck, where it used to be used up
+			id := spawnGroundUnit(w, 7.5, 4.5, core.MoveInfantry)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			w.Attach(id, &core.UnitType{Key: "engineer"})
+			if tc.order && !OrderBridgeRepair(w, ng, tm, id, 8, 4) {
+				t.Fatal("OrderBridgeRepair refused a reachable wreck")
+			}
+			if tc.countermand {
+				OrderMove(w, ng, id, 2, 2)
+			}
+			for i := 0; i < 200; i++ {
+				w.Tick(0.05)
+			}
+
+			if got := tm.At(8, 4).Terrain == maplib.TerrainBridge; got != tc.wantBridge {
+				t.Errorf("bridge rebuilt = %v, want %v", got, tc.wantBridge)
+			}
+			if w.Alive(id) == tc.wantBridge {
+				t.Errorf("engineer alive = %v, want %v", w.Alive(id), !tc.wantBridge)
+			}
+		})
+	}
+}
+
+func TestOrderBridgeRepairRefuses(t *testing.T) {
+	tm := riverMap()
+	tm.DamageBridge(8, 4, maplib.BridgeHP)
+	ng := pathfind.NewNavGrid(tm)
+	w := core.NewWorld(20)
+
+	tank := spawnGroundUnit(w, 2.5, 4.5, core.MoveVehicle)
+	w.Attach(tank, &core.UnitType{Key: "grizzly"})
+	eng := spawnGroundUnit(w, 2.5, 4.5, core.MoveInfantry)
+	w.Attach(eng, &core.UnitType{Key: "engineer"})
+
+	if OrderBridgeRepair(w, ng, tm, tank, 8, 4) {
+		t.Error("a tank took a bridge repair order")
+	}
+	if OrderBridgeRepair(w, ng, tm, eng, 8, 12) {
+		t.Error("an engineer took a repair order for a standing bridge")
+	}
+	if w.Has(tank, core.CompBridgeRepair) || w.Has(eng, core.CompBridgeRepair) {
+		t.Error("a refused order was recorded")
+	}
+}
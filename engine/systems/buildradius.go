@@ -0,0 +1,106 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+// DefaultBuildRadius is how far, in tiles, a building lets its owner build around it
+const DefaultBuildRadius = 10.0
+
+// BuildRadius returns how far around the given building its owner may
+// build, before faction traits. Unknown buildings use the default.
+func (tt *TechTree) BuildRadius(buildingKey string) float64 {
+	bdef, ok := tt.Buildings[buildingKey]
+	if !ok || bdef.BuildRadius == 0 {
+		return DefaultBuildRadius
+	}
+	if bdef.BuildRadius < 0 {
+		return 0
+	}
+	return bdef.BuildRadius
+}
+
+// factionRadius returns the build radius multiplier and build-anywhere trait
+// for a faction
+func (tt *TechTree) factionRadius(faction string) (float64, bool) {
+	fd, ok := tt.Factions[faction]
+	if !ok {
+		return 1, false
+	}
+	if fd.BuildRadiusMult <= 0 {
+		return 1, fd.BuildAnywhere
+	}
+	return fd.BuildRadiusMult, fd.BuildAnywhere
+}
+
+// buildArea is one owned building's placement circle
+type buildArea struct {
+	cx, cy, r float64
+}
+
+// buildAreas returns the placement circles around playerID's buildings,
+// or anywhere=true if the faction can build without them
+func buildAreas(w *core.World, tt *TechTree, playerID int, faction string) (areas []buildArea, anywhere bool) {
+	mult, anywhere := tt.factionRadius(faction)
+	if anywhere {
+		return nil, true
+	}
+	for _, bid := range w.Query(core.CompBuilding, core.CompOwner, core.CompPosition) {
+		if w.Get(bid, core.CompOwner).(*core.Owner).PlayerID != playerID {
+			continue
+		}
+		r := DefaultBuildRadius
+		if bn := w.Get(bid, core.CompBuildingName); bn != nil {
+			r = tt.BuildRadius(bn.(*core.BuildingName).Key)
+		}
+		if r <= 0 {
+			continue
+		}
+		b := w.Get(bid, core.CompBuilding).(*core.Building)
+		pos := w.Get(bid, core.CompPosition).(*core.Position)
+		areas = append(areas, buildArea{pos.X + float64(b.SizeX)/2, pos.Y + float64(b.SizeY)/2, r * mult})
+	}
+	return areas, false
+}
+
+// InBuildRadius reports whether playerID may start a building at tile (x, y)
+func InBuildRadius(w *core.World, tt *TechTree, playerID int, faction string, x, y int) bool {
+	areas, anywhere := buildAreas(w, tt, playerID, faction)
+	if anywhere {
+		return true
+	}
+	fx, fy := float64(x)+0.5, float64(y)+0.5
+	for _, a := range areas {
+		dx, dy := fx-a.cx, fy-a.cy
+		if dx*dx+dy*dy < a.r*a.r {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildMask marks the tiles of a width x height map where playerID may
+// start a building: the union of its buildings' radii
+func BuildMask(w *core.World, tt *TechTree, playerID int, faction string, width, height int) []bool {
+	mask := make([]bool, width*height)
+	areas, anywhere := buildAreas(w, tt, playerID, faction)
+	if anywhere {
+		for i := range mask {
+			mask[i] = true
+		}
+		return mask
+	}
+	for _, a := range areas {
+		x0, x1 := max(int(a.cx-a.r), 0), min(int(a.cx+a.r)+1, width-1)
+		y0, y1 := max(int(a.cy-a.r), 0), min(int(a.cy+a.r)+1, height-1)
+		for y := y0; y <= y1; y++ {
+			for x := x0; x <= x1; x++ {
+				dx, dy := float64(x)+0.5-a.cx, float64(y)+0.5-a.cy
+				if dx*dx+dy*dy < a.r*a.r {
+					mask[y*width+x] = true
+				}
+			}
+		}
+	}
+	return mask
+}
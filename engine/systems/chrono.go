@@ -0,0 +1,83 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// Chrono tuning
+const (
+	ChronoWarmUp   = 1.5  // seconds a unit stands charging before it jumps
+	ChronoCooldown = 20.0 // seconds before it can jump again
+	chronoNudge    = 2    // tiles searched for a free spot if the target fills up
+)
+
+// ChronoSystem charges and completes teleports. If the destination is blocked
+// when the jump fires, the unit lands on the nearest free tile close by, or
+// the jump fizzles without starting the cooldown.
+type ChronoSystem struct {
+	TileMap  *maplib.TileMap
+	EventBus *core.EventBus
+}
+
+func (s *ChronoSystem) Priority() int { return 9 }
+
+func (s *ChronoSystem) Update(w *core.World, dt float64) {
+	for _, id := range w.Query(core.CompChrono, core.CompPosition) {
+		c := w.Get(id, core.CompChrono).(*core.Chrono)
+		if c.CooldownNow > 0 {
+			c.CooldownNow -= dt
+		}
+		if !c.Charging {
+			continue
+		}
+		// Charging units stand still
+		if mov := w.Get(id, core.CompMovable); mov != nil {
+			m := mov.(*core.Movable)
+			m.Path = nil
+			m.PathIdx = 0
+		}
+		c.Timer += dt
+		if c.Timer < c.WarmUp {
+			continue
+		}
+		c.Charging = false
+
+		tx, ty, ok := s.landing(w, id, c.Target.X, c.Target.Y)
+		if !ok {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		from := *pos
+		pos.X, pos.Y = float64(tx)+0.5, float64(ty)+0.5
+		c.CooldownNow = c.Cooldown
+		if s.EventBus != nil {
+			s.EventBus.Publish(core.Event{Type: core.EvtChronoWarp, Tick: w.TickCount, Payload: core.ChronoWarp{
+				Unit: id, FromX: from.X, FromY: from.Y, ToX: pos.X, ToY: pos.Y,
+			}})
+		}
+	}
+}
+
+// StartChrono begins charging a jump to tile (x, y). It fails while the
+// ability is cooling down or already charging, or if the target is blocked.
+func StartChrono(w *core.World, tm *maplib.TileMap, id core.EntityID, x, y int) bool {
+	cc := w.Get(id, core.CompChrono)
+	if cc == nil || !w.Has(id, core.CompPosition) {
+		return false
+	}
+	c := cc.(*core.Chrono)
+	if c.Charging || c.CooldownNow > 0 || !unitFits(w, tm, id, x, y) {
+		return false
+	}
+	c.Charging = true
+	c.Timer = 0
+	c.Target = core.TilePos{X: x, Y: y}
+	return true
+}
+
+// landing picks where a jump to (x, y) ends: the target itself if still
+// free, otherwise the nearest free tile within chronoNudge
+func (s *ChronoSystem) landing(w *core.World, id core.EntityID, x, y int) (int, int, bool) {
+	return nearestFree(w, s.TileMap, id, x, y, chronoNudge)
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 
 	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
 )
 
 // DamageMultiplier table: [DamageType][ArmorType] -> multiplier
@@ -20,8 +21,15 @@ var DamageMultiplier = [5][5]float64{
 type CombatSystem struct {
 	EventBus *core.EventBus
 	Players  *core.PlayerManager
+	TileMap  *maplib.TileMap // optional: enables high-ground bonuses
 }
 
+// High-ground bonuses per elevation level above the target
+const (
+	HighGroundRangeBonus  = 0.15
+	HighGroundDamageBonus = 0.10
+)
+
 func (s *CombatSystem) Priority() int { return 20 }
 
 func (s *CombatSystem) Update(w *core.World, dt float64) {
@@ -36,8 +44,27 @@ func (s *CombatSystem) Update(w *core.World, dt float64) {
 			continue
 		}
 
+		// Powered-down defenses are offline
+		if b := w.Get(aid, core.CompBuilding); b != nil && b.(*core.Building).PoweredDown {
+			continue
+		}
+		if wep.Stance == core.StanceHold {
+			continue
+		}
+		// Siege units don't fire while switching modes
+		if w.Has(aid, core.CompSiege) && w.Has(aid, core.CompDeploying) {
+			continue
+		}
+		if wep.Damage <= 0 && wep.DamageType != core.DmgPsionic {
+			continue
+		}
+
 		apos := w.Get(aid, core.CompPosition).(*core.Position)
 		aown := w.Get(aid, core.CompOwner).(*core.Owner)
+		psionic := wep.DamageType == core.DmgPsionic
+		if psionic && !canControlMore(w, aid) {
+			continue
+		}
 
 		// Find nearest enemy in range
 		var bestID core.EntityID
@@ -47,16 +74,21 @@ func (s *CombatSystem) Update(w *core.World, dt float64) {
 				continue
 			}
 			town := w.Get(tid, core.CompOwner).(*core.Owner)
-			if s.Players.AreAllies(aown.PlayerID, town.PlayerID) {
+			if town.PlayerID == core.NeutralPlayerID || s.Players.AreAllies(aown.PlayerID, town.PlayerID) {
+				continue
+			}
+			if psionic && !controllable(w, tid) {
 				continue
 			}
 			tpos := w.Get(tid, core.CompPosition).(*core.Position)
 			d := apos.DistanceTo(tpos)
-			if d <= wep.Range && d < bestDist {
+			rng := wep.Range * (1 + HighGroundRangeBonus*float64(s.heightAdvantage(apos, tpos)))
+			if d <= rng && d < bestDist && s.lineOfFire(w, aid, tid, apos, tpos) {
 				bestDist = d
 				bestID = tid
 			}
 		}
+		wep.Engaged = bestID != 0
 		if bestID == 0 {
 			continue
 		}
@@ -64,8 +96,11 @@ func (s *CombatSystem) Update(w *core.World, dt float64) {
 		// Fire
 		wep.CooldownNow = wep.Cooldown
 		tpos := w.Get(bestID, core.CompPosition).(*core.Position)
+		dmg := int(float64(wep.Damage) * (1 + HighGroundDamageBonus*float64(s.heightAdvantage(apos, tpos))))
 
-		if wep.Projectile != "" {
+		if psionic {
+			Dominate(w, aid, bestID)
+		} else if wep.Projectile != "" {
 			// Spawn projectile entity
 			pid := w.Spawn()
 			w.Attach(pid, &core.Position{X: apos.X, Y: apos.Y})
@@ -75,29 +110,100 @@ func (s *CombatSystem) Update(w *core.World, dt float64) {
 				TargetX:  tpos.X,
 				TargetY:  tpos.Y,
 				Speed:    8.0,
-				Damage:   wep.Damage,
+				Damage:   dmg,
 				Splash:   wep.Splash,
 				DmgType:  wep.DamageType,
 				HitFX:    "explosion",
 			})
 		} else {
 			// Hitscan: apply damage immediately
-			ApplyDamage(w, bestID, wep.Damage, wep.DamageType, s.EventBus)
+			ApplyDamageFrom(w, aid, bestID, int(float64(dmg)*forestCover(w, s.TileMap, bestID)), wep.DamageType, s.EventBus)
+			hitTerrain(s.TileMap, int(tpos.X), int(tpos.Y), dmg, wep.DamageType)
 		}
 
 		if s.EventBus != nil {
-			s.EventBus.Emit(core.Event{Type: core.EvtUnitAttack, Tick: w.TickCount})
+			s.EventBus.Publish(core.Event{Type: core.EvtUnitAttack, Tick: w.TickCount})
 		}
 	}
 }
 
+// heightAdvantage returns how many elevation levels the attacker stands above the target
+func (s *CombatSystem) heightAdvantage(apos, tpos *core.Position) int {
+	if s.TileMap == nil {
+		return 0
+	}
+	d := s.TileMap.HeightAt(int(apos.X), int(apos.Y)) - s.TileMap.HeightAt(int(tpos.X), int(tpos.Y))
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// hitTerrain applies a weapon hit to the forest or bridge tile at (x, y)
+func hitTerrain(tm *maplib.TileMap, x, y, dmg int, dmgType core.DamageType) {
+	hitForest(tm, x, y, dmg, dmgType)
+	hitBridge(tm, x, y, dmg, dmgType)
+}
+
+// lineOfFire reports whether a direct shot from attacker to target is clear of
+// cliffs, buildings and ridges higher than both ends. Shots to or from
+// aircraft are never blocked.
+func (s *CombatSystem) lineOfFire(w *core.World, aid, tid core.EntityID, apos, tpos *core.Position) bool {
+	if s.TileMap == nil || isAirborne(w, aid) || isAirborne(w, tid) {
+		return true
+	}
+	top := s.TileMap.HeightAt(int(apos.X), int(apos.Y))
+	if h := s.TileMap.HeightAt(int(tpos.X), int(tpos.Y)); h > top {
+		top = h
+	}
+	return maplib.WalkLine(int(apos.X), int(apos.Y), int(tpos.X), int(tpos.Y), func(x, y int) bool {
+		return !shotBlockedAt(w, s.TileMap, x, y, aid, tid) && s.TileMap.HeightAt(x, y) <= top
+	})
+}
+
+// shotBlockedAt reports whether the tile at (x, y) stops a shot between the
+// two entities. Their own building footprints never block.
+func shotBlockedAt(w *core.World, tm *maplib.TileMap, x, y int, aid, tid core.EntityID) bool {
+	t := tm.At(x, y)
+	if t == nil || !t.BlocksShots() {
+		return false
+	}
+	return !inFootprint(w, aid, x, y) && !inFootprint(w, tid, x, y)
+}
+
+// inFootprint reports whether tile (x, y) lies under the entity's building
+func inFootprint(w *core.World, id core.EntityID, x, y int) bool {
+	b := w.Get(id, core.CompBuilding)
+	pos := w.Get(id, core.CompPosition)
+	if b == nil || pos == nil {
+		return false
+	}
+	bd, p := b.(*core.Building), pos.(*core.Position)
+	bx, by := int(p.X), int(p.Y)
+	return x >= bx && x < bx+bd.SizeX && y >= by && y < by+bd.SizeY
+}
+
+// isAirborne reports whether the entity is a flying unit
+func isAirborne(w *core.World, id core.EntityID) bool {
+	mov := w.Get(id, core.CompMovable)
+	return mov != nil && mov.(*core.Movable).MoveType == core.MoveAir
+}
+
 // ApplyDamage applies damage to an entity considering armor
 func ApplyDamage(w *core.World, id core.EntityID, baseDamage int, dmgType core.DamageType, bus *core.EventBus) {
+	ApplyDamageFrom(w, 0, id, baseDamage, dmgType, bus)
+}
+
+// ApplyDamageFrom applies damage and credits a kill to the attacker, if known
+func ApplyDamageFrom(w *core.World, attacker, id core.EntityID, baseDamage int, dmgType core.DamageType, bus *core.EventBus) {
 	hp := w.Get(id, core.CompHealth)
 	if hp == nil {
 		return
 	}
 	h := hp.(*core.Health)
+	if h.Current <= 0 {
+		return // already dying this tick
+	}
 
 	mult := 1.0
 	if arm := w.Get(id, core.CompArmor); arm != nil {
@@ -117,11 +223,80 @@ func ApplyDamage(w *core.World, id core.EntityID, baseDamage int, dmgType core.D
 	}
 	h.Current -= finalDmg
 
+	if bus != nil {
+		ev := core.DamageEvent{Target: id, Amount: finalDmg, Multiplier: mult, Killed: h.Current <= 0}
+		if p := w.Get(id, core.CompPosition); p != nil {
+			pos := p.(*core.Position)
+			ev.X, ev.Y = pos.X, pos.Y
+		}
+		bus.Publish(core.Event{Type: core.EvtUnitDamaged, Tick: w.TickCount, Payload: ev})
+		if ua, ok := underAttack(w, attacker, id, ev.X, ev.Y); ok {
+			bus.Publish(core.Event{Type: core.EvtUnderAttack, Tick: w.TickCount, Payload: ua})
+		}
+	}
+
 	if h.Current <= 0 {
-		h.Current = 0
+		kill(w, attacker, id, bus)
+	}
+}
+
+// Kill destroys an entity outright, whatever its armor: health drops to
+// zero and the usual death event fires. Entities without health just go.
+func Kill(w *core.World, id core.EntityID, bus *core.EventBus) {
+	h, ok := core.GetComponent[*core.Health](w, id)
+	if !ok {
 		w.Destroy(id)
-		if bus != nil {
-			bus.Emit(core.Event{Type: core.EvtUnitDestroyed, Tick: w.TickCount})
-		}
+		return
+	}
+	if h.Current > 0 {
+		kill(w, 0, id, bus)
+	}
+}
+
+// kill zeroes an entity's health, announces its death and destroys it
+func kill(w *core.World, attacker, id core.EntityID, bus *core.EventBus) {
+	if h, ok := core.GetComponent[*core.Health](w, id); ok {
+		h.Current = 0
+	}
+	if bus != nil {
+		bus.Publish(core.Event{Type: deathEvent(w, id), Tick: w.TickCount, Payload: killEvent(w, attacker, id)})
+	}
+	w.Destroy(id)
+}
+
+// underAttack describes a hit by another player's unit, if that's what it was
+func underAttack(w *core.World, attacker, id core.EntityID, x, y float64) (core.UnderAttack, bool) {
+	vo, ok := core.GetComponent[*core.Owner](w, id)
+	if !ok {
+		return core.UnderAttack{}, false
+	}
+	ao, ok := core.GetComponent[*core.Owner](w, attacker)
+	if !ok || attacker == 0 || ao.PlayerID == vo.PlayerID {
+		return core.UnderAttack{}, false
+	}
+	return core.UnderAttack{
+		PlayerID: vo.PlayerID, Target: id, Attacker: attacker,
+		X: x, Y: y, Building: w.Has(id, core.CompBuilding),
+	}, true
+}
+
+// deathEvent picks the destroyed event matching the victim
+func deathEvent(w *core.World, id core.EntityID) core.EventType {
+	if w.Has(id, core.CompBuilding) {
+		return core.EvtBuildingDestroyed
+	}
+	return core.EvtUnitDestroyed
+}
+
+// killEvent builds the payload for a death, crediting the attacker's owner
+func killEvent(w *core.World, attacker, id core.EntityID) core.KillEvent {
+	ev := core.KillEvent{Victim: id, PlayerID: core.NeutralPlayerID, Killer: attacker}
+	if o, ok := core.GetComponent[*core.Owner](w, id); ok {
+		ev.PlayerID = o.PlayerID
+	}
+	if o, ok := core.GetComponent[*core.Owner](w, attacker); ok && attacker != 0 {
+		ev.KillerOwner = o.PlayerID
+		ev.Credited = true
 	}
+	return ev
 }
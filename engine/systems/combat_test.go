@@ -0,0 +1,201 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// duel is a shooter at (2, 5) and a target at (8, 5) owned by rival players
+// on open ground, with one of the shooter's own wall pieces at (wallX, 5)
+// unless wallX is negative
+func duel(w *core.World, wallX int) (tm *maplib.TileMap, shooter, target, wall core.EntityID) {
+	tm = maplib.NewTileMap("t", 16, 16)
+	shooter = spawnGroundUnit(w, 2.5, 5.5, core.MoveVehicle)
+	w.Attach(shooter, &core.Owner{PlayerID: 0})
+	w.Attach(shooter, &core.Weapon{Damage: 30, Range: 8, Cooldown: 1})
+	target = spawnGroundUnit(w, 8.5, 5.5, core.MoveVehicle)
+	w.Attach(target, &core.Owner{PlayerID: 1})
+	w.Attach(target, &core.Health{Current: 100, Max: 100})
+	if wallX >= 0 {
+		wall = spawnBuilding(w, "wall", 0, float64(wallX), 5, 1)
+		w.Attach(wall, &core.Health{Current: 500, Max: 500})
+		OccupyTiles(tm, wallX, 5, 1, 1)
+	}
+	return tm, shooter, target, wall
+}
+
+func duelPlayers() *core.PlayerManager {
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, TeamID: 0})
+	pm.AddPlayer(&core.Player{ID: 1, TeamID: 1})
+	return pm
+}
+
+func TestWallBlocksHitscan(t *testing.T) {
+	tests := []struct {
+		name    string
+		wallX   int
+		air     bool // target is an aircraft
+		wantHit bool
+	}{
+		{"open line", -1, false, true},
+		{"wall in between", 5, false, false},
+		{"wall behind the target", 10, false, true},
+		{"aircraft fly over walls", 5, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm, shooter, target, _ := duel(w, tc.wallX)
+			if tc.air {
+				mov, _ := core.GetComponent[*core.Movable](w, target)
+				mov.MoveType = core.MoveAir
+			}
+			w.AddSystem(&CombatSystem{Players: duelPlayers(), TileMap: tm})
+			w.Tick(0.05)
+
+			h, _ := core.GetComponent[*core.Health](w, target)
+			if hit := h.Current < 100; hit != tc.wantHit {
+				t.Errorf("target hit = %v, want %v", hit, tc.wantHit)
+			}
+			wep, _ := core.GetComponent[*core.Weapon](w, shooter)
+			if wep.Engaged != tc.wantHit {
+				t.Errorf("Engaged = %v, want %v", wep.Engaged, tc.wantHit)
+			}
+		})
+	}
+}
+
+func TestShooterCanHitTheWallItself(t *testing.T) {
+	w := core.NewWorld(20)
+	tm, _, target, wall := duel(w, 5)
+	w.Destroy(target)
+	own, _ := core.GetComponent[*core.Owner](w, wall)
+	own.PlayerID = 1
+	w.AddSystem(&CombatSystem{Players: duelPlayers(), TileMap: tm})
+	w.Tick(0.05)
+
+	if h, _ := core.GetComponent[*core.Health](w, wall); h.Current >= 500 {
+		t.Error("a wall's own footprint blocked shots at it")
+	}
+}
+
+func TestWallStopsProjectiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		wallX      int
+		splash     float64
+		wantTarget bool // target takes damage
+		wantWall   bool // wall takes damage
+	}{
+		{"open line", -1, 0, true, false},
+		{"wall absorbs a direct shot", 5, 0, false, false},
+		{"splash bursts on the wall", 5, 1, false, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm, shooter, target, wall := duel(w, tc.wallX)
+			// a shot already in flight, so only the projectile system decides
+			pid := w.Spawn()
+			w.Attach(pid, &core.Position{X: 2.5, Y: 5.5})
+			w.Attach(pid, &core.Projectile{SourceID: shooter, TargetID: target, TargetX: 8.5, TargetY: 5.5,
+				Speed: 8, Damage: 40, Splash: tc.splash, DmgType: core.DmgExplosive})
+			w.AddSystem(&ProjectileSystem{TileMap: tm})
+			for i := 0; i < 40 && w.Alive(pid); i++ {
+				w.Tick(0.05)
+			}
+
+			if w.Alive(pid) {
+				t.Fatal("projectile never landed")
+			}
+			if h, _ := core.GetComponent[*core.Health](w, target); (h.Current < 100) != tc.wantTarget {
+				t.Errorf("target hit = %v, want %v", h.Current < 100, tc.wantTarget)
+			}
+			if tc.wallX >= 0 {
+				if h, _ := core.GetComponent[*core.Health](w, wall); (h.Current < 500) != tc.wantWall {
+					t.Errorf("wall hit = %v, want %v", h.Current < 500, tc.wantWall)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyDamageFrom(t *testing.T) {
+	tests := []struct {
+		name         string
+		armor        *core.Armor
+		dmg          int
+		hp           int
+		attackerSide int // owner of the attacker, -1 for no attacker
+		wantHP       int
+		wantKilled   bool
+		wantAlert    bool // EvtUnderAttack published
+	}{
+		{"unarmored", nil, 30, 100, 1, 70, false, true},
+		{"armor type scales damage", &core.Armor{ArmorType: core.ArmorHeavy}, 50, 100, 1, 80, false, true},
+		{"armor value comes off first", &core.Armor{ArmorType: core.ArmorNone, Value: 10}, 30, 100, 1, 80, false, true},
+		{"at least one point gets through", &core.Armor{ArmorType: core.ArmorHeavy, Value: 500}, 30, 100, 1, 99, false, true},
+		{"lethal hit is credited", nil, 150, 100, 1, 0, true, true},
+		{"friendly fire raises no alert", nil, 30, 100, 0, 70, false, false},
+		{"unknown source raises no alert", nil, 30, 100, -1, 70, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			bus := core.NewEventBus()
+			victim := spawnGroundUnit(w, 5.5, 5.5, core.MoveVehicle)
+			w.Attach(victim, &core.Owner{PlayerID: 0})
+			w.Attach(victim, &core.Health{Current: tc.hp, Max: tc.hp})
+			if tc.armor != nil {
+				w.Attach(victim, tc.armor)
+			}
+			var attacker core.EntityID
+			if tc.attackerSide >= 0 {
+				attacker = spawnGroundUnit(w, 3.5, 5.5, core.MoveVehicle)
+				w.Attach(attacker, &core.Owner{PlayerID: tc.attackerSide})
+			}
+
+			var damaged []core.DamageEvent
+			var kills []core.KillEvent
+			alerts := 0
+			core.Subscribe(bus, core.EvtUnitDamaged, func(e core.DamageEvent) { damaged = append(damaged, e) })
+			core.Subscribe(bus, core.EvtUnitDestroyed, func(e core.KillEvent) { kills = append(kills, e) })
+			core.Subscribe(bus, core.EvtUnderAttack, func(core.UnderAttack) { alerts++ })
+
+			ApplyDamageFrom(w, attacker, victim, tc.dmg, core.DmgKinetic, bus)
+			// a second hit in the same tick doesn't count against the dead
+			if tc.wantKilled {
+				ApplyDamageFrom(w, attacker, victim, tc.dmg, core.DmgKinetic, bus)
+			}
+			bus.Dispatch()
+
+			h, _ := core.GetComponent[*core.Health](w, victim)
+			if h.Current != tc.wantHP {
+				t.Errorf("HP = %d, want %d", h.Current, tc.wantHP)
+			}
+			if len(damaged) != 1 {
+				t.Errorf("%d damage events, want 1", len(damaged))
+			} else if !tc.wantKilled && damaged[0].Amount != tc.hp-tc.wantHP {
+				t.Errorf("damage event for %d, want %d", damaged[0].Amount, tc.hp-tc.wantHP)
+			}
+			if (alerts > 0) != tc.wantAlert {
+				t.Errorf("%d under-attack alerts, want any = %v", alerts, tc.wantAlert)
+			}
+			if !tc.wantKilled {
+				if len(kills) != 0 {
+					t.Errorf("unexpected kill events %+v", kills)
+				}
+				return
+			}
+			if len(kills) != 1 {
+				t.Fatalf("%d kill events, want 1", len(kills))
+			}
+			if k := kills[0]; !k.Credited || k.Killer != attacker || k.KillerOwner != tc.attackerSide {
+				t.Errorf("kill %+v not credited to attacker %d of player %d", k, attacker, tc.attackerSide)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package systems
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// Crate bonus tuning
+const (
+	CrateCreditAmount  = 1000
+	CrateRevealSeconds = 30.0
+)
+
+// CrateSystem hands out crate bonuses to units that drive over them and
+// respawns crates on the map's CrateInterval
+type CrateSystem struct {
+	TileMap  *maplib.TileMap
+	TechTree *TechTree
+	Players  *core.PlayerManager
+	EventBus *core.EventBus
+	Fog      *FogSystem // optional: needed for the reveal bonus
+
+	rng   *rand.Rand
+	timer float64
+}
+
+// NewCrateSystem creates a crate system seeded for reproducible rolls
+func NewCrateSystem(tm *maplib.TileMap, tt *TechTree, pm *core.PlayerManager, bus *core.EventBus, fog *FogSystem, seed int64) *CrateSystem {
+	return &CrateSystem{
+		TileMap:  tm,
+		TechTree: tt,
+		Players:  pm,
+		EventBus: bus,
+		Fog:      fog,
+		rng:      rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (s *CrateSystem) Priority() int { return 40 }
+
+func (s *CrateSystem) Update(w *core.World, dt float64) {
+	crates := w.Query(core.CompCrate, core.CompPosition)
+	units := w.Query(core.CompPosition, core.CompMovable, core.CompOwner)
+	remaining := len(crates)
+	for _, cid := range crates {
+		cpos := w.Get(cid, core.CompPosition).(*core.Position)
+		cx, cy := int(cpos.X), int(cpos.Y)
+		for _, uid := range units {
+			own := w.Get(uid, core.CompOwner).(*core.Owner)
+			mov := w.Get(uid, core.CompMovable).(*core.Movable)
+			pos := w.Get(uid, core.CompPosition).(*core.Position)
+			if own.PlayerID == core.NeutralPlayerID || mov.MoveType == core.MoveAir {
+				continue
+			}
+			if int(pos.X) != cx || int(pos.Y) != cy {
+				continue
+			}
+			bonus := w.Get(cid, core.CompCrate).(*core.Crate).Bonus
+			s.collect(w, uid, own, bonus, cx, cy)
+			w.Destroy(cid)
+			remaining--
+			break
+		}
+	}
+
+	if s.TileMap == nil || s.TileMap.CrateInterval <= 0 {
+		return
+	}
+	s.timer += dt
+	if s.timer < s.TileMap.CrateInterval {
+		return
+	}
+	s.timer = 0
+	if remaining < s.TileMap.MaxCrates {
+		if x, y, ok := s.randomFreeTile(w); ok {
+			SpawnCrate(w, x, y, core.CrateRandom)
+		}
+	}
+}
+
+// collect applies a crate bonus to the unit that picked it up
+func (s *CrateSystem) collect(w *core.World, uid core.EntityID, own *core.Owner, bonus core.CrateBonus, x, y int) {
+	if bonus == core.CrateRandom {
+		bonus = core.CrateBonus(1 + s.rng.Intn(int(core.CrateUnit)))
+	}
+	// An elite unit can't be promoted further, so it gets money instead
+	if bonus == core.CrateVeterancy && !PromoteUnit(w, uid) {
+		bonus = core.CrateCredits
+	}
+	switch bonus {
+	case core.CrateCredits:
+		if p := s.Players.GetPlayer(own.PlayerID); p != nil {
+			p.Credits += CrateCreditAmount
+		}
+	case core.CrateReveal:
+		if s.Fog != nil && s.TileMap != nil {
+			s.Fog.RevealArea(own.PlayerID, 0, 0, s.TileMap.Width-1, s.TileMap.Height-1, CrateRevealSeconds)
+		}
+	case core.CrateUnit:
+		// the free unit turns up beside the crate, or on the nearest tile
+		// it can stand on
+		if key := s.freeUnitKey(own.Faction); key != "" {
+			SpawnUnitNear(w, s.TileMap, key, s.TechTree.Units[key], own.PlayerID, own.Faction, float64(x)+1.5, float64(y)+0.5)
+		}
+	}
+	if s.EventBus != nil {
+		s.EventBus.Publish(core.Event{Type: core.EvtCrateCollected, Tick: w.TickCount, Payload: core.CratePickup{
+			PlayerID: own.PlayerID, Unit: uid, Bonus: bonus, X: x, Y: y,
+		}})
+	}
+}
+
+// freeUnitKey picks a random armed ground unit available to the faction
+func (s *CrateSystem) freeUnitKey(faction string) string {
+	if s.TechTree == nil {
+		return ""
+	}
+	var keys []string
+	for key, u := range s.TechTree.Units {
+		if u.Damage > 0 && u.MoveType != core.MoveAir && u.MoveType != core.MoveNaval &&
+			(u.Faction == "" || u.Faction == faction) {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) == 0 {
+		return ""
+	}
+	sort.Strings(keys)
+	return keys[s.rng.Intn(len(keys))]
+}
+
+// randomFreeTile finds an open ground tile with no crate on it
+func (s *CrateSystem) randomFreeTile(w *core.World) (int, int, bool) {
+	tm := s.TileMap
+	taken := make(map[[2]int]bool)
+	for _, cid := range w.Query(core.CompCrate, core.CompPosition) {
+		pos := w.Get(cid, core.CompPosition).(*core.Position)
+		taken[[2]int{int(pos.X), int(pos.Y)}] = true
+	}
+	for attempt := 0; attempt < 50; attempt++ {
+		x, y := s.rng.Intn(tm.Width), s.rng.Intn(tm.Height)
+		t := tm.At(x, y)
+		if t == nil || t.Occupied || t.OreAmount > 0 || t.Passable&maplib.PassVehicle == 0 || taken[[2]int{x, y}] {
+			continue
+		}
+		return x, y, true
+	}
+	return 0, 0, false
+}
+
+// SpawnCrate places a crate on the tile at (x, y)
+func SpawnCrate(w *core.World, x, y int, bonus core.CrateBonus) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: float64(x) + 0.5, Y: float64(y) + 0.5})
+	w.Attach(id, &core.Crate{Bonus: bonus})
+	return id
+}
+
+// ParseCrateBonus maps a map-file crate key to a bonus; unknown keys are random
+func ParseCrateBonus(key string) core.CrateBonus {
+	switch key {
+	case "credits":
+		return core.CrateCredits
+	case "reveal":
+		return core.CrateReveal
+	case "veterancy":
+		return core.CrateVeterancy
+	case "unit":
+		return core.CrateUnit
+	}
+	return core.CrateRandom
+}
@@ -0,0 +1,142 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// crateRun has a unit of player 0 drive onto a crate at (5, 5) and ticks
+// until the bonus has landed
+func crateRun(t *testing.T, bonus core.CrateBonus, setup func(w *core.World, tm *maplib.TileMap, unit core.EntityID)) (*core.World, *maplib.TileMap, *core.PlayerManager, *FogSystem, core.EntityID) {
+	t.Helper()
+	w := core.NewWorld(20)
+	tm := maplib.NewTileMap("t", 16, 16)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, Credits: 500, Faction: "Allied"})
+	fog := NewFogSystem(16, 16, pm)
+	bus := core.NewEventBus()
+	var got []core.CratePickup
+	core.Subscribe(bus, core.EvtCrateCollected, func(e core.CratePickup) { got = append(got, e) })
+
+	unit := spawnGroundUnit(w, 5.5, 5.5, core.MoveVehicle)
+	w.Attach(unit, &core.Owner{PlayerID: 0, Faction: "Allied"})
+	w.Attach(unit, &core.Health{Current: 100, Max: 100})
+	crate := SpawnCrate(w, 5, 5, bonus)
+	if setup != nil {
+		setup(w, tm, unit)
+	}
+	w.AddSystem(fog)
+	w.AddSystem(NewCrateSystem(tm, NewTechTree(), pm, bus, fog, 1))
+	w.Tick(0.05)
+	w.Tick(0.05)
+	bus.Dispatch()
+
+	if w.Alive(crate) {
+		t.Fatal("crate was not picked up")
+	}
+	if len(got) != 1 || got[0].Unit != unit {
+		t.Fatalf("pickup events %+v, want one for unit %d", got, unit)
+	}
+	return w, tm, pm, fog, unit
+}
+
+func TestCrateCredits(t *testing.T) {
+	_, _, pm, _, _ := crateRun(t, core.CrateCredits, nil)
+	if got := pm.GetPlayer(0).Credits; got != 500+CrateCreditAmount {
+		t.Errorf("credits = %d, want %d", got, 500+CrateCreditAmount)
+	}
+}
+
+func TestCrateReveal(t *testing.T) {
+	_, _, _, fog, _ := crateRun(t, core.CrateReveal, nil)
+	for _, p := range [][2]int{{0, 0}, {15, 15}, {15, 0}} {
+		if !fog.Fogs[0].IsVisible(p[0], p[1]) {
+			t.Errorf("tile %v still dark after a reveal crate", p)
+		}
+	}
+}
+
+func TestCrateVeterancy(t *testing.T) {
+	tests := []struct {
+		name        string
+		rank        int
+		wantRank    int
+		wantCredits int
+	}{
+		{"rookie is promoted", 0, 1, 500},
+		{"veteran is promoted", 1, 2, 500},
+		{"elite gets money instead", core.MaxVeterancyRank, core.MaxVeterancyRank, 500 + CrateCreditAmount},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, _, pm, _, unit := crateRun(t, core.CrateVeterancy, func(w *core.World, _ *maplib.TileMap, unit core.EntityID) {
+				w.Attach(unit, &core.Veterancy{Rank: tc.rank})
+			})
+			vet, _ := core.GetComponent[*core.Veterancy](w, unit)
+			if vet.Rank != tc.wantRank {
+				t.Errorf("rank = %d, want %d", vet.Rank, tc.wantRank)
+			}
+			if got := pm.GetPlayer(0).Credits; got != tc.wantCredits {
+				t.Errorf("credits = %d, want %d", got, tc.wantCredits)
+			}
+		})
+	}
+}
+
+func TestCrateFreeUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		block   func(tm *maplib.TileMap)
+		wantX   int // tile the free unit lands on, -1 for anywhere free
+		wantOne bool
+	}{
+		{"beside the crate", func(*maplib.TileMap) {}, 6, true},
+		{"water beside the crate", func(tm *maplib.TileMap) { tm.SetTerrain(6, 4, 8, 6, maplib.TerrainWater) }, -1, true},
+		{"cliff beside the crate", func(tm *maplib.TileMap) { tm.SetTerrain(6, 5, 6, 5, maplib.TerrainCliff) }, -1, true},
+		{"building beside the crate", func(tm *maplib.TileMap) { OccupyTiles(tm, 6, 4, 2, 3) }, -1, true},
+		{"no ground in reach", func(tm *maplib.TileMap) {
+			tm.SetTerrain(0, 0, 15, 15, maplib.TerrainWater)
+			tm.SetTerrain(5, 5, 5, 5, maplib.TerrainGrass)
+		}, -1, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w, tm, _, _, unit := crateRun(t, core.CrateUnit, func(_ *core.World, tm *maplib.TileMap, _ core.EntityID) {
+				tc.block(tm)
+			})
+			var free []core.EntityID
+			for _, id := range w.Query(core.CompMovable, core.CompPosition) {
+				if id != unit {
+					free = append(free, id)
+				}
+			}
+			if !tc.wantOne {
+				if len(free) != 0 {
+					t.Errorf("%d free units spawned with nowhere to stand", len(free))
+				}
+				return
+			}
+			if len(free) != 1 {
+				t.Fatalf("%d free units, want 1", len(free))
+			}
+			id := free[0]
+			if own, _ := core.GetComponent[*core.Owner](w, id); own.PlayerID != 0 {
+				t.Errorf("free unit owned by %d, want 0", own.PlayerID)
+			}
+			pos, _ := core.GetComponent[*core.Position](w, id)
+			mov, _ := core.GetComponent[*core.Movable](w, id)
+			x, y := int(pos.X), int(pos.Y)
+			if tc.wantX >= 0 && (x != tc.wantX || y != 5) {
+				t.Errorf("free unit on (%d, %d), want (%d, 5)", x, y, tc.wantX)
+			}
+			if !tm.IsPassable(x, y, MovePassFlag(mov.MoveType)) {
+				t.Errorf("free unit on blocked tile (%d, %d)", x, y)
+			}
+			if x == 5 && y == 5 {
+				t.Error("free unit on top of the unit that collected it")
+			}
+		})
+	}
+}
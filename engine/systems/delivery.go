@@ -0,0 +1,121 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// Delivery tuning
+const (
+	DeliverySpeed    = 6.0 // tiles per second
+	DeliveryAltitude = 3.0 // cruising height; carriers dip to half of it at the drop
+	DeliveryBend     = 0.3 // sideways bend of the route as a fraction of its length
+)
+
+// DeliverySystem flies carriers along their delivery routes: in from the
+// map edge, unloading at the drop point, and back out again. Units that
+// found no room at the drop fly home with the carrier.
+type DeliverySystem struct {
+	TileMap  *maplib.TileMap
+	EventBus *core.EventBus
+}
+
+func (s *DeliverySystem) Priority() int { return 8 }
+
+func (s *DeliverySystem) Update(w *core.World, dt float64) {
+	for _, id := range w.Query(core.CompDelivery, core.CompPosition) {
+		d := w.Get(id, core.CompDelivery).(*core.Delivery)
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+
+		// The curve is a little longer than the chord; close enough for pacing
+		legLen := math.Hypot(d.DropX-d.EntryX, d.DropY-d.EntryY) * 1.1
+		if legLen < 1 {
+			legLen = 1
+		}
+		d.T += d.Speed * dt / legLen
+
+		if !d.Unloaded && d.T >= 1 {
+			for range UnloadAll(w, s.TileMap, id, d.DropX, d.DropY) {
+				if s.EventBus != nil {
+					s.EventBus.Publish(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
+				}
+			}
+			d.Unloaded = true
+			d.T = 0
+		} else if d.Unloaded && d.T >= 1 {
+			if t, ok := core.GetComponent[*core.Transport](w, id); ok {
+				for _, pid := range t.Passengers {
+					w.Destroy(pid)
+				}
+			}
+			w.Destroy(id)
+			continue
+		}
+
+		// Inbound runs Entry -> Drop, outbound retraces it backwards
+		t := d.T
+		if d.Unloaded {
+			t = 1 - t
+		}
+		x, y := bezier(d.EntryX, d.ControlX, d.DropX, t), bezier(d.EntryY, d.ControlY, d.DropY, t)
+		dx, dy := x-pos.X, y-pos.Y
+		if dx != 0 || dy != 0 {
+			pos.Facing = math.Atan2(dy, dx)
+		}
+		pos.X, pos.Y = x, y
+		pos.Z = DeliveryAltitude * (1 - 0.5*t)
+	}
+}
+
+// bezier evaluates a quadratic Bezier curve at t
+func bezier(p0, p1, p2, t float64) float64 {
+	u := 1 - t
+	return u*u*p0 + 2*u*t*p1 + t*t*p2
+}
+
+// SendDelivery spawns a carrier at the map edge nearest the drop point with
+// the given units aboard. It flies in, sets them down at (dropX, dropY) and
+// leaves the way it came.
+func SendDelivery(w *core.World, tm *maplib.TileMap, tt *TechTree, playerID int, faction string, units []string, dropX, dropY float64) core.EntityID {
+	ex, ey := nearestEdge(tm, dropX, dropY)
+	// Bend the route sideways so the approach reads as a flight path
+	mx, my := (ex+dropX)/2, (ey+dropY)/2
+	nx, ny := -(dropY - ey), dropX-ex
+	cid := w.Spawn()
+	w.Attach(cid, &core.Position{X: ex, Y: ey, Z: DeliveryAltitude})
+	w.Attach(cid, &core.Owner{PlayerID: playerID, Faction: faction})
+	w.Attach(cid, &core.Transport{Capacity: len(units)})
+	w.Attach(cid, &core.Delivery{
+		EntryX: ex, EntryY: ey,
+		ControlX: mx + nx*DeliveryBend, ControlY: my + ny*DeliveryBend,
+		DropX: dropX, DropY: dropY,
+		Speed: DeliverySpeed,
+	})
+
+	for _, key := range units {
+		udef, ok := tt.Units[key]
+		if !ok {
+			continue
+		}
+		LoadUnit(w, cid, SpawnUnit(w, key, udef, playerID, faction, dropX, dropY))
+	}
+	return cid
+}
+
+// nearestEdge returns the point just off the map edge closest to (x, y)
+func nearestEdge(tm *maplib.TileMap, x, y float64) (float64, float64) {
+	w, h := float64(tm.Width), float64(tm.Height)
+	best, ex, ey := x, -2.0, y
+	if d := w - x; d < best {
+		best, ex, ey = d, w+2, y
+	}
+	if y < best {
+		best, ex, ey = y, x, -2
+	}
+	if d := h - y; d < best {
+		ex, ey = x, h+2
+	}
+	return ex, ey
+}
@@ -0,0 +1,145 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// MCVDeployTime is how long an MCV takes to unpack, or a Construction Yard to pack up, in seconds
+const MCVDeployTime = 3.0
+
+// ConYardSize is the Construction Yard footprint in tiles
+const ConYardSize = 3
+
+// DeploySystem finishes timed MCV deploys and Construction Yard undeploys.
+// Tiles are occupied only once the yard stands and freed once it has packed
+// up. A deploy whose footprint got blocked meanwhile is called off.
+type DeploySystem struct {
+	TileMap  *maplib.TileMap // optional
+	EventBus *core.EventBus
+}
+
+func (s *DeploySystem) Priority() int { return 7 }
+
+func (s *DeploySystem) Update(w *core.World, dt float64) {
+	for _, id := range w.Query(core.CompDeploying, core.CompPosition) {
+		// A unit killed mid-deploy just dies; nothing gets built
+		if hp := w.Get(id, core.CompHealth); hp != nil && hp.(*core.Health).Current <= 0 {
+			continue
+		}
+		d := w.Get(id, core.CompDeploying).(*core.Deploying)
+		d.Timer += dt
+		if d.Timer < d.Duration {
+			continue
+		}
+		// Siege units switch mode in place and stay the same entity
+		if sg := w.Get(id, core.CompSiege); sg != nil {
+			sg.(*core.Siege).Deployed = !d.Undeploy
+			if wep, ok := core.GetComponent[*core.Weapon](w, id); ok {
+				sg.(*core.Siege).Apply(wep)
+			}
+			w.Detach(id, core.CompDeploying)
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		tx, ty := int(pos.X), int(pos.Y)
+		if d.Undeploy {
+			size := ConYardSize
+			if b := w.Get(id, core.CompBuilding); b != nil {
+				size = b.(*core.Building).SizeX
+			}
+			if s.TileMap != nil {
+				FreeTiles(s.TileMap, tx, ty, size, size)
+			}
+			UndeployConYard(w, id, s.EventBus)
+			continue
+		}
+		if s.TileMap != nil && !CanDeploy(w, s.TileMap, id) {
+			w.Detach(id, core.CompDeploying)
+			continue
+		}
+		if DeployMCV(w, id, s.EventBus) != 0 && s.TileMap != nil {
+			OccupyTiles(s.TileMap, tx, ty, ConYardSize, ConYardSize)
+		}
+	}
+}
+
+// StartDeploy begins unpacking an MCV where it stands. It stops moving and
+// stays vulnerable until the Construction Yard appears. Returns false if
+// the yard's footprint is blocked.
+func StartDeploy(w *core.World, tm *maplib.TileMap, mcvID core.EntityID) bool {
+	// An MCV without a Position is riding in a transport
+	if !w.Has(mcvID, core.CompMCV) || !w.Has(mcvID, core.CompPosition) || w.Has(mcvID, core.CompDeploying) {
+		return false
+	}
+	if !CanDeploy(w, tm, mcvID) {
+		return false
+	}
+	if mov := w.Get(mcvID, core.CompMovable); mov != nil {
+		m := mov.(*core.Movable)
+		m.Path = nil
+		m.PathIdx = 0
+	}
+	w.Attach(mcvID, &core.Deploying{Duration: MCVDeployTime})
+	return true
+}
+
+// CanDeploy reports whether an MCV has room to unpack where it stands: the
+// yard's footprint, with its corner on the MCV's tile, must be buildable
+// ground with no other ground unit on it
+func CanDeploy(w *core.World, tm *maplib.TileMap, mcvID core.EntityID) bool {
+	pos, ok := core.GetComponent[*core.Position](w, mcvID)
+	if !ok {
+		return false
+	}
+	return FootprintClear(w, tm, int(pos.X), int(pos.Y), ConYardSize, ConYardSize, mcvID)
+}
+
+// SiegeDeployTime is how long a siege unit takes to set up or pack up, in seconds
+const SiegeDeployTime = 2.0
+
+// ToggleSiege starts deploying a mobile siege unit or packing up a deployed
+// one. It stops moving meanwhile and can't fire until done.
+func ToggleSiege(w *core.World, id core.EntityID) bool {
+	sg := w.Get(id, core.CompSiege)
+	if sg == nil || !w.Has(id, core.CompPosition) || w.Has(id, core.CompDeploying) {
+		return false
+	}
+	if mov := w.Get(id, core.CompMovable); mov != nil {
+		m := mov.(*core.Movable)
+		m.Path = nil
+		m.PathIdx = 0
+	}
+	w.Attach(id, &core.Deploying{Duration: SiegeDeployTime, Undeploy: sg.(*core.Siege).Deployed})
+	return true
+}
+
+// DeployAll handles the deploy order for a selection: MCVs unpack where they
+// stand, Construction Yards pack up and siege units switch mode. Returns
+// false if an MCV was refused because its footprint is blocked.
+func DeployAll(w *core.World, tm *maplib.TileMap, ids []core.EntityID) bool {
+	ok := true
+	for _, id := range ids {
+		if StartDeploy(w, tm, id) || StartUndeploy(w, id) || ToggleSiege(w, id) {
+			continue
+		}
+		if w.Has(id, core.CompMCV) && !w.Has(id, core.CompDeploying) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// StartUndeploy begins packing a finished Construction Yard back into an MCV.
+// Production pauses meanwhile and nothing is refunded.
+func StartUndeploy(w *core.World, cyID core.EntityID) bool {
+	b := w.Get(cyID, core.CompBuilding)
+	if b == nil || !b.(*core.Building).IsConYard || w.Has(cyID, core.CompDeploying) {
+		return false
+	}
+	if bc := w.Get(cyID, core.CompBuildingConstruction); bc != nil && !bc.(*core.BuildingConstruction).Complete {
+		return false
+	}
+	w.Attach(cyID, &core.Deploying{Duration: MCVDeployTime, Undeploy: true})
+	return true
+}
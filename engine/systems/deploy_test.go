@@ -0,0 +1,198 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func spawnMCV(w *core.World, x, y float64) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: x, Y: y})
+	w.Attach(id, &core.Movable{Speed: 1, MoveType: core.MoveVehicle})
+	w.Attach(id, &core.Health{Current: 1000, Max: 1000})
+	w.Attach(id, &core.Owner{PlayerID: 0})
+	w.Attach(id, &core.MCV{CanDeploy: true})
+	return id
+}
+
+func TestStartDeployChecksFootprint(t *testing.T) {
+	tests := []struct {
+		name  string
+		x, y  float64
+		setup func(w *core.World, tm *maplib.TileMap)
+		want  bool
+	}{
+		{"open ground", 4.5, 4.5, func(*core.World, *maplib.TileMap) {}, true},
+		{"unit in the way", 4.5, 4.5, func(w *core.World, _ *maplib.TileMap) {
+			spawnGroundUnit(w, 6.5, 6.5, core.MoveVehicle)
+		}, false},
+		{"water under the yard", 4.5, 4.5, func(_ *core.World, tm *maplib.TileMap) {
+			tm.At(5, 6).Terrain = maplib.TerrainWater
+		}, false},
+		{"building in the way", 4.5, 4.5, func(_ *core.World, tm *maplib.TileMap) {
+			tm.SetOccupied(6, 4, true)
+		}, false},
+		{"yard would run off the map", 14.5, 4.5, func(*core.World, *maplib.TileMap) {}, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			tc.setup(w, tm)
+			mcv := spawnMCV(w, tc.x, tc.y)
+			if got := StartDeploy(w, tm, mcv); got != tc.want {
+				t.Fatalf("StartDeploy = %v, want %v", got, tc.want)
+			}
+			if w.Has(mcv, core.CompDeploying) != tc.want {
+				t.Errorf("Deploying attached = %v, want %v", w.Has(mcv, core.CompDeploying), tc.want)
+			}
+		})
+	}
+}
+
+func TestDeployCalledOffWhenBlocked(t *testing.T) {
+	tests := []struct {
+		name      string
+		blockAt   float64 // a unit drives onto this tile mid-deploy; 0 for none
+		wantYard  bool
+		wantTiles bool
+	}{
+		{"clear", 0, true, true},
+		{"unit drove on", 5.5, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			mcv := spawnMCV(w, 4.5, 4.5)
+			if !StartDeploy(w, tm, mcv) {
+				t.Fatal("StartDeploy refused open ground")
+			}
+			if tc.blockAt > 0 {
+				spawnGroundUnit(w, tc.blockAt, tc.blockAt, core.MoveInfantry)
+			}
+			sys := &DeploySystem{TileMap: tm}
+			sys.Update(w, MCVDeployTime+0.1)
+
+			yards := 0
+			for _, id := range w.Query(core.CompBuilding) {
+				if w.Get(id, core.CompBuilding).(*core.Building).IsConYard {
+					yards++
+				}
+			}
+			if (yards == 1) != tc.wantYard {
+				t.Errorf("yards = %d, want yard %v", yards, tc.wantYard)
+			}
+			if tm.At(5, 5).Occupied != tc.wantTiles {
+				t.Errorf("footprint occupied = %v, want %v", tm.At(5, 5).Occupied, tc.wantTiles)
+			}
+			if !tc.wantYard {
+				if !w.Alive(mcv) || w.Has(mcv, core.CompDeploying) {
+					t.Error("blocked MCV should stay an MCV and stop deploying")
+				}
+			}
+		})
+	}
+}
+
+// finishDeploying ticks the deploy system until every switch in progress is done
+func finishDeploying(w *core.World) {
+	for i := 0; i < int(SiegeDeployTime/0.05)+2; i++ {
+		w.Tick(0.05)
+	}
+}
+
+func TestSiegeWeaponByMode(t *testing.T) {
+	tt := NewTechTree()
+	sieged := tt.Units["v3"].Siege
+	tests := []struct {
+		name       string
+		promotions int
+		deploy     bool
+		wantDamage int
+		wantRange  float64
+	}{
+		{"mobile", 0, false, 0, 0},
+		{"deployed", 0, true, sieged.Damage, sieged.Range},
+		{"veteran deployed", 1, true, sieged.Damage * 6 / 5, sieged.Range},
+		{"elite deployed", 2, true, sieged.Damage * 6 / 5 * 6 / 5, sieged.Range},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			w.AddSystem(&DeploySystem{})
+			id := SpawnUnit(w, "v3", tt.Units["v3"], 0, "Soviet", 4.5, 4.5)
+			for i := 0; i < tc.promotions; i++ {
+				PromoteUnit(w, id)
+			}
+			if tc.deploy {
+				if !ToggleSiege(w, id) {
+					t.Fatal("ToggleSiege failed")
+				}
+				finishDeploying(w)
+			}
+			wep, _ := core.GetComponent[*core.Weapon](w, id)
+			if wep.Damage != tc.wantDamage || wep.Range != tc.wantRange {
+				t.Errorf("weapon %d dmg / %.1f range, want %d / %.1f", wep.Damage, wep.Range, tc.wantDamage, tc.wantRange)
+			}
+		})
+	}
+}
+
+func TestSiegeKeepsPromotionAcrossModes(t *testing.T) {
+	tt := NewTechTree()
+	w := core.NewWorld(20)
+	w.AddSystem(&DeploySystem{})
+	w.AddSystem(&CombatSystem{})
+	id := SpawnUnit(w, "v3", tt.Units["v3"], 0, "Soviet", 4.5, 4.5)
+	ToggleSiege(w, id)
+	finishDeploying(w)
+	PromoteUnit(w, id)
+	want := tt.Units["v3"].Siege.Damage * 6 / 5
+
+	ToggleSiege(w, id)
+	finishDeploying(w)
+	ToggleSiege(w, id)
+	finishDeploying(w)
+	if wep, _ := core.GetComponent[*core.Weapon](w, id); wep.Damage != want {
+		t.Errorf("damage %d after packing up and redeploying, want %d", wep.Damage, want)
+	}
+}
+
+func TestDeployAllSwitchesEveryUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		blocked bool
+		wantOK  bool
+	}{
+		{"MCV and siege units", false, true},
+		{"MCV blocked", true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tt := NewTechTree()
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 32, 32)
+			mcv := spawnMCV(w, 4.5, 4.5)
+			if tc.blocked {
+				tm.SetOccupied(5, 5, true)
+			}
+			v1 := SpawnUnit(w, "v3", tt.Units["v3"], 0, "Soviet", 12.5, 4.5)
+			v2 := SpawnUnit(w, "v3", tt.Units["v3"], 0, "Soviet", 14.5, 4.5)
+
+			if got := DeployAll(w, tm, []core.EntityID{mcv, v1, v2}); got != tc.wantOK {
+				t.Errorf("DeployAll = %v, want %v", got, tc.wantOK)
+			}
+			if w.Has(mcv, core.CompDeploying) == tc.blocked {
+				t.Errorf("MCV deploying = %v, want %v", !tc.blocked, tc.blocked)
+			}
+			for _, id := range []core.EntityID{v1, v2} {
+				if !w.Has(id, core.CompDeploying) {
+					t.Errorf("siege unit %d didn't start deploying", id)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package systems
+
+import (
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// Environment tuning
+const (
+	DayLength     = 480.0 // seconds for a full day/night cycle
+	NightVision   = 0.6   // sight multiplier at midnight
+	RainVision    = 0.85  // sight multiplier in rain
+	MistVision    = 0.65  // sight multiplier in fog
+	FlareRadius   = 5     // tiles lit by a flare
+	FlareTime     = 10.0  // seconds a flare burns
+	FlareCooldown = 30.0  // seconds between flares per player
+)
+
+// EnvironmentSystem drives the time of day and weather. FogSystem scales
+// sight ranges by VisionScale and the renderer tints the scene from
+// Darkness. Maps turn the cycle and weather on with DayNight and Weather.
+type EnvironmentSystem struct {
+	DayNight  bool
+	Weather   maplib.Weather
+	TimeOfDay float64    // 0..1 through the day; 0 is midnight, 0.5 noon
+	Fog       *FogSystem // optional: needed for flares
+
+	flareCooldown map[int]float64 // playerID -> seconds until the next flare
+}
+
+// NewEnvironmentSystem reads the map's environment settings; the cycle
+// starts at mid-morning
+func NewEnvironmentSystem(tm *maplib.TileMap, fog *FogSystem) *EnvironmentSystem {
+	return &EnvironmentSystem{
+		DayNight:      tm.DayNight,
+		Weather:       tm.Weather,
+		TimeOfDay:     0.35,
+		Fog:           fog,
+		flareCooldown: make(map[int]float64),
+	}
+}
+
+func (s *EnvironmentSystem) Priority() int { return 1 }
+
+func (s *EnvironmentSystem) Update(w *core.World, dt float64) {
+	if s.DayNight {
+		s.TimeOfDay = math.Mod(s.TimeOfDay+dt/DayLength, 1)
+	}
+	for id, cd := range s.flareCooldown {
+		if cd -= dt; cd <= 0 {
+			delete(s.flareCooldown, id)
+		} else {
+			s.flareCooldown[id] = cd
+		}
+	}
+}
+
+// Darkness returns 0 at noon (or with the cycle off) up to 1 at midnight
+func (s *EnvironmentSystem) Darkness() float64 {
+	if !s.DayNight {
+		return 0
+	}
+	return 0.5 + 0.5*math.Cos(2*math.Pi*s.TimeOfDay)
+}
+
+// VisionScale returns the sight multiplier for the current time and weather.
+// Lit viewers (searchlights) ignore the dark but not the weather.
+func (s *EnvironmentSystem) VisionScale(lit bool) float64 {
+	scale := 1.0
+	if !lit {
+		scale = 1 - (1-NightVision)*s.Darkness()
+	}
+	switch s.Weather {
+	case maplib.WeatherRain:
+		scale *= RainVision
+	case maplib.WeatherFog:
+		scale *= MistVision
+	}
+	return scale
+}
+
+// Flare lights up the area around (x, y) for a player and reports whether
+// one was ready
+func (s *EnvironmentSystem) Flare(playerID, x, y int) bool {
+	if s.Fog == nil || s.flareCooldown[playerID] > 0 {
+		return false
+	}
+	s.Fog.RevealArea(playerID, x-FlareRadius, y-FlareRadius, x+FlareRadius, y+FlareRadius, FlareTime)
+	s.flareCooldown[playerID] = FlareCooldown
+	return true
+}
@@ -0,0 +1,97 @@
+package systems
+
+import (
+	"math"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestVisionScale(t *testing.T) {
+	tests := []struct {
+		name     string
+		dayNight bool
+		time     float64
+		weather  maplib.Weather
+		lit      bool
+		want     float64
+	}{
+		{"cycle off", false, 0, maplib.WeatherClear, false, 1},
+		{"noon", true, 0.5, maplib.WeatherClear, false, 1},
+		{"midnight", true, 0, maplib.WeatherClear, false, NightVision},
+		{"dusk", true, 0.25, maplib.WeatherClear, false, (1 + NightVision) / 2},
+		{"searchlight at midnight", true, 0, maplib.WeatherClear, true, 1},
+		{"rain at noon", true, 0.5, maplib.WeatherRain, false, RainVision},
+		{"fog at midnight", true, 0, maplib.WeatherFog, false, NightVision * MistVision},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := &EnvironmentSystem{DayNight: tc.dayNight, TimeOfDay: tc.time, Weather: tc.weather}
+			if got := s.VisionScale(tc.lit); math.Abs(got-tc.want) > 1e-9 {
+				t.Errorf("VisionScale = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNightShrinksVision(t *testing.T) {
+	tests := []struct {
+		name     string
+		time     float64
+		building bool
+		far      bool // whether a tile 7 tiles away is seen
+	}{
+		{"unit at noon", 0.5, false, true},
+		{"unit at midnight", 0, false, false},
+		{"powered building at midnight", 0, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0})
+			fog := NewFogSystem(32, 32, pm)
+			fog.Environment = &EnvironmentSystem{DayNight: true, TimeOfDay: tc.time}
+			w := core.NewWorld(20)
+			id := w.Spawn()
+			w.Attach(id, &core.Position{X: 16.5, Y: 16.5})
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			w.Attach(id, &core.FogVision{Range: 8})
+			if tc.building {
+				w.Attach(id, &core.Building{SizeX: 1, SizeY: 1, PowerDraw: 10})
+			}
+			fog.Update(w, 0.05)
+
+			f := fog.Fogs[0]
+			if !f.IsVisible(20, 16) {
+				t.Error("a tile 4 away should always be seen")
+			}
+			if got := f.IsVisible(23, 16); got != tc.far {
+				t.Errorf("tile 7 away visible = %v, want %v", got, tc.far)
+			}
+		})
+	}
+}
+
+func TestFlareCooldown(t *testing.T) {
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0})
+	fog := NewFogSystem(32, 32, pm)
+	env := &EnvironmentSystem{Fog: fog, flareCooldown: map[int]float64{}}
+	w := core.NewWorld(20)
+
+	if !env.Flare(0, 10, 10) {
+		t.Fatal("first flare refused")
+	}
+	fog.Update(w, 0.05)
+	if !fog.Fogs[0].IsVisible(10+FlareRadius, 10) {
+		t.Error("flare didn't light its radius")
+	}
+	if env.Flare(0, 20, 20) {
+		t.Error("second flare fired during the cooldown")
+	}
+	env.Update(w, FlareCooldown)
+	if !env.Flare(0, 20, 20) {
+		t.Error("flare not ready after the cooldown")
+	}
+}
@@ -0,0 +1,123 @@
+package systems
+
+import (
+	"math/rand"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+// Forest tuning
+const (
+	ForestCover        = 0.25 // damage reduction for infantry standing in forest
+	ForestBurnTime     = 12.0 // seconds a forest tile burns; long enough to burn it down
+	ForestBurnDPS      = 30.0 // forest damage per second while burning
+	ForestSpreadChance = 0.08 // chance per second that fire jumps to each forest neighbour
+)
+
+// ForestSystem burns forest tiles, spreads fire to neighbouring trees and
+// reopens the nav grid when a tile is cleared to dirt
+type ForestSystem struct {
+	TileMap *maplib.TileMap
+	NavGrid *pathfind.NavGrid // optional: refreshed when forest is cleared
+
+	rng  *rand.Rand
+	burn map[int]float64 // tile index -> fractional burn damage carried over
+}
+
+// NewForestSystem creates a forest system seeded for reproducible fire spread
+func NewForestSystem(tm *maplib.TileMap, ng *pathfind.NavGrid, seed int64) *ForestSystem {
+	return &ForestSystem{
+		TileMap: tm,
+		NavGrid: ng,
+		rng:     rand.New(rand.NewSource(seed)),
+		burn:    make(map[int]float64),
+	}
+}
+
+func (s *ForestSystem) Priority() int { return 45 }
+
+func (s *ForestSystem) Update(w *core.World, dt float64) {
+	tm := s.TileMap
+	var ignite [][2]int
+	for i := range tm.Tiles {
+		t := &tm.Tiles[i]
+		if t.Terrain != maplib.TerrainForest {
+			continue
+		}
+		if t.Burning > 0 {
+			ignite = s.burnTile(i, dt, ignite)
+		}
+	}
+	for _, p := range ignite {
+		tm.At(p[0], p[1]).Burning = ForestBurnTime
+	}
+
+	// Fire or shelling may have cleared tiles; reopen them to vehicles
+	if s.NavGrid != nil {
+		s.NavGrid.Sync(tm)
+	}
+}
+
+// burnTile burns down the forest tile at index i and appends any neighbours
+// the fire spreads to
+func (s *ForestSystem) burnTile(i int, dt float64, ignite [][2]int) [][2]int {
+	tm := s.TileMap
+	t := &tm.Tiles[i]
+	x, y := i%tm.Width, i/tm.Width
+	t.Burning -= dt
+
+	s.burn[i] += ForestBurnDPS * dt
+	dmg := int(s.burn[i])
+	s.burn[i] -= float64(dmg)
+	if tm.DamageForest(x, y, dmg) || t.Burning <= 0 {
+		t.Burning = 0
+		delete(s.burn, i)
+		return ignite
+	}
+
+	for _, d := range [4][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		n := tm.At(x+d[0], y+d[1])
+		if n != nil && n.Terrain == maplib.TerrainForest && n.Burning <= 0 && s.rng.Float64() < ForestSpreadChance*dt {
+			ignite = append(ignite, [2]int{x + d[0], y + d[1]})
+		}
+	}
+	return ignite
+}
+
+// hitForest applies a weapon hit to the tile at (x, y): explosives wear a
+// forest down and fire sets it alight
+func hitForest(tm *maplib.TileMap, x, y, dmg int, dmgType core.DamageType) {
+	if tm == nil {
+		return
+	}
+	t := tm.At(x, y)
+	if t == nil || t.Terrain != maplib.TerrainForest {
+		return
+	}
+	switch dmgType {
+	case core.DmgExplosive:
+		tm.DamageForest(x, y, dmg)
+	case core.DmgFire:
+		t.Burning = ForestBurnTime
+	}
+}
+
+// forestCover returns the damage multiplier for an entity's position:
+// infantry in forest take ForestCover less damage
+func forestCover(w *core.World, tm *maplib.TileMap, id core.EntityID) float64 {
+	if tm == nil {
+		return 1
+	}
+	mov := w.Get(id, core.CompMovable)
+	pos := w.Get(id, core.CompPosition)
+	if mov == nil || pos == nil || mov.(*core.Movable).MoveType != core.MoveInfantry {
+		return 1
+	}
+	p := pos.(*core.Position)
+	if t := tm.At(int(p.X), int(p.Y)); t != nil && t.Terrain == maplib.TerrainForest {
+		return 1 - ForestCover
+	}
+	return 1
+}
@@ -0,0 +1,92 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+func TestForestBlocksVehiclesOnly(t *testing.T) {
+	tm := maplib.NewTileMap("t", 16, 16)
+	tm.SetTerrain(8, 0, 8, 15, maplib.TerrainForest) // a tree line across the map
+	ng := pathfind.NewNavGrid(tm)
+
+	tests := []struct {
+		name string
+		flag maplib.PassFlag
+		want bool
+	}{
+		{"infantry walks through", maplib.PassInfantry, true},
+		{"vehicle is stopped", maplib.PassVehicle, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := ng.Passable(8, 4, tc.flag); got != tc.want {
+				t.Errorf("Passable = %v, want %v", got, tc.want)
+			}
+			path := pathfind.FindPath(ng, 2, 4, 14, 4, tc.flag)
+			if (len(path) > 0) != tc.want {
+				t.Errorf("found a path across the trees = %v, want %v", len(path) > 0, tc.want)
+			}
+		})
+	}
+}
+
+func TestForestCover(t *testing.T) {
+	tm := maplib.NewTileMap("t", 16, 16)
+	tm.SetTerrain(4, 4, 4, 4, maplib.TerrainForest)
+	tests := []struct {
+		name     string
+		x, y     float64
+		moveType core.MoveType
+		want     float64
+	}{
+		{"infantry in forest", 4.5, 4.5, core.MoveInfantry, 1 - ForestCover},
+		{"infantry in the open", 6.5, 4.5, core.MoveInfantry, 1},
+		{"vehicle beside forest", 4.5, 4.5, core.MoveVehicle, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			id := spawnGroundUnit(w, tc.x, tc.y, tc.moveType)
+			if got := forestCover(w, tm, id); got != tc.want {
+				t.Errorf("forestCover = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestForestClearedByExplosivesAndFire(t *testing.T) {
+	tests := []struct {
+		name    string
+		dmgType core.DamageType
+		hits    int
+		burn    float64 // seconds of ForestSystem updates afterwards
+	}{
+		{"shelled flat", core.DmgExplosive, maplib.ForestHP / 50, 0},
+		{"burned down", core.DmgFire, 1, ForestBurnTime},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tm := maplib.NewTileMap("t", 8, 8)
+			tm.SetTerrain(3, 3, 3, 3, maplib.TerrainForest)
+			ng := pathfind.NewNavGrid(tm)
+			for i := 0; i < tc.hits; i++ {
+				hitForest(tm, 3, 3, 50, tc.dmgType)
+			}
+			fs := NewForestSystem(tm, ng, 1)
+			for tt := 0.0; tt < tc.burn; tt += 0.05 {
+				fs.Update(core.NewWorld(20), 0.05)
+			}
+			fs.Update(core.NewWorld(20), 0.05)
+			if got := tm.At(3, 3).Terrain; got != maplib.TerrainDirt {
+				t.Fatalf("terrain = %v, want dirt", got)
+			}
+			if !ng.Passable(3, 3, maplib.PassVehicle) {
+				t.Error("cleared tile is still closed to vehicles")
+			}
+		})
+	}
+}
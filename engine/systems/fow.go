@@ -1,7 +1,10 @@
 package systems
 
 import (
+	"math"
+
 	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
 )
 
 // FogState represents visibility of a tile
@@ -18,6 +21,8 @@ type FogOfWar struct {
 	Width, Height int
 	Grid          []FogState // per-tile fog state
 	PlayerID      int
+
+	refs []uint16 // viewers and reveals currently lighting each tile
 }
 
 func NewFogOfWar(w, h, playerID int) *FogOfWar {
@@ -26,6 +31,21 @@ func NewFogOfWar(w, h, playerID int) *FogOfWar {
 		Height:   h,
 		Grid:     make([]FogState, w*h),
 		PlayerID: playerID,
+		refs:     make([]uint16, w*h),
+	}
+}
+
+// light adds a viewer to a tile, making it visible
+func (f *FogOfWar) light(i int) {
+	f.refs[i]++
+	f.Grid[i] = FogVisible
+}
+
+// unlight removes a viewer from a tile; the last one leaves it explored
+func (f *FogOfWar) unlight(i int) {
+	f.refs[i]--
+	if f.refs[i] == 0 {
+		f.Grid[i] = FogExplored
 	}
 }
 
@@ -46,6 +66,47 @@ func (f *FogOfWar) IsVisible(x, y int) bool {
 type FogSystem struct {
 	Fogs    map[int]*FogOfWar // playerID -> fog
 	Players *core.PlayerManager
+	TileMap *maplib.TileMap // optional: enables elevation line-of-sight
+
+	// ForestBlocks makes forest tiles hide what lies behind them
+	ForestBlocks bool
+
+	// Environment scales sight ranges for night and weather (optional)
+	Environment *EnvironmentSystem
+
+	reveals []fogReveal
+	viewers map[core.EntityID]*fogViewer
+	stamp   uint64 // Update count, marks viewers still in the world
+}
+
+// FogRefreshTime is how often a viewer that hasn't moved recasts its sight,
+// picking up terrain changes such as cleared forest or new buildings
+const FogRefreshTime = 2.0
+
+// fogViewer is one entity's lit tiles, kept until it moves or changes
+type fogViewer struct {
+	x, y, r, owner int
+	tiles          []int       // tile indices this viewer lights
+	fogs           []*FogOfWar // owner's and allies' fogs
+	age            float64     // seconds since the tiles were cast
+	seen           uint64
+}
+
+// fogReveal is a scripted reveal of part of the map
+type fogReveal struct {
+	playerID       int
+	x1, y1, x2, y2 int
+	remaining      float64 // seconds left, or < 0 for permanent
+	lit            bool
+}
+
+// RevealArea makes a rectangle visible to a player for the given number
+// of seconds, or permanently when seconds <= 0.
+func (s *FogSystem) RevealArea(playerID, x1, y1, x2, y2 int, seconds float64) {
+	if seconds <= 0 {
+		seconds = -1
+	}
+	s.reveals = append(s.reveals, fogReveal{playerID, x1, y1, x2, y2, seconds, false})
 }
 
 func NewFogSystem(w, h int, pm *core.PlayerManager) *FogSystem {
@@ -61,59 +122,227 @@ func NewFogSystem(w, h int, pm *core.PlayerManager) *FogSystem {
 
 func (s *FogSystem) Priority() int { return 2 }
 
-func (s *FogSystem) Update(w *core.World, _ float64) {
-	// Demote all visible to explored
-	for _, fog := range s.Fogs {
-		for i := range fog.Grid {
-			if fog.Grid[i] == FogVisible {
-				fog.Grid[i] = FogExplored
+// Update only recasts viewers that moved, changed range or owner, or are
+// due a refresh; everything else keeps lighting the tiles it lit before.
+func (s *FogSystem) Update(w *core.World, dt float64) {
+	if s.viewers == nil {
+		s.viewers = make(map[core.EntityID]*fogViewer)
+	}
+	s.stamp++
+
+	// Scripted reveals
+	active := s.reveals[:0]
+	for _, r := range s.reveals {
+		if !r.lit {
+			s.lightArea(r, (*FogOfWar).light)
+			r.lit = true
+		}
+		if r.remaining > 0 {
+			r.remaining -= dt
+			if r.remaining <= 0 {
+				s.lightArea(r, (*FogOfWar).unlight)
+				continue
 			}
 		}
+		active = append(active, r)
 	}
+	s.reveals = active
 
 	// Reveal tiles around units with FogVision
-	units := w.Query(core.CompPosition, core.CompFogVision, core.CompOwner)
-	for _, id := range units {
+	for _, id := range w.Query(core.CompPosition, core.CompFogVision, core.CompOwner) {
 		pos := w.Get(id, core.CompPosition).(*core.Position)
 		vis := w.Get(id, core.CompFogVision).(*core.FogVision)
 		own := w.Get(id, core.CompOwner).(*core.Owner)
-
-		fog := s.Fogs[own.PlayerID]
-		if fog == nil {
+		if s.Fogs[own.PlayerID] == nil {
 			continue
 		}
 
 		cx, cy := int(pos.X), int(pos.Y)
-		r := vis.Range
-		for dy := -r; dy <= r; dy++ {
-			for dx := -r; dx <= r; dx++ {
-				if dx*dx+dy*dy <= r*r {
-					tx, ty := cx+dx, cy+dy
-					if tx >= 0 && ty >= 0 && tx < fog.Width && ty < fog.Height {
-						fog.Grid[ty*fog.Width+tx] = FogVisible
-					}
-				}
+		r := s.visionRange(w, id, vis.Range)
+		v := s.viewers[id]
+		if v == nil {
+			v = &fogViewer{}
+			s.viewers[id] = v
+		}
+		v.seen = s.stamp
+		v.age += dt
+		if v.tiles != nil && v.x == cx && v.y == cy && v.r == r && v.owner == own.PlayerID && v.age < FogRefreshTime {
+			continue
+		}
+		s.unlightViewer(v)
+		*v = fogViewer{x: cx, y: cy, r: r, owner: own.PlayerID, tiles: v.tiles[:0], seen: s.stamp}
+		s.castViewer(w, id, v)
+	}
+
+	// Viewers that died, lost their vision or were loaded into a transport
+	for id, v := range s.viewers {
+		if v.seen != s.stamp {
+			s.unlightViewer(v)
+			delete(s.viewers, id)
+		}
+	}
+}
+
+// castViewer lights what a viewer sees for its owner and allies
+func (s *FogSystem) castViewer(w *core.World, id core.EntityID, v *fogViewer) {
+	for _, p := range s.Players.Players {
+		if p.ID == v.owner || s.Players.AreAllies(v.owner, p.ID) {
+			if f := s.Fogs[p.ID]; f != nil {
+				v.fogs = append(v.fogs, f)
 			}
 		}
+	}
+	fog := s.Fogs[v.owner]
+	shadowcast(v.x, v.y, v.r, fog.Width, fog.Height, s.sightBlocker(w, id, v.x, v.y), func(x, y int) {
+		v.tiles = append(v.tiles, y*fog.Width+x)
+	})
+	for _, f := range v.fogs {
+		for _, i := range v.tiles {
+			f.light(i)
+		}
+	}
+}
+
+// unlightViewer withdraws a viewer's tiles from every fog it lit
+func (s *FogSystem) unlightViewer(v *fogViewer) {
+	for _, f := range v.fogs {
+		for _, i := range v.tiles {
+			f.unlight(i)
+		}
+	}
+}
+
+// lightArea applies op to every tile of a scripted reveal
+func (s *FogSystem) lightArea(r fogReveal, op func(*FogOfWar, int)) {
+	fog := s.Fogs[r.playerID]
+	if fog == nil {
+		return
+	}
+	for y := max(r.y1, 0); y <= min(r.y2, fog.Height-1); y++ {
+		for x := max(r.x1, 0); x <= min(r.x2, fog.Width-1); x++ {
+			op(fog, y*fog.Width+x)
+		}
+	}
+}
+
+// Invalidate makes every viewer recast its sight on the next Update, for
+// terrain changes that shouldn't wait for FogRefreshTime
+func (s *FogSystem) Invalidate() {
+	for _, v := range s.viewers {
+		v.age = FogRefreshTime
+	}
+}
+
+// visionRange scales a sight range for night and weather. Powered buildings
+// light their surroundings with searchlights and keep their range at night.
+func (s *FogSystem) visionRange(w *core.World, id core.EntityID, r int) int {
+	if s.Environment == nil {
+		return r
+	}
+	lit := false
+	if b := w.Get(id, core.CompBuilding); b != nil && !b.(*core.Building).PoweredDown {
+		lit = true
+	}
+	scaled := int(math.Round(float64(r) * s.Environment.VisionScale(lit)))
+	if scaled < 1 {
+		return 1
+	}
+	return scaled
+}
+
+// sightBlocker returns which tiles the viewer can't see past: ground higher
+// than the viewer's own, buildings other than the viewer itself and, with
+// ForestBlocks, forest. Blocking tiles are still seen; what lies behind
+// them is not, so high ground sees over low ground but not the reverse.
+func (s *FogSystem) sightBlocker(w *core.World, id core.EntityID, cx, cy int) func(x, y int) bool {
+	if s.TileMap == nil {
+		return func(int, int) bool { return false }
+	}
+	eye := s.TileMap.HeightAt(cx, cy)
+	x0, y0, x1, y1 := cx, cy, cx, cy // the viewer's own footprint
+	if b, ok := core.GetComponent[*core.Building](w, id); ok {
+		x1, y1 = cx+b.SizeX-1, cy+b.SizeY-1
+	}
+	return func(x, y int) bool {
+		t := s.TileMap.At(x, y)
+		if t == nil {
+			return true
+		}
+		if int(t.Height) > eye {
+			return true
+		}
+		if t.Occupied && (x < x0 || x > x1 || y < y0 || y > y1) {
+			return true
+		}
+		return s.ForestBlocks && t.Terrain == maplib.TerrainForest
+	}
+}
+
+// octants maps shadowcasting's one-octant coordinates onto the eight
+// octants around the viewer: x = col*xx + row*xy, y = col*yx + row*yy
+var octants = [8][4]int{
+	{1, 0, 0, 1}, {0, 1, 1, 0}, {0, -1, 1, 0}, {-1, 0, 0, 1},
+	{-1, 0, 0, -1}, {0, -1, -1, 0}, {0, 1, -1, 0}, {1, 0, 0, -1},
+}
+
+// shadowcast calls visit for every in-bounds tile within r of (cx, cy)
+// that the viewer can see, using recursive shadowcasting
+func shadowcast(cx, cy, r, width, height int, opaque func(x, y int) bool, visit func(x, y int)) {
+	inBounds := func(x, y int) bool { return x >= 0 && y >= 0 && x < width && y < height }
+	if inBounds(cx, cy) {
+		visit(cx, cy)
+	}
+	for _, o := range octants {
+		castLight(cx, cy, 1, 1.0, 0.0, r, o, inBounds, opaque, visit)
+	}
+}
 
-		// Also reveal for allies
-		for _, p := range s.Players.Players {
-			if p.ID != own.PlayerID && s.Players.AreAllies(own.PlayerID, p.ID) {
-				afog := s.Fogs[p.ID]
-				if afog == nil {
+// castLight scans one octant row by row, narrowing the lit slope range
+// [end, start] as blocking tiles throw shadows
+func castLight(cx, cy, row int, start, end float64, r int, o [4]int, inBounds, opaque func(x, y int) bool, visit func(x, y int)) {
+	if start < end {
+		return
+	}
+	newStart := 0.0
+	for j := row; j <= r; j++ {
+		blocked := false
+		dy := -j
+		for dx := -j; dx <= 0; dx++ {
+			lSlope := (float64(dx) - 0.5) / (float64(dy) + 0.5)
+			rSlope := (float64(dx) + 0.5) / (float64(dy) - 0.5)
+			if start < rSlope {
+				continue
+			}
+			if end > lSlope {
+				break
+			}
+			x, y := cx+dx*o[0]+dy*o[1], cy+dx*o[2]+dy*o[3]
+			wall := !inBounds(x, y) || opaque(x, y)
+			if dx*dx+dy*dy <= r*r && inBounds(x, y) {
+				visit(x, y)
+			}
+			if blocked {
+				if wall {
+					newStart = rSlope
 					continue
 				}
-				for dy := -r; dy <= r; dy++ {
-					for dx := -r; dx <= r; dx++ {
-						if dx*dx+dy*dy <= r*r {
-							tx, ty := cx+dx, cy+dy
-							if tx >= 0 && ty >= 0 && tx < afog.Width && ty < afog.Height {
-								afog.Grid[ty*afog.Width+tx] = FogVisible
-							}
-						}
-					}
-				}
+				blocked = false
+				start = newStart
+			} else if wall && j < r {
+				blocked = true
+				castLight(cx, cy, j+1, start, lSlope, r, o, inBounds, opaque, visit)
+				newStart = rSlope
 			}
 		}
+		if blocked {
+			break
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
 	}
+	return x
 }
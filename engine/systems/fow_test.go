@@ -0,0 +1,187 @@
+package systems
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// fogScene is a viewer at (4, 10) looking east along row 10 with one
+// feature placed at (7, 10)
+func fogScene(t *testing.T, eye int8, feature func(tm *maplib.TileMap), forest bool) *FogOfWar {
+	t.Helper()
+	w := core.NewWorld(20)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0})
+	tm := maplib.NewTileMap("t", 32, 32)
+	tm.At(4, 10).Height = eye
+	feature(tm)
+	fog := NewFogSystem(32, 32, pm)
+	fog.TileMap = tm
+	fog.ForestBlocks = forest
+	w.AddSystem(fog)
+
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: 4.5, Y: 10.5})
+	w.Attach(id, &core.Owner{PlayerID: 0})
+	w.Attach(id, &core.FogVision{Range: 8})
+	w.Tick(0.05)
+	return fog.Fogs[0]
+}
+
+func TestFogShadows(t *testing.T) {
+	cliff := func(tm *maplib.TileMap) { tm.At(7, 10).Height = 2 }
+	tests := []struct {
+		name        string
+		eye         int8
+		feature     func(tm *maplib.TileMap)
+		forest      bool
+		wantBlocker bool // the feature tile itself is seen
+		wantBehind  bool // (10, 10), behind the feature
+	}{
+		{"open ground", 0, func(*maplib.TileMap) {}, false, true, true},
+		{"cliff", 0, cliff, false, true, false},
+		{"viewer on equal high ground", 2, cliff, false, true, true},
+		{"building", 0, func(tm *maplib.TileMap) { tm.SetOccupied(7, 10, true) }, false, true, false},
+		{"forest that blocks", 0, func(tm *maplib.TileMap) { tm.At(7, 10).Terrain = maplib.TerrainForest }, true, true, false},
+		{"forest that doesn't", 0, func(tm *maplib.TileMap) { tm.At(7, 10).Terrain = maplib.TerrainForest }, false, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := fogScene(t, tc.eye, tc.feature, tc.forest)
+			if f.IsVisible(7, 10) != tc.wantBlocker {
+				t.Errorf("feature visible = %v, want %v", f.IsVisible(7, 10), tc.wantBlocker)
+			}
+			if f.IsVisible(10, 10) != tc.wantBehind {
+				t.Errorf("tile behind visible = %v, want %v", f.IsVisible(10, 10), tc.wantBehind)
+			}
+			// Shadows only fall behind the feature, never to the sides
+			if !f.IsVisible(4, 14) || !f.IsVisible(1, 10) {
+				t.Error("open tiles off to the side are hidden")
+			}
+		})
+	}
+}
+
+func TestShadowcastStaysInRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		cx, cy int
+		r      int
+	}{
+		{"centre", 16, 16, 6},
+		{"corner", 0, 0, 6},
+		{"off the edge", 31, 5, 8},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			seen := map[[2]int]int{}
+			shadowcast(tc.cx, tc.cy, tc.r, 32, 32, func(int, int) bool { return false }, func(x, y int) {
+				seen[[2]int{x, y}]++
+			})
+			for p := range seen {
+				dx, dy := p[0]-tc.cx, p[1]-tc.cy
+				if p[0] < 0 || p[1] < 0 || p[0] >= 32 || p[1] >= 32 {
+					t.Errorf("visited out-of-bounds tile %v", p)
+				}
+				if dx*dx+dy*dy > tc.r*tc.r {
+					t.Errorf("visited %v beyond range %d", p, tc.r)
+				}
+			}
+			for y := max(tc.cy-tc.r, 0); y <= min(tc.cy+tc.r, 31); y++ {
+				for x := max(tc.cx-tc.r, 0); x <= min(tc.cx+tc.r, 31); x++ {
+					dx, dy := x-tc.cx, y-tc.cy
+					if dx*dx+dy*dy <= tc.r*tc.r && seen[[2]int{x, y}] == 0 {
+						t.Errorf("open tile (%d, %d) in range not visited", x, y)
+					}
+				}
+			}
+		})
+	}
+}
+
+// fogArmy spreads n viewers (a tenth of them buildings) over a 128x128 map
+func fogArmy(n int) (*core.World, *FogSystem, []core.EntityID) {
+	w := core.NewWorld(20)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0})
+	pm.AddPlayer(&core.Player{ID: 1, TeamID: 1})
+	tm := maplib.NewTileMap("t", 128, 128)
+	fog := NewFogSystem(128, 128, pm)
+	fog.TileMap = tm
+	w.AddSystem(fog)
+	var units []core.EntityID
+	for i := 0; i < n; i++ {
+		id := w.Spawn()
+		w.Attach(id, &core.Position{X: float64(i*37%120) + 4, Y: float64(i*53%120) + 4})
+		w.Attach(id, &core.Owner{PlayerID: i % 2})
+		w.Attach(id, &core.FogVision{Range: 7})
+		if i%10 == 0 {
+			w.Attach(id, &core.Building{SizeX: 2, SizeY: 2})
+			continue
+		}
+		units = append(units, id)
+	}
+	return w, fog, units
+}
+
+// march moves every nth unit one tile, wrapping around the map
+func march(w *core.World, units []core.EntityID, every int) {
+	for i := 0; i < len(units); i += every {
+		pos := w.Get(units[i], core.CompPosition).(*core.Position)
+		pos.X = float64((int(pos.X)+1)%124) + 2.5
+	}
+}
+
+func TestIncrementalFogMatchesFullRecast(t *testing.T) {
+	w, fog, units := fogArmy(200)
+	for i := 0; i < 30; i++ {
+		march(w, units, 1+i%4)
+		w.Tick(0.05)
+	}
+	before := map[int][]FogState{}
+	for id, f := range fog.Fogs {
+		before[id] = append([]FogState(nil), f.Grid...)
+	}
+	fog.Invalidate()
+	w.Tick(0.05) // nothing moved, so a full recast must change nothing
+	for id, f := range fog.Fogs {
+		for i, got := range f.Grid {
+			if got != before[id][i] {
+				t.Fatalf("player %d tile %d: incremental %v, full recast %v", id, i, before[id][i], got)
+			}
+		}
+	}
+}
+
+func BenchmarkFogUpdate(b *testing.B) {
+	for _, n := range []int{100, 500} {
+		for _, tc := range []struct {
+			name  string
+			every int  // move every nth unit per tick; 0 for none
+			full  bool // recast every viewer every tick, as before caching
+		}{
+			{"idle", 0, false},
+			{"tenth-moving", 10, false},
+			{"all-moving", 1, false},
+			{"full-recompute", 10, true},
+		} {
+			b.Run(fmt.Sprintf("%s/%d", tc.name, n), func(b *testing.B) {
+				w, fog, units := fogArmy(n)
+				w.Tick(0.05)
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					if tc.every > 0 {
+						march(w, units, tc.every)
+					}
+					if tc.full {
+						fog.Invalidate()
+					}
+					w.Tick(0.05)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+// Gate tuning
+const (
+	GateRange    = 1.8 // tiles within which a friendly unit opens a gate
+	GateOpenTime = 0.6 // seconds to swing fully open or shut
+	GateHoldTime = 1.5 // seconds a gate stays open after the last friend passes
+)
+
+// GateSystem opens gates for approaching friendly units, closes them after
+// and registers them with the nav grid so enemies path around
+type GateSystem struct {
+	NavGrid *pathfind.NavGrid
+	Players *core.PlayerManager
+
+	cells map[core.EntityID]core.TilePos // gates registered with the nav grid
+}
+
+func (s *GateSystem) Priority() int { return 9 }
+
+func (s *GateSystem) Update(w *core.World, dt float64) {
+	if s.cells == nil {
+		s.cells = make(map[core.EntityID]core.TilePos)
+	}
+
+	units := w.Query(core.CompPosition, core.CompMovable, core.CompOwner)
+	for _, id := range w.Query(core.CompGate, core.CompPosition, core.CompOwner) {
+		g := w.Get(id, core.CompGate).(*core.Gate)
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		owner := w.Get(id, core.CompOwner).(*core.Owner).PlayerID
+		if _, ok := s.cells[id]; !ok {
+			c := core.TilePos{X: int(pos.X), Y: int(pos.Y)}
+			s.NavGrid.SetGate(c.X, c.Y, owner)
+			s.cells[id] = c
+		}
+
+		centre := core.Position{X: pos.X + 0.5, Y: pos.Y + 0.5}
+		for _, uid := range units {
+			if s.friendly(owner, w.Get(uid, core.CompOwner).(*core.Owner).PlayerID) &&
+				centre.DistanceTo(w.Get(uid, core.CompPosition).(*core.Position)) <= GateRange {
+				g.Hold = GateHoldTime
+				break
+			}
+		}
+		if g.Hold > 0 {
+			g.Hold -= dt
+			g.Open = min(g.Open+dt/GateOpenTime, 1)
+		} else {
+			g.Open = max(g.Open-dt/GateOpenTime, 0)
+		}
+	}
+}
+
+// OnDespawn leaves an open gap where a destroyed or sold gate stood
+func (s *GateSystem) OnDespawn(_ *core.World, id core.EntityID) {
+	s.unregister(id)
+}
+
+// OnDetach unregisters an entity that stops being a gate
+func (s *GateSystem) OnDetach(_ *core.World, id core.EntityID, ct core.ComponentType) {
+	if ct == core.CompGate {
+		s.unregister(id)
+	}
+}
+
+// unregister removes a gate from the nav grid
+func (s *GateSystem) unregister(id core.EntityID) {
+	if c, ok := s.cells[id]; ok {
+		s.NavGrid.ClearGate(c.X, c.Y)
+		delete(s.cells, id)
+	}
+}
+
+// friendly reports whether a gate owned by owner opens for player
+func (s *GateSystem) friendly(owner, player int) bool {
+	return owner == player || (s.Players != nil && s.Players.AreAllies(owner, player))
+}
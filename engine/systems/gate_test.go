@@ -0,0 +1,116 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+// gatedWall is a map split by a water channel at x=8 with a single gate
+// owned by player 0 at (8, 8)
+func gatedWall(w *core.World, pm *core.PlayerManager) (*pathfind.NavGrid, core.EntityID) {
+	tm := maplib.NewTileMap("t", 16, 16)
+	tm.SetTerrain(8, 0, 8, 15, maplib.TerrainWater)
+	tm.SetTerrain(8, 8, 8, 8, maplib.TerrainGrass)
+	ng := pathfind.NewNavGrid(tm)
+	ng.Friendly = pm.AreAllies
+
+	gate := w.Spawn()
+	w.Attach(gate, &core.Position{X: 8, Y: 8})
+	w.Attach(gate, &core.Owner{PlayerID: 0})
+	w.Attach(gate, &core.Gate{})
+	w.AddSystem(&GateSystem{NavGrid: ng, Players: pm})
+	w.Tick(0.05) // registers the gate
+	return ng, gate
+}
+
+func gatePlayers() *core.PlayerManager {
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, TeamID: 0})
+	pm.AddPlayer(&core.Player{ID: 1, TeamID: 0})
+	pm.AddPlayer(&core.Player{ID: 2, TeamID: 1})
+	return pm
+}
+
+func TestGatePassesFriendsOnly(t *testing.T) {
+	tests := []struct {
+		name   string
+		player int
+		want   bool
+	}{
+		{"owner", 0, true},
+		{"ally", 1, true},
+		{"enemy", 2, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			ng, _ := gatedWall(w, gatePlayers())
+			id := spawnGroundUnit(w, 3.5, 8.5, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: tc.player})
+
+			OrderMove(w, ng, id, 13, 8)
+			m, _ := core.GetComponent[*core.Movable](w, id)
+			if m.Unreachable == tc.want {
+				t.Errorf("unreachable = %v, want %v", m.Unreachable, !tc.want)
+			}
+			if pathCrosses(w, id, 8, 8) != tc.want {
+				t.Errorf("path through the gate = %v, want %v", !tc.want, tc.want)
+			}
+		})
+	}
+}
+
+func TestGateOpensForApproachingFriends(t *testing.T) {
+	tests := []struct {
+		name     string
+		player   int
+		x        float64
+		wantOpen bool
+	}{
+		{"friend beside the gate", 0, 7.5, true},
+		{"ally beside the gate", 1, 7.5, true},
+		{"enemy beside the gate", 2, 7.5, false},
+		{"friend far away", 0, 2.5, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			_, gate := gatedWall(w, gatePlayers())
+			id := spawnGroundUnit(w, tc.x, 8.5, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: tc.player})
+
+			for i := 0; i < 20; i++ {
+				w.Tick(0.05)
+			}
+			g, _ := core.GetComponent[*core.Gate](w, gate)
+			if (g.Open == 1) != tc.wantOpen {
+				t.Fatalf("open = %.2f, want open %v", g.Open, tc.wantOpen)
+			}
+
+			// Once the unit leaves the gate shuts again after its hold time
+			w.Get(id, core.CompPosition).(*core.Position).X = 1
+			for i := 0; i < int((GateHoldTime+GateOpenTime)/0.05)+2; i++ {
+				w.Tick(0.05)
+			}
+			if g.Open != 0 {
+				t.Errorf("gate still %.2f open after the unit left", g.Open)
+			}
+		})
+	}
+}
+
+func TestRemovedGateStopsBlocking(t *testing.T) {
+	w := core.NewWorld(20)
+	ng, gate := gatedWall(w, gatePlayers())
+	if ng.GateOpensFor(8, 8, 2) {
+		t.Fatal("gate open to an enemy")
+	}
+	w.Destroy(gate)
+	w.Tick(0.05)
+	if !ng.GateOpensFor(8, 8, 2) {
+		t.Error("destroyed gate still blocks")
+	}
+}
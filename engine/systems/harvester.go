@@ -28,8 +28,15 @@ func (s *HarvesterSystem) Update(w *core.World, dt float64) {
 
 		switch harv.State {
 		case core.HarvIdle:
-			// Find nearest ore
-			ox, oy := s.findNearestOre(int(pos.X), int(pos.Y))
+			// Work the assigned patch until it runs dry, then the nearest ore
+			ox, oy := -1, -1
+			if harv.HasField {
+				ox, oy = s.findFieldOre(harv.Field)
+				harv.HasField = ox >= 0
+			}
+			if ox < 0 {
+				ox, oy = s.findNearestOre(int(pos.X), int(pos.Y))
+			}
 			if ox >= 0 {
 				harv.State = core.HarvMovingToOre
 				OrderMove(w, s.NavGrid, id, ox, oy)
@@ -81,8 +88,15 @@ func (s *HarvesterSystem) Update(w *core.World, dt float64) {
 			}
 
 		case core.HarvReturning:
+			// Cargo only counts once it reaches a building; a harvester
+			// that ends up anywhere else goes back to work and tries again
+			// when it is full
 			if mov.PathIdx >= len(mov.Path) {
-				harv.State = core.HarvUnloading
+				if atDock(w, id, pos) {
+					harv.State = core.HarvUnloading
+				} else {
+					harv.State = core.HarvIdle
+				}
 			}
 
 		case core.HarvUnloading:
@@ -92,13 +106,19 @@ func (s *HarvesterSystem) Update(w *core.World, dt float64) {
 				if harv.Resource == "gem" {
 					value = harv.Current * 50
 				}
-				player.Credits += value
+				wasted := player.StoreOre(value)
 				if s.EventBus != nil {
-					s.EventBus.Emit(core.Event{Type: core.EvtResourceHarvested, Tick: w.TickCount})
+					s.EventBus.Publish(core.Event{Type: core.EvtResourceHarvested, Tick: w.TickCount, Payload: core.HarvestEvent{PlayerID: player.ID, Amount: value - wasted}})
+					if wasted > 0 {
+						s.EventBus.Publish(core.Event{Type: core.EvtOreWasted, Tick: w.TickCount, Payload: core.OreWasted{PlayerID: player.ID, Amount: wasted}})
+					}
 				}
 			}
 			harv.Current = 0
 			harv.State = core.HarvIdle
+
+		case core.HarvStopped:
+			// Waits for the player; OrderMove sends it back to work
 		}
 	}
 }
@@ -123,23 +143,96 @@ func (s *HarvesterSystem) findNearestOre(fx, fy int) (int, int) {
 	return bx, by
 }
 
+// FieldRadius is how far from its assigned tile a harvester's patch reaches
+const FieldRadius = 4
+
+// findFieldOre returns the ore tile within FieldRadius closest to the centre
+// of an assigned patch, or -1, -1 once the patch is mined out
+func (s *HarvesterSystem) findFieldOre(f core.TilePos) (int, int) {
+	bestDist := math.MaxFloat64
+	bx, by := -1, -1
+	for y := f.Y - FieldRadius; y <= f.Y+FieldRadius; y++ {
+		for x := f.X - FieldRadius; x <= f.X+FieldRadius; x++ {
+			t := s.TileMap.At(x, y)
+			if t == nil || t.OreAmount <= 0 {
+				continue
+			}
+			dx := float64(x - f.X)
+			dy := float64(y - f.Y)
+			if d := dx*dx + dy*dy; d < bestDist {
+				bestDist = d
+				bx, by = x, y
+			}
+		}
+	}
+	return bx, by
+}
+
+// AssignField sends a harvester to mine around (x, y) from now on
+func AssignField(w *core.World, ng *pathfind.NavGrid, id core.EntityID, x, y int) bool {
+	h, ok := core.GetComponent[*core.Harvester](w, id)
+	if !ok {
+		return false
+	}
+	h.Field = core.TilePos{X: x, Y: y}
+	h.HasField = true
+	h.State = core.HarvMovingToOre
+	OrderMove(w, ng, id, x, y)
+	return true
+}
+
+// returnToRefinery sends a loaded harvester to the free tile beside the
+// nearest own building it can reach. Footprints block the nav grid, so it
+// docks next to the building rather than on it.
 func (s *HarvesterSystem) returnToRefinery(w *core.World, id core.EntityID, pos *core.Position, mov *core.Movable) {
-	// Find nearest own refinery/construction yard
 	own := w.Get(id, core.CompOwner).(*core.Owner)
-	buildings := w.Query(core.CompPosition, core.CompBuilding, core.CompOwner)
+	flag := MovePassFlag(mov.MoveType)
+	tx, ty := int(pos.X), int(pos.Y)
 	bestDist := math.MaxFloat64
-	bx, by := int(pos.X), int(pos.Y)
-	for _, bid := range buildings {
-		bown := w.Get(bid, core.CompOwner).(*core.Owner)
-		if bown.PlayerID != own.PlayerID {
+	bx, by := -1, -1
+	for _, bid := range w.Query(core.CompPosition, core.CompBuilding, core.CompOwner) {
+		if w.Get(bid, core.CompOwner).(*core.Owner).PlayerID != own.PlayerID {
 			continue
 		}
 		bpos := w.Get(bid, core.CompPosition).(*core.Position)
-		d := pos.DistanceTo(bpos)
-		if d < bestDist {
-			bestDist = d
-			bx, by = int(bpos.X), int(bpos.Y)
+		b := w.Get(bid, core.CompBuilding).(*core.Building)
+		x0, y0 := int(bpos.X), int(bpos.Y)
+		for y := y0 - 1; y <= y0+b.SizeY; y++ {
+			for x := x0 - 1; x <= x0+b.SizeX; x++ {
+				edge := x == x0-1 || x == x0+b.SizeX || y == y0-1 || y == y0+b.SizeY
+				if !edge || !s.NavGrid.PassableFor(x, y, flag, own.PlayerID) {
+					continue
+				}
+				dx, dy := float64(x-tx), float64(y-ty)
+				if d := dx*dx + dy*dy; d < bestDist {
+					bestDist = d
+					bx, by = x, y
+				}
+			}
 		}
 	}
+	if bx < 0 {
+		mov.Path = nil
+		mov.PathIdx = 0
+		return
+	}
 	OrderMove(w, s.NavGrid, id, bx, by)
 }
+
+// atDock reports whether a harvester stands next to a building of its owner
+func atDock(w *core.World, id core.EntityID, pos *core.Position) bool {
+	owner := ownerOf(w, id)
+	tx, ty := int(pos.X), int(pos.Y)
+	for _, bid := range w.Query(core.CompPosition, core.CompBuilding, core.CompOwner) {
+		if w.Get(bid, core.CompOwner).(*core.Owner).PlayerID != owner {
+			continue
+		}
+		bpos := w.Get(bid, core.CompPosition).(*core.Position)
+		b := w.Get(bid, core.CompBuilding).(*core.Building)
+		x0, y0 := int(bpos.X), int(bpos.Y)
+		if tx >= x0-1 && tx <= x0+b.SizeX && ty >= y0-1 && ty <= y0+b.SizeY {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+func TestHarvesterPrefersAssignedField(t *testing.T) {
+	near := core.TilePos{X: 8, Y: 5}
+	far := core.TilePos{X: 25, Y: 25}
+	tests := []struct {
+		name      string
+		assign    bool
+		farOre    int
+		wantGoal  core.TilePos
+		wantField bool
+	}{
+		{"no field goes to the nearest ore", false, 500, near, false},
+		{"assigned far field", true, 500, far, true},
+		{"field mined out falls back to nearest", true, 0, near, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 32, 32)
+			tm.At(near.X, near.Y).OreAmount = 500
+			tm.At(far.X, far.Y).OreAmount = tc.farOre
+			ng := pathfind.NewNavGrid(tm)
+			w.AddSystem(&HarvesterSystem{NavGrid: ng, TileMap: tm})
+
+			id := spawnGroundUnit(w, 5.5, 5.5, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			h := &core.Harvester{Capacity: 20, Rate: 2, Resource: "ore"}
+			w.Attach(id, h)
+			if tc.assign {
+				if !AssignField(w, ng, id, far.X, far.Y) {
+					t.Fatal("AssignField failed")
+				}
+				h.State = core.HarvIdle // as if it had just unloaded
+			}
+
+			w.Tick(0.05)
+			m, _ := core.GetComponent[*core.Movable](w, id)
+			if len(m.Path) == 0 {
+				t.Fatal("harvester didn't set off")
+			}
+			if goal := m.Path[len(m.Path)-1]; goal != tc.wantGoal {
+				t.Errorf("heading to %v, want %v", goal, tc.wantGoal)
+			}
+			if h.HasField != tc.wantField {
+				t.Errorf("HasField = %v, want %v", h.HasField, tc.wantField)
+			}
+		})
+	}
+}
+
+func TestAssignFieldNeedsHarvester(t *testing.T) {
+	w := core.NewWorld(20)
+	ng := pathfind.NewNavGrid(maplib.NewTileMap("t", 16, 16))
+	tank := spawnGroundUnit(w, 2.5, 2.5, core.MoveVehicle)
+	if AssignField(w, ng, tank, 8, 8) {
+		t.Error("AssignField accepted a unit that isn't a harvester")
+	}
+	if m, _ := core.GetComponent[*core.Movable](w, tank); len(m.Path) != 0 {
+		t.Error("AssignField moved a unit that isn't a harvester")
+	}
+}
+
+func TestHarvestCycleAroundRefinery(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(tm *maplib.TileMap) // terrain change that rebuilds the nav grid
+	}{
+		{"nav grid as built", nil},
+		{"nav grid rebuilt after a forest tile is cleared", func(tm *maplib.TileMap) {
+			tm.DamageForest(20, 20, maplib.ForestHP)
+		}},
+		{"nav grid rebuilt after a bridge collapses", func(tm *maplib.TileMap) {
+			tm.DamageBridge(25, 5, maplib.BridgeHP)
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 32, 32)
+			tm.SetTerrain(20, 20, 20, 20, maplib.TerrainForest)
+			tm.SetTerrain(25, 0, 25, 31, maplib.TerrainWater)
+			tm.SetTerrain(25, 5, 25, 5, maplib.TerrainBridge)
+			tm.At(17, 11).OreAmount = 500
+			ng := pathfind.NewNavGrid(tm)
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0, OreCapacity: 10000})
+
+			// The footprint is marked after the grid is built, as at game start
+			spawnBuilding(w, "refinery", 0, 10, 10, 3)
+			OccupyTiles(tm, 10, 10, 3, 3)
+			w.AddSystem(&MovementSystem{NavGrid: ng, TileMap: tm})
+			w.AddSystem(&HarvesterSystem{NavGrid: ng, TileMap: tm, Players: pm})
+			w.AddSystem(NewForestSystem(tm, ng, 1))
+			w.AddSystem(&BridgeSystem{TileMap: tm, NavGrid: ng})
+
+			id := spawnGroundUnit(w, 16.5, 11.5, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			h := &core.Harvester{Capacity: 20, Rate: 2, Resource: "ore"}
+			w.Attach(id, h)
+			if tc.change != nil {
+				tc.change(tm)
+			}
+
+			p := pm.GetPlayer(0)
+			for i := 0; i < 1000 && p.Credits == 0; i++ {
+				w.Tick(0.05)
+			}
+			if p.Credits != 20*25 {
+				t.Fatalf("credits = %d after the cycle, want %d", p.Credits, 20*25)
+			}
+			if ng.Passable(10, 10, maplib.PassVehicle) {
+				t.Error("refinery footprint is open on the nav grid")
+			}
+			pos, _ := core.GetComponent[*core.Position](w, id)
+			x, y := int(pos.X), int(pos.Y)
+			inside := x >= 10 && x <= 12 && y >= 10 && y <= 12
+			beside := x >= 9 && x <= 13 && y >= 9 && y <= 13
+			if inside || !beside {
+				t.Errorf("unloaded at (%d, %d), want a tile beside the refinery", x, y)
+			}
+
+			// and it heads back out for the next load
+			for i := 0; i < 200 && h.State != core.HarvHarvesting; i++ {
+				w.Tick(0.05)
+			}
+			if h.State != core.HarvHarvesting {
+				t.Errorf("harvester state = %v after unloading, want harvesting", h.State)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+// MindLinkRangeMult is how far past weapon range a captive may stray before
+// the link snaps
+const MindLinkRangeMult = 2.5
+
+// MindControlSystem releases captives whose controller has died or who have
+// strayed beyond link range
+type MindControlSystem struct {
+	links map[core.EntityID]*core.MindControl // controllers seen last tick
+}
+
+func (s *MindControlSystem) Priority() int { return 26 }
+
+func (s *MindControlSystem) Update(w *core.World, dt float64) {
+	if s.links == nil {
+		s.links = make(map[core.EntityID]*core.MindControl)
+	}
+	for _, cid := range w.Query(core.CompMindControl) {
+		mc := w.Get(cid, core.CompMindControl).(*core.MindControl)
+		s.links[cid] = mc
+		if hp := w.Get(cid, core.CompHealth); hp != nil && hp.(*core.Health).Current <= 0 {
+			releaseAll(w, mc)
+			continue
+		}
+		cpos := w.Get(cid, core.CompPosition)
+		kept := mc.Captives[:0]
+		for _, c := range mc.Captives {
+			vpos := w.Get(c.ID, core.CompPosition)
+			if !w.Has(c.ID, core.CompOwner) {
+				continue // captive died
+			}
+			// Captives carried in a transport have no position; the link holds
+			if cpos != nil && vpos != nil && mc.LinkRange > 0 &&
+				cpos.(*core.Position).DistanceTo(vpos.(*core.Position)) > mc.LinkRange {
+				release(w, c)
+				continue
+			}
+			kept = append(kept, c)
+		}
+		mc.Captives = kept
+	}
+}
+
+// OnDespawn frees everything a removed controller held
+func (s *MindControlSystem) OnDespawn(w *core.World, id core.EntityID) {
+	s.unlink(w, id)
+}
+
+// OnDetach frees a controller's captives when it loses the ability
+func (s *MindControlSystem) OnDetach(w *core.World, id core.EntityID, ct core.ComponentType) {
+	if ct == core.CompMindControl {
+		s.unlink(w, id)
+	}
+}
+
+// unlink releases a controller's captives and forgets it
+func (s *MindControlSystem) unlink(w *core.World, cid core.EntityID) {
+	if mc, ok := s.links[cid]; ok {
+		releaseAll(w, mc)
+		delete(s.links, cid)
+	}
+}
+
+// Dominate hands target over to the controller's owner. It fails if the
+// controller is full or the target can't be controlled.
+func Dominate(w *core.World, controller, target core.EntityID) bool {
+	mcc := w.Get(controller, core.CompMindControl)
+	cown := w.Get(controller, core.CompOwner)
+	town := w.Get(target, core.CompOwner)
+	if mcc == nil || cown == nil || town == nil || !canControlMore(w, controller) || !controllable(w, target) {
+		return false
+	}
+	mc := mcc.(*core.MindControl)
+	to, co := town.(*core.Owner), cown.(*core.Owner)
+	mc.Captives = append(mc.Captives, core.Captive{ID: target, PlayerID: to.PlayerID, Faction: to.Faction})
+	to.PlayerID, to.Faction = co.PlayerID, co.Faction
+	haltCaptive(w, target)
+	return true
+}
+
+// canControlMore reports whether the controller has a free slot
+func canControlMore(w *core.World, controller core.EntityID) bool {
+	mc, ok := core.GetComponent[*core.MindControl](w, controller)
+	return ok && len(mc.Captives) < mc.Capacity
+}
+
+// controllable reports whether a unit can be taken over: buildings,
+// controllers and units already under control are immune
+func controllable(w *core.World, id core.EntityID) bool {
+	if w.Has(id, core.CompBuilding) || w.Has(id, core.CompMindControl) || !w.Has(id, core.CompMovable) {
+		return false
+	}
+	return ControllerOf(w, id) == 0
+}
+
+// ControllerOf returns the unit controlling id, or 0 if it is free
+func ControllerOf(w *core.World, id core.EntityID) core.EntityID {
+	for _, cid := range w.Query(core.CompMindControl) {
+		for _, c := range w.Get(cid, core.CompMindControl).(*core.MindControl).Captives {
+			if c.ID == id {
+				return cid
+			}
+		}
+	}
+	return 0
+}
+
+// releaseAll frees every captive held by mc
+func releaseAll(w *core.World, mc *core.MindControl) {
+	for _, c := range mc.Captives {
+		release(w, c)
+	}
+	mc.Captives = nil
+}
+
+// release returns a captive to its original owner
+func release(w *core.World, c core.Captive) {
+	own := w.Get(c.ID, core.CompOwner)
+	if own == nil {
+		return
+	}
+	o := own.(*core.Owner)
+	o.PlayerID, o.Faction = c.PlayerID, c.Faction
+	haltCaptive(w, c.ID)
+}
+
+// haltCaptive drops the orders a unit had under its previous owner
+func haltCaptive(w *core.World, id core.EntityID) {
+	if mov := w.Get(id, core.CompMovable); mov != nil {
+		m := mov.(*core.Movable)
+		m.Path = nil
+		m.PathIdx = 0
+	}
+	if wep := w.Get(id, core.CompWeapon); wep != nil {
+		wep.(*core.Weapon).Stance = core.StanceAggressive
+	}
+}
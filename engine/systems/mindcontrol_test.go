@@ -0,0 +1,105 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+func spawnController(w *core.World, capacity int) core.EntityID {
+	id := spawnGroundUnit(w, 5, 5, core.MoveInfantry)
+	w.Attach(id, &core.Owner{PlayerID: 0, Faction: "Soviet"})
+	w.Attach(id, &core.Health{Current: 100, Max: 100})
+	w.Attach(id, &core.MindControl{Capacity: capacity, LinkRange: 10})
+	return id
+}
+
+func spawnVictim(w *core.World, x float64) core.EntityID {
+	id := spawnGroundUnit(w, x, 5, core.MoveVehicle)
+	w.Attach(id, &core.Owner{PlayerID: 1, Faction: "Allied"})
+	w.Attach(id, &core.Health{Current: 100, Max: 100})
+	return id
+}
+
+func TestDominate(t *testing.T) {
+	tests := []struct {
+		name   string
+		target func(w *core.World) core.EntityID
+		full   bool
+		want   bool
+	}{
+		{"free unit", func(w *core.World) core.EntityID { return spawnVictim(w, 6) }, false, true},
+		{"controller at capacity", func(w *core.World) core.EntityID { return spawnVictim(w, 6) }, true, false},
+		{"building", func(w *core.World) core.EntityID { return spawnBuilding(w, "barracks", 1, 6, 5, 2) }, false, false},
+		{"another controller", func(w *core.World) core.EntityID {
+			id := spawnController(w, 1)
+			w.Get(id, core.CompOwner).(*core.Owner).PlayerID = 1
+			return id
+		}, false, false},
+		{"already controlled", func(w *core.World) core.EntityID {
+			id := spawnVictim(w, 6)
+			Dominate(w, spawnController(w, 1), id)
+			return id
+		}, false, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			yuri := spawnController(w, 1)
+			if tc.full {
+				Dominate(w, yuri, spawnVictim(w, 7))
+			}
+			target := tc.target(w)
+			before := w.Get(target, core.CompOwner).(*core.Owner).PlayerID
+
+			if got := Dominate(w, yuri, target); got != tc.want {
+				t.Fatalf("Dominate = %v, want %v", got, tc.want)
+			}
+			owner := w.Get(target, core.CompOwner).(*core.Owner).PlayerID
+			if tc.want && owner != 0 {
+				t.Errorf("owner = %d after takeover, want 0", owner)
+			}
+			if !tc.want && owner != before {
+				t.Errorf("owner changed from %d to %d on a failed takeover", before, owner)
+			}
+		})
+	}
+}
+
+func TestMindControlRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		cut  func(w *core.World, yuri, victim core.EntityID)
+	}{
+		{"controller killed", func(w *core.World, yuri, _ core.EntityID) {
+			w.Get(yuri, core.CompHealth).(*core.Health).Current = 0
+		}},
+		{"controller removed", func(w *core.World, yuri, _ core.EntityID) { w.Destroy(yuri) }},
+		{"captive out of range", func(w *core.World, _, victim core.EntityID) {
+			w.Get(victim, core.CompPosition).(*core.Position).X = 40
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			w.AddSystem(&MindControlSystem{})
+			yuri := spawnController(w, 1)
+			victim := spawnVictim(w, 6)
+			if !Dominate(w, yuri, victim) {
+				t.Fatal("Dominate failed")
+			}
+			w.Tick(0.05)
+			if ControllerOf(w, victim) != yuri {
+				t.Fatal("link broke while nothing changed")
+			}
+
+			tc.cut(w, yuri, victim)
+			w.Tick(0.05)
+			w.Tick(0.05)
+			own := w.Get(victim, core.CompOwner).(*core.Owner)
+			if own.PlayerID != 1 || own.Faction != "Allied" {
+				t.Errorf("owner = %d/%s, want 1/Allied", own.PlayerID, own.Faction)
+			}
+		})
+	}
+}
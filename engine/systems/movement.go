@@ -11,11 +11,29 @@ import (
 // MovementSystem moves units along their paths
 type MovementSystem struct {
 	NavGrid *pathfind.NavGrid
+	TileMap *maplib.TileMap // optional: enables uphill slowdown
 }
 
+// UphillPenalty is the speed lost per elevation level climbed
+const UphillPenalty = 0.25
+
+// Stuck detection: a unit that hasn't closed StuckProgress tiles on its
+// waypoint within StuckTime seconds repaths, then nudges aside, and gives up
+// after StuckRetries attempts
+const (
+	StuckTime     = 1.5
+	StuckProgress = 0.1
+	StuckRetries  = 4
+)
+
 func (s *MovementSystem) Priority() int { return 10 }
 
 func (s *MovementSystem) Update(w *core.World, dt float64) {
+	// Buildings placed or removed since the last tick block or reopen
+	// their footprints before anyone replans
+	if s.NavGrid != nil && s.TileMap != nil {
+		s.NavGrid.Sync(s.TileMap)
+	}
 	ids := w.Query(core.CompPosition, core.CompMovable)
 	// Collect positions for steering
 	positions := make(map[core.EntityID][3]float64)
@@ -28,9 +46,22 @@ func (s *MovementSystem) Update(w *core.World, dt float64) {
 		pos := w.Get(id, core.CompPosition).(*core.Position)
 		mov := w.Get(id, core.CompMovable).(*core.Movable)
 
-		if mov.PathIdx >= len(mov.Path) {
+		if mov.PathIdx >= len(mov.Path) || w.Has(id, core.CompDeploying) {
+			continue
+		}
+		// Deployed siege units are rooted until they pack up
+		if sg := w.Get(id, core.CompSiege); sg != nil && sg.(*core.Siege).Deployed {
+			mov.Path = nil
+			mov.PathIdx = 0
 			continue
 		}
+		// Attack-moving units stand and fight while they have a target
+		if mov.AttackMove {
+			if wep, ok := core.GetComponent[*core.Weapon](w, id); ok && wep.Engaged {
+				mov.Stuck = 0
+				continue
+			}
+		}
 
 		// Collect nearby units for avoidance
 		var others [][3]float64
@@ -50,8 +81,15 @@ func (s *MovementSystem) Update(w *core.World, dt float64) {
 			pts[i] = pathfind.Point{X: tp.X, Y: tp.Y}
 		}
 		steer := pathfind.Steer(pos.X, pos.Y, mov.Speed, pts, mov.PathIdx, others)
-		pos.X += steer.VX * dt
-		pos.Y += steer.VY * dt
+		slope := s.slopeFactor(pos, mov)
+		nx, ny := pos.X+steer.VX*slope*dt, pos.Y+steer.VY*slope*dt
+		// Gates stay shut to anyone they don't open for
+		if !s.gateOpen(w, id, mov, pos, nx, ny) {
+			mov.Path = nil
+			mov.PathIdx = 0
+			continue
+		}
+		pos.X, pos.Y = nx, ny
 
 		// Update facing
 		if steer.VX != 0 || steer.VY != 0 {
@@ -64,10 +102,101 @@ func (s *MovementSystem) Update(w *core.World, dt float64) {
 		dx, dy := tx-pos.X, ty-pos.Y
 		if dx*dx+dy*dy < 0.15 {
 			mov.PathIdx++
+			mov.Stuck = 0
+			continue
+		}
+		dist := math.Sqrt(dx*dx + dy*dy)
+		if mov.Stuck == 0 || dist < mov.Closest-StuckProgress {
+			mov.Closest = dist
+			mov.Stuck = 0
+		}
+		mov.Stuck += dt
+		if mov.Stuck >= StuckTime {
+			s.unstick(w, id, pos, mov)
 		}
 	}
 }
 
+// unstick tries to free a unit that stopped making progress: odd attempts
+// repath to the destination, even ones step aside to a free neighbour.
+// Past StuckRetries, or when no path exists, the order is dropped.
+func (s *MovementSystem) unstick(w *core.World, id core.EntityID, pos *core.Position, mov *core.Movable) {
+	mov.Stuck = 0
+	mov.Retries++
+	if mov.Retries > StuckRetries || s.NavGrid == nil {
+		giveUp(mov)
+		return
+	}
+	sx, sy := int(pos.X), int(pos.Y)
+	if mov.Retries%2 == 1 {
+		goal := mov.Path[len(mov.Path)-1]
+		if !routeTo(w, s.NavGrid, id, mov, sx, sy, goal.X, goal.Y) {
+			giveUp(mov)
+		}
+		return
+	}
+	flag := MovePassFlag(mov.MoveType)
+	owner := ownerOf(w, id)
+	for i := 0; i < 8; i++ {
+		d := neighbours[(int(id)+mov.Retries+i)%8]
+		nx, ny := sx+d[0], sy+d[1]
+		if s.NavGrid.PassableFor(nx, ny, flag, owner) {
+			rest := mov.Path[mov.PathIdx:]
+			mov.Path = append([]core.TilePos{{X: nx, Y: ny}}, rest...)
+			mov.PathIdx = 0
+			return
+		}
+	}
+}
+
+// neighbours lists the eight tile offsets around a tile
+var neighbours = [8][2]int{
+	{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1},
+}
+
+// giveUp drops a unit's path and flags the destination as unreachable
+func giveUp(mov *core.Movable) {
+	mov.Path = nil
+	mov.PathIdx = 0
+	mov.Retries = 0
+	mov.Unreachable = true
+}
+
+// ownerOf returns an entity's player, or the neutral player if unowned
+func ownerOf(w *core.World, id core.EntityID) int {
+	if own, ok := core.GetComponent[*core.Owner](w, id); ok {
+		return own.PlayerID
+	}
+	return core.NeutralPlayerID
+}
+
+// gateOpen reports whether a unit stepping from pos to (nx, ny) may enter
+// the tile it reaches. Aircraft fly over gates.
+func (s *MovementSystem) gateOpen(w *core.World, id core.EntityID, mov *core.Movable, pos *core.Position, nx, ny float64) bool {
+	tx, ty := int(math.Floor(nx)), int(math.Floor(ny))
+	if s.NavGrid == nil || mov.MoveType == core.MoveAir || (tx == int(math.Floor(pos.X)) && ty == int(math.Floor(pos.Y))) {
+		return true
+	}
+	own := w.Get(id, core.CompOwner)
+	if own == nil {
+		return true
+	}
+	return s.NavGrid.GateOpensFor(tx, ty, own.(*core.Owner).PlayerID)
+}
+
+// slopeFactor returns the speed multiplier for climbing toward the next waypoint
+func (s *MovementSystem) slopeFactor(pos *core.Position, mov *core.Movable) float64 {
+	if s.TileMap == nil || mov.MoveType == core.MoveAir {
+		return 1.0
+	}
+	next := mov.Path[mov.PathIdx]
+	climb := s.TileMap.HeightAt(next.X, next.Y) - s.TileMap.HeightAt(int(pos.X), int(pos.Y))
+	if climb <= 0 {
+		return 1.0
+	}
+	return 1.0 / (1.0 + UphillPenalty*float64(climb))
+}
+
 // MovePassFlag converts core.MoveType to maplib.PassFlag
 func MovePassFlag(mt core.MoveType) maplib.PassFlag {
 	switch mt {
@@ -93,17 +222,141 @@ func OrderMove(w *core.World, ng *pathfind.NavGrid, id core.EntityID, gx, gy int
 	if pos == nil || mov == nil {
 		return
 	}
-	p := pos.(*core.Position)
 	m := mov.(*core.Movable)
-	sx, sy := int(p.X), int(p.Y)
+	// Any new order ends a Stop
+	if wep := w.Get(id, core.CompWeapon); wep != nil {
+		wep.(*core.Weapon).Stance = core.StanceAggressive
+	}
+	if h := w.Get(id, core.CompHarvester); h != nil && h.(*core.Harvester).State == core.HarvStopped {
+		h.(*core.Harvester).State = core.HarvMovingToOre
+	}
+	w.Detach(id, core.CompBridgeRepair)
+	p := pos.(*core.Position)
+	m.Retries = 0
+	m.AttackMove = false
+	m.Unreachable = !routeTo(w, ng, id, m, int(p.X), int(p.Y), gx, gy)
+}
+
+// OrderAttackMove sends a unit to a destination, stopping to fight any
+// enemy that comes into range on the way
+func OrderAttackMove(w *core.World, ng *pathfind.NavGrid, id core.EntityID, gx, gy int) {
+	OrderMove(w, ng, id, gx, gy)
+	if m, ok := core.GetComponent[*core.Movable](w, id); ok {
+		m.AttackMove = true
+	}
+}
+
+// routeTo replaces a unit's path with one from (sx, sy) to (gx, gy),
+// leaving it untouched and returning false if there is none
+func routeTo(w *core.World, ng *pathfind.NavGrid, id core.EntityID, m *core.Movable, sx, sy, gx, gy int) bool {
 	flag := MovePassFlag(m.MoveType)
-	path := pathfind.FindPath(ng, sx, sy, gx, gy, flag)
-	if path != nil {
-		path = pathfind.SmoothPath(ng, path, flag)
-		m.Path = make([]core.TilePos, len(path))
-		for i, pt := range path {
-			m.Path[i] = core.TilePos{X: pt.X, Y: pt.Y}
-		}
-		m.PathIdx = 0
+	owner := ownerOf(w, id)
+	path := pathfind.FindPathFor(ng, sx, sy, gx, gy, flag, owner)
+	if path == nil {
+		return false
+	}
+	path = pathfind.SmoothPathFor(ng, path, flag, owner)
+	m.Path = make([]core.TilePos, len(path))
+	for i, pt := range path {
+		m.Path[i] = core.TilePos{X: pt.X, Y: pt.Y}
+	}
+	m.PathIdx = 0
+	m.Stuck = 0
+	return true
+}
+
+// unitFits reports whether a unit could stand on tile (x, y): passable for
+// it, not under a building and not holding another unit
+func unitFits(w *core.World, tm *maplib.TileMap, id core.EntityID, x, y int) bool {
+	flag := maplib.PassAll
+	if mov := w.Get(id, core.CompMovable); mov != nil {
+		flag = MovePassFlag(mov.(*core.Movable).MoveType)
+	}
+	if tm == nil || !tm.IsPassable(x, y, flag) {
+		return false
+	}
+	for _, uid := range UnitsInFootprint(w, x, y, 1, 1) {
+		if uid != id {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestFree returns the tile closest to (x, y), within radius rings, that
+// unit id fits on
+func nearestFree(w *core.World, tm *maplib.TileMap, id core.EntityID, x, y, radius int) (int, int, bool) {
+	for r := 0; r <= radius; r++ {
+		for dy := -r; dy <= r; dy++ {
+			for dx := -r; dx <= r; dx++ {
+				if max(abs(dx), abs(dy)) != r {
+					continue
+				}
+				if unitFits(w, tm, id, x+dx, y+dy) {
+					return x + dx, y + dy, true
+				}
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// ScatterSearch is how many tiles out ScatterUnits looks for room
+const ScatterSearch = 3
+
+// ScatterUnits moves each unit to the nearest free tile around it, so a
+// clump spreads out. Tiles holding a unit, or already picked for another,
+// don't count as free.
+func ScatterUnits(w *core.World, ng *pathfind.NavGrid, ids []core.EntityID) {
+	taken := make(map[core.TilePos]bool)
+	for _, id := range w.Query(core.CompPosition, core.CompMovable) {
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		taken[core.TilePos{X: int(pos.X), Y: int(pos.Y)}] = true
+	}
+	for _, id := range ids {
+		pos, ok := core.GetComponent[*core.Position](w, id)
+		mov, ok2 := core.GetComponent[*core.Movable](w, id)
+		if !ok || !ok2 {
+			continue
+		}
+		flag := MovePassFlag(mov.MoveType)
+		owner := ownerOf(w, id)
+		sx, sy := int(pos.X), int(pos.Y)
+	search:
+		for r := 1; r <= ScatterSearch; r++ {
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					if max(abs(dx), abs(dy)) != r {
+						continue
+					}
+					t := core.TilePos{X: sx + dx, Y: sy + dy}
+					if taken[t] || !ng.PassableFor(t.X, t.Y, flag, owner) {
+						continue
+					}
+					taken[t] = true
+					OrderMove(w, ng, id, t.X, t.Y)
+					break search
+				}
+			}
+		}
+	}
+}
+
+// StopUnit clears a unit's path and puts it on hold: it won't pick targets
+// until given another order. Harvesters abort their current trip. Only
+// mobile units take the order; turrets and other buildings are left alone.
+func StopUnit(w *core.World, id core.EntityID) {
+	m, ok := core.GetComponent[*core.Movable](w, id)
+	if !ok {
+		return
+	}
+	m.Path = nil
+	m.PathIdx = 0
+	m.AttackMove = false
+	if wep := w.Get(id, core.CompWeapon); wep != nil {
+		wep.(*core.Weapon).Stance = core.StanceHold
+	}
+	if h := w.Get(id, core.CompHarvester); h != nil {
+		h.(*core.Harvester).State = core.HarvStopped
 	}
 }
@@ -0,0 +1,146 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+func TestStopUnit(t *testing.T) {
+	w := core.NewWorld(20)
+	ng := pathfind.NewNavGrid(maplib.NewTileMap("t", 32, 32))
+
+	tank := spawnGroundUnit(w, 2.5, 2.5, core.MoveVehicle)
+	w.Attach(tank, &core.Weapon{Damage: 10, Range: 5})
+	OrderAttackMove(w, ng, tank, 20, 20)
+
+	harv := spawnGroundUnit(w, 4.5, 2.5, core.MoveVehicle)
+	w.Attach(harv, &core.Harvester{State: core.HarvMovingToOre})
+
+	turret := w.Spawn()
+	w.Attach(turret, &core.Position{X: 10, Y: 10})
+	w.Attach(turret, &core.Building{SizeX: 1, SizeY: 1})
+	w.Attach(turret, &core.Weapon{Damage: 10, Range: 6})
+
+	for _, id := range []core.EntityID{tank, harv, turret} {
+		StopUnit(w, id)
+	}
+
+	m, _ := core.GetComponent[*core.Movable](w, tank)
+	if m.Path != nil || m.PathIdx != 0 || m.AttackMove {
+		t.Errorf("tank keeps orders: path %v, idx %d, attack-move %v", m.Path, m.PathIdx, m.AttackMove)
+	}
+	if wep, _ := core.GetComponent[*core.Weapon](w, tank); wep.Stance != core.StanceHold {
+		t.Errorf("tank stance = %v, want StanceHold", wep.Stance)
+	}
+	if h, _ := core.GetComponent[*core.Harvester](w, harv); h.State != core.HarvStopped {
+		t.Errorf("harvester state = %v, want HarvStopped", h.State)
+	}
+	if wep, _ := core.GetComponent[*core.Weapon](w, turret); wep.Stance != core.StanceAggressive {
+		t.Error("a turret was put on hold by Stop")
+	}
+
+	// A new order ends the hold
+	OrderMove(w, ng, tank, 10, 2)
+	if wep, _ := core.GetComponent[*core.Weapon](w, tank); wep.Stance != core.StanceAggressive {
+		t.Errorf("tank stance after a new order = %v, want StanceAggressive", wep.Stance)
+	}
+}
+
+func TestScatterUnits(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		wall    bool // surround the clump's tile with water
+		wantOut int  // units given a scatter destination
+	}{
+		{"clump on open ground", 4, false, 4},
+		{"single unit", 1, false, 1},
+		{"walled in", 3, true, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 32, 32)
+			if tc.wall {
+				tm.SetTerrain(7, 7, 13, 13, maplib.TerrainWater)
+				tm.SetTerrain(10, 10, 10, 10, maplib.TerrainGrass)
+			}
+			ng := pathfind.NewNavGrid(tm)
+			var ids []core.EntityID
+			for i := 0; i < tc.n; i++ {
+				ids = append(ids, spawnGroundUnit(w, 10.5, 10.5, core.MoveVehicle))
+			}
+
+			ScatterUnits(w, ng, ids)
+			dest := map[core.TilePos]bool{}
+			out := 0
+			for _, id := range ids {
+				m, _ := core.GetComponent[*core.Movable](w, id)
+				if len(m.Path) == 0 {
+					continue
+				}
+				out++
+				d := m.Path[len(m.Path)-1]
+				if d == (core.TilePos{X: 10, Y: 10}) {
+					t.Errorf("unit %d told to stay on the crowded tile", id)
+				}
+				if dest[d] {
+					t.Errorf("two units sent to %v", d)
+				}
+				dest[d] = true
+			}
+			if out != tc.wantOut {
+				t.Errorf("%d units scattered, want %d", out, tc.wantOut)
+			}
+		})
+	}
+}
+
+func TestStuckUnitRecovery(t *testing.T) {
+	tests := []struct {
+		name            string
+		speed           float64 // 0 stands in for a unit that can't get past what's around it
+		boxIn           bool    // buildings close in around the unit after the order
+		wantUnreachable bool
+		wantArrived     bool
+	}{
+		{"free to move", 2, false, false, true},
+		{"wedged in place", 0, false, true, false},
+		{"boxed in by buildings", 0, true, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			ng := pathfind.NewNavGrid(maplib.NewTileMap("t", 32, 32))
+			w.AddSystem(&MovementSystem{NavGrid: ng})
+			id := spawnGroundUnit(w, 5.5, 5.5, core.MoveVehicle)
+			w.Get(id, core.CompMovable).(*core.Movable).Speed = tc.speed
+			OrderMove(w, ng, id, 12, 5)
+			if tc.boxIn {
+				for _, d := range neighbours {
+					ng.SetBlocked(5+d[0], 5+d[1])
+				}
+			}
+
+			// Long enough to use up every retry
+			ticks := int(StuckTime*(StuckRetries+2)/0.05) + 1
+			for i := 0; i < ticks; i++ {
+				w.Tick(0.05)
+			}
+			m, _ := core.GetComponent[*core.Movable](w, id)
+			if m.Unreachable != tc.wantUnreachable {
+				t.Errorf("unreachable = %v, want %v", m.Unreachable, tc.wantUnreachable)
+			}
+			if tc.wantUnreachable && m.Path != nil {
+				t.Error("a unit that gave up still has a path")
+			}
+			pos, _ := core.GetComponent[*core.Position](w, id)
+			if arrived := int(pos.X) == 12 && int(pos.Y) == 5; arrived != tc.wantArrived {
+				t.Errorf("arrived = %v at (%.1f, %.1f), want %v", arrived, pos.X, pos.Y, tc.wantArrived)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+func spawnPowered(w *core.World, owner, draw int, down bool) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Owner{PlayerID: owner})
+	w.Attach(id, &core.Building{SizeX: 2, SizeY: 2, PowerDraw: draw, PoweredDown: down})
+	return id
+}
+
+func TestTogglePowerOnlyOwnBuildings(t *testing.T) {
+	tests := []struct {
+		name      string
+		ownDown   bool
+		enemyDown bool
+		wantOwn   bool // own building PoweredDown afterwards
+		wantUse   int  // player 0 PowerUse afterwards
+	}{
+		{"both running", false, false, true, 0},
+		{"enemy running doesn't keep ours down", true, false, false, 20},
+		{"enemy down is left down", false, true, true, 0},
+		{"both down", true, true, false, 20},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			pm := core.NewPlayerManager()
+			use := 20
+			if tc.ownDown {
+				use = 0
+			}
+			pm.AddPlayer(&core.Player{ID: 0, PowerUse: use})
+			pm.AddPlayer(&core.Player{ID: 1, PowerUse: 30})
+			own := spawnPowered(w, 0, 20, tc.ownDown)
+			enemy := spawnPowered(w, 1, 30, tc.enemyDown)
+
+			if !TogglePower(w, pm, []core.EntityID{enemy, own}, 0) {
+				t.Fatal("TogglePower reported no change")
+			}
+			b, _ := core.GetComponent[*core.Building](w, own)
+			if b.PoweredDown != tc.wantOwn {
+				t.Errorf("own PoweredDown = %v, want %v", b.PoweredDown, tc.wantOwn)
+			}
+			eb, _ := core.GetComponent[*core.Building](w, enemy)
+			if eb.PoweredDown != tc.enemyDown {
+				t.Errorf("enemy PoweredDown changed to %v", eb.PoweredDown)
+			}
+			if got := pm.GetPlayer(0).PowerUse; got != tc.wantUse {
+				t.Errorf("player 0 PowerUse = %d, want %d", got, tc.wantUse)
+			}
+			if got := pm.GetPlayer(1).PowerUse; got != 30 {
+				t.Errorf("player 1 PowerUse = %d, want 30", got)
+			}
+		})
+	}
+}
+
+func TestTogglePowerNothingOwned(t *testing.T) {
+	w := core.NewWorld(20)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 1, PowerUse: 30})
+	enemy := spawnPowered(w, 1, 30, false)
+	if TogglePower(w, pm, []core.EntityID{enemy}, 0) {
+		t.Error("toggled a building the player doesn't own")
+	}
+}
+
+func TestRadarNeedsPower(t *testing.T) {
+	tests := []struct {
+		name      string
+		plant     int  // power generated
+		down      bool // radar switched off
+		building  bool // radar still under construction
+		noRadar   bool
+		wantRadar bool
+	}{
+		{"powered", 100, false, false, false, true},
+		{"low power", 20, false, false, false, false},
+		{"switched off", 100, true, false, false, false},
+		{"under construction", 100, false, true, false, false},
+		{"no radar", 100, false, false, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0})
+			pm.AddPlayer(&core.Player{ID: 1})
+
+			plant := spawnPowered(w, 0, 0, false)
+			w.Get(plant, core.CompBuilding).(*core.Building).PowerGen = tc.plant
+			if !tc.noRadar {
+				radar := spawnPowered(w, 0, 40, tc.down)
+				w.Attach(radar, &core.BuildingName{Key: RadarKey})
+				if tc.building {
+					w.Attach(radar, &core.BuildingConstruction{Progress: 0.5})
+				}
+			}
+			enemy := spawnPowered(w, 1, 40, false)
+			w.Get(enemy, core.CompBuilding).(*core.Building).PowerGen = 100
+			w.Attach(enemy, &core.BuildingName{Key: RadarKey})
+			w.Attach(enemy, &core.BuildingConstruction{Progress: 1, Complete: true})
+
+			(&PowerSystem{Players: pm}).Update(w, 0.05)
+			if got := pm.GetPlayer(0).Radar; got != tc.wantRadar {
+				t.Errorf("player 0 Radar = %v, want %v", got, tc.wantRadar)
+			}
+			// the enemy's finished, powered radar is unaffected
+			if !pm.GetPlayer(1).Radar {
+				t.Error("player 1 Radar = false, want true")
+			}
+		})
+	}
+}
+
+func TestRadarGoesDarkWhenPowerDrops(t *testing.T) {
+	w := core.NewWorld(20)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0})
+	plant := spawnPowered(w, 0, 0, false)
+	w.Get(plant, core.CompBuilding).(*core.Building).PowerGen = 100
+	radar := spawnPowered(w, 0, 40, false)
+	w.Attach(radar, &core.BuildingName{Key: RadarKey})
+	s := &PowerSystem{Players: pm}
+
+	s.Update(w, 0.05)
+	if !pm.GetPlayer(0).Radar {
+		t.Fatal("radar offline with enough power")
+	}
+	w.Despawn(plant)
+	s.Update(w, 0.05)
+	if pm.GetPlayer(0).Radar {
+		t.Error("radar still online after losing the power plant")
+	}
+}
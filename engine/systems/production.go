@@ -1,47 +1,67 @@
 package systems
 
 import (
+	"math"
+
 	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
 )
 
 // UnitDef defines a unit type that can be produced
 type UnitDef struct {
-	Name      string
-	Cost      int
-	BuildTime float64 // seconds
-	HP        int
-	Speed     float64
-	Damage    int
-	Range     float64
-	ArmorType core.ArmorType
-	DmgType   core.DamageType
-	MoveType  core.MoveType
-	Vision    int
-	Prereqs   []string
-	Faction   string
+	Name        string
+	Cost        int
+	BuildTime   float64 // seconds
+	HP          int
+	Speed       float64
+	Damage      int
+	Range       float64
+	ArmorType   core.ArmorType
+	DmgType     core.DamageType
+	MoveType    core.MoveType
+	Vision      int
+	Prereqs     []string
+	Faction     string
+	Regen       float64             // HP per second of self-healing
+	RegenNear   string              // if set, only heals near this building
+	RegenRadius float64             // how near RegenNear it must be
+	MindControl int                 // number of units it can control at once
+	Chrono      bool                // can teleport
+	Siege       *core.WeaponProfile // weapon once deployed; the unit must deploy to use it
 }
 
 // BuildingDef defines a building type
 type BuildingDef struct {
-	Name      string
-	Cost      int
-	BuildTime float64
-	HP        int
-	SizeX     int
-	SizeY     int
-	PowerGen  int
-	PowerDraw int
-	TechLevel int
-	Prereqs   []string
-	CanProduce []string
-	Faction   string
-	IsDefense bool
+	Name        string
+	Cost        int
+	BuildTime   float64
+	HP          int
+	SizeX       int
+	SizeY       int
+	PowerGen    int
+	PowerDraw   int
+	TechLevel   int
+	Prereqs     []string
+	CanProduce  []string
+	Faction     string
+	IsDefense   bool
+	Pad         bool    // footprint is a drive-on pad rather than blocked tiles
+	Storage     int     // ore capacity, in credits
+	BuildRadius float64 // tiles around it its owner may build; 0 = DefaultBuildRadius, <0 = none
+}
+
+// FactionDef holds traits shared by a whole faction
+type FactionDef struct {
+	BuildAnywhere   bool    // may place buildings anywhere on the map
+	BuildRadiusMult float64 // scales every building's build radius
 }
 
 // TechTree holds all definitions
 type TechTree struct {
 	Units     map[string]*UnitDef
 	Buildings map[string]*BuildingDef
+	Factions  map[string]*FactionDef
 }
 
 // NewTechTree creates a default RA2-style tech tree
@@ -49,34 +69,45 @@ func NewTechTree() *TechTree {
 	tt := &TechTree{
 		Units:     make(map[string]*UnitDef),
 		Buildings: make(map[string]*BuildingDef),
+		Factions:  make(map[string]*FactionDef),
 	}
 
 	// Allied units
 	tt.Units["gi"] = &UnitDef{Name: "GI", Cost: 200, BuildTime: 3, HP: 125, Speed: 3.0, Damage: 15, Range: 5, ArmorType: core.ArmorLight, DmgType: core.DmgKinetic, MoveType: core.MoveInfantry, Vision: 5, Faction: "Allied"}
 	tt.Units["engineer"] = &UnitDef{Name: "Engineer", Cost: 500, BuildTime: 5, HP: 75, Speed: 2.5, Damage: 0, Range: 0, ArmorType: core.ArmorNone, MoveType: core.MoveInfantry, Vision: 4, Faction: ""}
-	tt.Units["attack_dog"] = &UnitDef{Name: "Attack Dog", Cost: 200, BuildTime: 2, HP: 100, Speed: 5.0, Damage: 100, Range: 1, ArmorType: core.ArmorNone, DmgType: core.DmgKinetic, MoveType: core.MoveInfantry, Vision: 7, Faction: ""}
+	tt.Units["attack_dog"] = &UnitDef{Name: "Attack Dog", Cost: 200, BuildTime: 2, HP: 100, Speed: 5.0, Damage: 100, Range: 1, ArmorType: core.ArmorNone, DmgType: core.DmgKinetic, MoveType: core.MoveInfantry, Vision: 7, Faction: "", Regen: 2}
 	tt.Units["grizzly"] = &UnitDef{Name: "Grizzly Tank", Cost: 700, BuildTime: 8, HP: 400, Speed: 2.5, Damage: 75, Range: 5.5, ArmorType: core.ArmorHeavy, DmgType: core.DmgExplosive, MoveType: core.MoveVehicle, Vision: 6, Faction: "Allied", Prereqs: []string{"war_factory"}}
 	tt.Units["ifv"] = &UnitDef{Name: "IFV", Cost: 600, BuildTime: 6, HP: 200, Speed: 3.5, Damage: 40, Range: 6, ArmorType: core.ArmorLight, DmgType: core.DmgKinetic, MoveType: core.MoveVehicle, Vision: 7, Faction: "Allied", Prereqs: []string{"war_factory"}}
-	tt.Units["harvester_a"] = &UnitDef{Name: "Chrono Miner", Cost: 1400, BuildTime: 12, HP: 600, Speed: 1.5, MoveType: core.MoveVehicle, Vision: 4, Faction: "Allied"}
+	tt.Units["chrono_legion"] = &UnitDef{Name: "Chrono Legionnaire", Cost: 1500, BuildTime: 12, HP: 125, Speed: 2.5, Damage: 40, Range: 5, ArmorType: core.ArmorLight, DmgType: core.DmgElectric, MoveType: core.MoveInfantry, Vision: 6, Faction: "Allied", Prereqs: []string{"radar"}, Chrono: true}
+	tt.Units["harvester_a"] = &UnitDef{Name: "Chrono Miner", Cost: 1400, BuildTime: 12, HP: 600, Speed: 1.5, MoveType: core.MoveVehicle, Vision: 4, Faction: "Allied", Regen: HarvesterRegen, RegenNear: "refinery", RegenRadius: HarvesterRegenRadius}
 
 	// Soviet units
 	tt.Units["conscript"] = &UnitDef{Name: "Conscript", Cost: 100, BuildTime: 2, HP: 100, Speed: 3.0, Damage: 12, Range: 4.5, ArmorType: core.ArmorNone, DmgType: core.DmgKinetic, MoveType: core.MoveInfantry, Vision: 5, Faction: "Soviet"}
 	tt.Units["rhino"] = &UnitDef{Name: "Rhino Tank", Cost: 900, BuildTime: 10, HP: 500, Speed: 2.0, Damage: 90, Range: 5.5, ArmorType: core.ArmorHeavy, DmgType: core.DmgExplosive, MoveType: core.MoveVehicle, Vision: 6, Faction: "Soviet", Prereqs: []string{"war_factory"}}
-	tt.Units["harvester_s"] = &UnitDef{Name: "War Miner", Cost: 1400, BuildTime: 12, HP: 800, Speed: 1.2, Damage: 20, Range: 3, ArmorType: core.ArmorHeavy, DmgType: core.DmgKinetic, MoveType: core.MoveVehicle, Vision: 4, Faction: "Soviet"}
+	tt.Units["harvester_s"] = &UnitDef{Name: "War Miner", Cost: 1400, BuildTime: 12, HP: 800, Speed: 1.2, Damage: 20, Range: 3, ArmorType: core.ArmorHeavy, DmgType: core.DmgKinetic, MoveType: core.MoveVehicle, Vision: 4, Faction: "Soviet", Regen: HarvesterRegen, RegenNear: "refinery", RegenRadius: HarvesterRegenRadius}
+	tt.Units["yuri"] = &UnitDef{Name: "Yuri", Cost: 1200, BuildTime: 10, HP: 100, Speed: 2.5, Range: 5, ArmorType: core.ArmorNone, DmgType: core.DmgPsionic, MoveType: core.MoveInfantry, Vision: 7, Faction: "Soviet", Prereqs: []string{"radar"}, MindControl: 1}
+	tt.Units["v3"] = &UnitDef{Name: "V3 Launcher", Cost: 800, BuildTime: 10, HP: 150, Speed: 2.0, ArmorType: core.ArmorLight, MoveType: core.MoveVehicle, Vision: 7, Faction: "Soviet", Prereqs: []string{"radar"}, Siege: &core.WeaponProfile{Damage: 150, Range: 12, Cooldown: 5, Projectile: "rocket", Splash: 1.5, DamageType: core.DmgExplosive}}
 	tt.Units["mcv"] = &UnitDef{Name: "MCV", Cost: 3000, BuildTime: 20, HP: 1000, Speed: 0.8, ArmorType: core.ArmorHeavy, MoveType: core.MoveVehicle, Vision: 6, Prereqs: []string{"war_factory"}, Faction: ""}
 
 	// Buildings (shared names, faction handled by Faction field)
-	tt.Buildings["construction_yard"] = &BuildingDef{Name: "Construction Yard", Cost: 0, BuildTime: 0, HP: 1000, SizeX: 3, SizeY: 3, PowerGen: 0, PowerDraw: 0, TechLevel: 0, Faction: ""}
+	tt.Buildings["construction_yard"] = &BuildingDef{Name: "Construction Yard", Cost: 0, BuildTime: 0, HP: 1000, SizeX: 3, SizeY: 3, PowerGen: 0, PowerDraw: 0, TechLevel: 0, Faction: "", BuildRadius: 15}
 	tt.Buildings["power_plant"] = &BuildingDef{Name: "Power Plant", Cost: 800, BuildTime: 15, HP: 750, SizeX: 2, SizeY: 2, PowerGen: 100, PowerDraw: 0, TechLevel: 0, Prereqs: []string{"construction_yard"}, Faction: ""}
-	tt.Buildings["barracks"] = &BuildingDef{Name: "Barracks", Cost: 500, BuildTime: 20, HP: 500, SizeX: 2, SizeY: 2, PowerDraw: 20, TechLevel: 0, CanProduce: []string{"gi", "conscript", "engineer", "attack_dog"}, Prereqs: []string{"power_plant"}, Faction: ""}
-	tt.Buildings["refinery"] = &BuildingDef{Name: "Ore Refinery", Cost: 2000, BuildTime: 25, HP: 900, SizeX: 3, SizeY: 3, PowerDraw: 30, TechLevel: 0, Prereqs: []string{"power_plant"}, Faction: ""}
-	tt.Buildings["war_factory"] = &BuildingDef{Name: "War Factory", Cost: 2000, BuildTime: 30, HP: 1000, SizeX: 3, SizeY: 3, PowerDraw: 50, TechLevel: 1, CanProduce: []string{"grizzly", "rhino", "ifv", "harvester_a", "harvester_s", "mcv"}, Prereqs: []string{"refinery"}, Faction: ""}
+	tt.Buildings["barracks"] = &BuildingDef{Name: "Barracks", Cost: 500, BuildTime: 20, HP: 500, SizeX: 2, SizeY: 2, PowerDraw: 20, TechLevel: 0, CanProduce: []string{"gi", "conscript", "engineer", "attack_dog", "yuri", "chrono_legion"}, Prereqs: []string{"power_plant"}, Faction: ""}
+	tt.Buildings["refinery"] = &BuildingDef{Name: "Ore Refinery", Cost: 2000, BuildTime: 25, HP: 900, SizeX: 3, SizeY: 3, PowerDraw: 30, TechLevel: 0, Prereqs: []string{"power_plant"}, Faction: "", Storage: RefineryStorage}
+	tt.Buildings["war_factory"] = &BuildingDef{Name: "War Factory", Cost: 2000, BuildTime: 30, HP: 1000, SizeX: 3, SizeY: 3, PowerDraw: 50, TechLevel: 1, CanProduce: []string{"grizzly", "rhino", "ifv", "harvester_a", "harvester_s", "v3", "mcv"}, Prereqs: []string{"refinery"}, Faction: ""}
 	tt.Buildings["radar"] = &BuildingDef{Name: "Radar", Cost: 1000, BuildTime: 20, HP: 500, SizeX: 2, SizeY: 2, PowerDraw: 40, TechLevel: 2, Prereqs: []string{"war_factory"}, Faction: ""}
+	tt.Buildings["silo"] = &BuildingDef{Name: "Ore Silo", Cost: 150, BuildTime: 8, HP: 300, SizeX: 1, SizeY: 1, PowerDraw: 10, TechLevel: 0, Prereqs: []string{"refinery"}, Faction: "", Storage: SiloStorage, BuildRadius: 6}
+	tt.Buildings["service_depot"] = &BuildingDef{Name: "Service Depot", Cost: 800, BuildTime: 15, HP: 800, SizeX: 3, SizeY: 3, PowerDraw: 20, TechLevel: 1, Prereqs: []string{"war_factory"}, Faction: "", Pad: true}
 
 	// Defense buildings
 	tt.Buildings["pillbox"] = &BuildingDef{Name: "Pillbox", Cost: 500, BuildTime: 10, HP: 400, SizeX: 1, SizeY: 1, PowerDraw: 0, TechLevel: 0, Prereqs: []string{"barracks"}, Faction: "", IsDefense: true}
 	tt.Buildings["prism_tower"] = &BuildingDef{Name: "Prism Tower", Cost: 1500, BuildTime: 20, HP: 600, SizeX: 1, SizeY: 1, PowerDraw: 75, TechLevel: 2, Prereqs: []string{"radar"}, Faction: "Allied", IsDefense: true}
-	tt.Buildings["wall"] = &BuildingDef{Name: "Wall", Cost: 100, BuildTime: 3, HP: 200, SizeX: 1, SizeY: 1, PowerDraw: 0, TechLevel: 0, Prereqs: []string{"barracks"}, Faction: "", IsDefense: true}
+	tt.Buildings["gate"] = &BuildingDef{Name: "Gate", Cost: 250, BuildTime: 5, HP: 400, SizeX: 1, SizeY: 1, PowerDraw: 0, TechLevel: 0, Prereqs: []string{"barracks"}, Faction: "", IsDefense: true, BuildRadius: -1, Pad: true}
+	tt.Buildings["wall"] = &BuildingDef{Name: "Wall", Cost: 100, BuildTime: 3, HP: 200, SizeX: 1, SizeY: 1, PowerDraw: 0, TechLevel: 0, Prereqs: []string{"barracks"}, Faction: "", IsDefense: true, BuildRadius: -1}
+
+	// Faction traits: Soviet bases sprawl a little further
+	tt.Factions["Allied"] = &FactionDef{BuildRadiusMult: 1.0}
+	tt.Factions["Soviet"] = &FactionDef{BuildRadiusMult: 1.2}
 
 	return tt
 }
@@ -131,8 +162,10 @@ func PlayerOwnsBuildingKey(w *core.World, playerID int, key string) bool {
 	return false
 }
 
-// FindProductionBuilding finds a building that can produce the given unit for a player
+// FindProductionBuilding finds a building that can produce the given unit for a player,
+// preferring the primary one while its queue has room
 func FindProductionBuilding(w *core.World, tt *TechTree, playerID int, unitKey string) core.EntityID {
+	var best core.EntityID
 	for _, bid := range w.Query(core.CompProduction, core.CompOwner, core.CompBuildingName) {
 		own := w.Get(bid, core.CompOwner).(*core.Owner)
 		if own.PlayerID != playerID {
@@ -161,11 +194,36 @@ func FindProductionBuilding(w *core.World, tt *TechTree, playerID int, unitKey s
 			}
 		}
 		prod := w.Get(bid, core.CompProduction).(*core.Production)
-		if len(prod.Queue) < 5 {
+		if len(prod.Queue) >= 5 {
+			continue
+		}
+		if prod.Primary {
 			return bid
 		}
+		// Without a primary, the oldest building takes the order
+		if best == 0 || bid < best {
+			best = bid
+		}
+	}
+	return best
+}
+
+// SetPrimary makes a production building the one its kind produces from,
+// taking the mark from the owner's other buildings of the same type
+func SetPrimary(w *core.World, id core.EntityID) bool {
+	prod, ok := core.GetComponent[*core.Production](w, id)
+	bn, ok2 := core.GetComponent[*core.BuildingName](w, id)
+	own, ok3 := core.GetComponent[*core.Owner](w, id)
+	if !ok || !ok2 || !ok3 {
+		return false
+	}
+	for _, oid := range w.Query(core.CompProduction, core.CompBuildingName, core.CompOwner) {
+		if w.Get(oid, core.CompOwner).(*core.Owner).PlayerID == own.PlayerID && w.Get(oid, core.CompBuildingName).(*core.BuildingName).Key == bn.Key {
+			w.Get(oid, core.CompProduction).(*core.Production).Primary = false
+		}
 	}
-	return 0
+	prod.Primary = true
+	return true
 }
 
 // ProductionSystem handles building production queues
@@ -173,6 +231,7 @@ type ProductionSystem struct {
 	TechTree *TechTree
 	Players  *core.PlayerManager
 	EventBus *core.EventBus
+	NavGrid  *pathfind.NavGrid // optional: enables war mode orders
 }
 
 func (s *ProductionSystem) Priority() int { return 35 }
@@ -195,6 +254,14 @@ func (s *ProductionSystem) Update(w *core.World, dt float64) {
 			continue
 		}
 
+		// Powered-down factories hold their queue, as do yards packing up
+		if b := w.Get(id, core.CompBuilding); b != nil && b.(*core.Building).PoweredDown {
+			continue
+		}
+		if w.Has(id, core.CompDeploying) {
+			continue
+		}
+
 		// Check power ratio for speed
 		player := s.Players.GetPlayer(own.PlayerID)
 		rate := prod.Rate
@@ -211,26 +278,14 @@ func (s *ProductionSystem) Update(w *core.World, dt float64) {
 				spawnX = pos.X + 2
 				spawnY = pos.Y + 2
 			}
-			uid := w.Spawn()
-			w.Attach(uid, &core.Position{X: spawnX, Y: spawnY})
-			w.Attach(uid, &core.Sprite{Width: 24, Height: 24, Visible: true, ScaleX: 1, ScaleY: 1})
-			w.Attach(uid, &core.Health{Current: udef.HP, Max: udef.HP})
-			w.Attach(uid, &core.Movable{Speed: udef.Speed, MoveType: udef.MoveType})
-			w.Attach(uid, &core.Selectable{Radius: 0.5})
-			w.Attach(uid, &core.Owner{PlayerID: own.PlayerID, Faction: own.Faction})
-			w.Attach(uid, &core.FogVision{Range: udef.Vision})
-			if udef.Damage > 0 {
-				w.Attach(uid, &core.Weapon{Name: udef.Name, Damage: udef.Damage, Range: udef.Range, Cooldown: 1.5, DamageType: udef.DmgType, TargetType: core.TargetAll})
-			}
-			w.Attach(uid, &core.Armor{ArmorType: udef.ArmorType})
-
-			// MCV special component
-			if unitName == "mcv" {
-				w.Attach(uid, &core.MCV{CanDeploy: true})
+			uid := SpawnUnit(w, unitName, udef, own.PlayerID, own.Faction, spawnX, spawnY)
+			// War mode sends fighters straight to the staging point
+			if player != nil && player.WarMode && s.NavGrid != nil && w.Has(uid, core.CompWeapon) {
+				OrderAttackMove(w, s.NavGrid, uid, player.AttackPoint.X, player.AttackPoint.Y)
 			}
 
 			if s.EventBus != nil {
-				s.EventBus.Emit(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
+				s.EventBus.Publish(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount, Payload: core.UnitEvent{ID: uid, PlayerID: own.PlayerID, Key: unitName}})
 			}
 
 			prod.Progress = 0
@@ -239,9 +294,80 @@ func (s *ProductionSystem) Update(w *core.World, dt float64) {
 	}
 }
 
-// PowerSystem recalculates power for all players each tick
+// SpawnUnit creates a unit entity from its definition at a world position
+func SpawnUnit(w *core.World, key string, udef *UnitDef, playerID int, faction string, x, y float64) core.EntityID {
+	uid := w.Spawn()
+	w.Attach(uid, &core.Position{X: x, Y: y})
+	w.Attach(uid, &core.Sprite{Width: 24, Height: 24, Visible: true, ScaleX: 1, ScaleY: 1})
+	w.Attach(uid, &core.Health{Current: udef.HP, Max: udef.HP})
+	w.Attach(uid, &core.Movable{Speed: udef.Speed, MoveType: udef.MoveType})
+	w.Attach(uid, &core.Selectable{Radius: 0.5})
+	w.Attach(uid, &core.Owner{PlayerID: playerID, Faction: faction})
+	w.Attach(uid, &core.FogVision{Range: udef.Vision})
+	if udef.Damage > 0 {
+		w.Attach(uid, &core.Weapon{Name: udef.Name, Damage: udef.Damage, Range: udef.Range, Cooldown: 1.5, DamageType: udef.DmgType, TargetType: core.TargetAll})
+	}
+	w.Attach(uid, &core.Armor{ArmorType: udef.ArmorType})
+	w.Attach(uid, &core.UnitType{Key: key})
+	if udef.Regen > 0 {
+		w.Attach(uid, &core.Regen{Rate: udef.Regen, NearBuilding: udef.RegenNear, Radius: udef.RegenRadius})
+	}
+	if udef.MindControl > 0 {
+		w.Attach(uid, &core.Weapon{Name: udef.Name, Range: udef.Range, Cooldown: 2, DamageType: core.DmgPsionic, TargetType: core.TargetGround | core.TargetNaval})
+		w.Attach(uid, &core.MindControl{Capacity: udef.MindControl, LinkRange: udef.Range * MindLinkRangeMult})
+	}
+	if udef.Chrono {
+		w.Attach(uid, &core.Chrono{WarmUp: ChronoWarmUp, Cooldown: ChronoCooldown})
+	}
+	if udef.Siege != nil {
+		mobile := core.WeaponProfile{Damage: udef.Damage, Range: udef.Range, Cooldown: 1.5, DamageType: udef.DmgType}
+		if !w.Has(uid, core.CompWeapon) {
+			w.Attach(uid, &core.Weapon{Name: udef.Name, TargetType: core.TargetGround | core.TargetBuilding})
+		}
+		sg := &core.Siege{Mobile: mobile, Sieged: *udef.Siege}
+		sg.Apply(w.Get(uid, core.CompWeapon).(*core.Weapon))
+		w.Attach(uid, sg)
+	}
+
+	// MCV special component
+	if key == "mcv" {
+		w.Attach(uid, &core.MCV{CanDeploy: true})
+	}
+	return uid
+}
+
+// SpawnSearch is how many tiles out SpawnUnitNear looks for room
+const SpawnSearch = 4
+
+// SpawnUnitNear creates a unit on the free tile nearest world position
+// (x, y) that it can stand on. If no tile within SpawnSearch has room it
+// creates nothing and returns false. Without a map it spawns at (x, y).
+func SpawnUnitNear(w *core.World, tm *maplib.TileMap, key string, udef *UnitDef, playerID int, faction string, x, y float64) (core.EntityID, bool) {
+	uid := SpawnUnit(w, key, udef, playerID, faction, x, y)
+	if tm == nil {
+		return uid, true
+	}
+	tx, ty, ok := nearestFree(w, tm, uid, int(math.Floor(x)), int(math.Floor(y)), SpawnSearch)
+	if !ok {
+		// nothing holds the new entity yet, so it can go right away
+		w.Despawn(uid)
+		return 0, false
+	}
+	w.Attach(uid, &core.Position{X: float64(tx) + 0.5, Y: float64(ty) + 0.5})
+	return uid, true
+}
+
+// RadarKey is the building that powers the minimap radar
+const RadarKey = "radar"
+
+// PowerSystem recalculates power for all players each tick, announcing
+// when a player's demand first outgrows supply. A player's radar works
+// while they own a finished, switched-on radar and have enough power.
 type PowerSystem struct {
-	Players *core.PlayerManager
+	Players  *core.PlayerManager
+	EventBus *core.EventBus // optional
+
+	low map[int]bool // players already short of power
 }
 
 func (s *PowerSystem) Priority() int { return 5 }
@@ -252,6 +378,7 @@ func (s *PowerSystem) Update(w *core.World, _ float64) {
 		p.Power = 0
 		p.PowerUse = 0
 	}
+	radar := make(map[int]bool)
 	buildings := w.Query(core.CompBuilding, core.CompOwner)
 	for _, bid := range buildings {
 		b := w.Get(bid, core.CompBuilding).(*core.Building)
@@ -261,8 +388,121 @@ func (s *PowerSystem) Update(w *core.World, _ float64) {
 			continue
 		}
 		player.Power += b.PowerGen
-		player.PowerUse += b.PowerDraw
+		if !b.PoweredDown {
+			player.PowerUse += b.PowerDraw
+			if isRadar(w, bid) {
+				radar[own.PlayerID] = true
+			}
+		}
 	}
+	if s.low == nil {
+		s.low = make(map[int]bool)
+	}
+	for _, p := range s.Players.Players {
+		p.Radar = radar[p.ID] && p.HasPower()
+		low := !p.HasPower()
+		if low && !s.low[p.ID] && s.EventBus != nil {
+			s.EventBus.Publish(core.Event{Type: core.EvtLowPower, Tick: w.TickCount, Payload: p.ID})
+		}
+		s.low[p.ID] = low
+	}
+}
+
+// isRadar reports whether a building is a finished radar
+func isRadar(w *core.World, id core.EntityID) bool {
+	bn, ok := core.GetComponent[*core.BuildingName](w, id)
+	if !ok || bn.Key != RadarKey {
+		return false
+	}
+	bc, ok := core.GetComponent[*core.BuildingConstruction](w, id)
+	return !ok || bc.Complete
+}
+
+// Ore storage per building, in credits
+const (
+	RefineryStorage = 4000
+	SiloStorage     = 3000
+)
+
+// StorageSystem totals each player's ore capacity from refineries and silos
+type StorageSystem struct {
+	Players *core.PlayerManager
+}
+
+func (s *StorageSystem) Priority() int { return 5 }
+
+func (s *StorageSystem) Update(w *core.World, _ float64) {
+	for _, p := range s.Players.Players {
+		p.OreCapacity = 0
+	}
+	for _, bid := range w.Query(core.CompBuilding, core.CompOwner) {
+		b := w.Get(bid, core.CompBuilding).(*core.Building)
+		if b.Storage <= 0 {
+			continue
+		}
+		if bc := w.Get(bid, core.CompBuildingConstruction); bc != nil && !bc.(*core.BuildingConstruction).Complete {
+			continue
+		}
+		if player := s.Players.GetPlayer(w.Get(bid, core.CompOwner).(*core.Owner).PlayerID); player != nil {
+			player.OreCapacity += b.Storage
+		}
+	}
+	// Ore held by storage that was lost or sold stays spendable but no
+	// longer takes up room
+	for _, p := range s.Players.Players {
+		p.Ore = min(p.Ore, p.OreCapacity)
+	}
+}
+
+// SetPoweredDown switches a building off or back on and updates its
+// owner's power usage immediately. Buildings that draw no power can't be
+// powered down; returns false if nothing changed.
+func SetPoweredDown(w *core.World, id core.EntityID, pm *core.PlayerManager, down bool) bool {
+	bc := w.Get(id, core.CompBuilding)
+	if bc == nil {
+		return false
+	}
+	b := bc.(*core.Building)
+	if b.PowerDraw <= 0 || b.PoweredDown == down {
+		return false
+	}
+	b.PoweredDown = down
+	if oc := w.Get(id, core.CompOwner); oc != nil {
+		if p := pm.GetPlayer(oc.(*core.Owner).PlayerID); p != nil {
+			if down {
+				p.PowerUse -= b.PowerDraw
+			} else {
+				p.PowerUse += b.PowerDraw
+			}
+		}
+	}
+	return true
+}
+
+// TogglePower switches playerID's buildings among ids together: off if
+// any of them is running, otherwise back on. Other players' buildings are
+// left alone. Returns false if nothing changed.
+func TogglePower(w *core.World, pm *core.PlayerManager, ids []core.EntityID, playerID int) bool {
+	var own []core.EntityID
+	down := false
+	for _, id := range ids {
+		b, ok := core.GetComponent[*core.Building](w, id)
+		o, ok2 := core.GetComponent[*core.Owner](w, id)
+		if !ok || !ok2 || o.PlayerID != playerID {
+			continue
+		}
+		own = append(own, id)
+		if b.PowerDraw > 0 && !b.PoweredDown {
+			down = true
+		}
+	}
+	changed := false
+	for _, id := range own {
+		if SetPoweredDown(w, id, pm, down) {
+			changed = true
+		}
+	}
+	return changed
 }
 
 // BuildingConstructionSystem handles building construction animation
@@ -301,12 +541,20 @@ func (s *BuildingConstructionSystem) Update(w *core.World, dt float64) {
 			hp.Current = hp.Max
 
 			// Special: refinery spawns a harvester on completion
-			if bn := w.Get(id, core.CompBuildingName); bn != nil {
-				key := bn.(*core.BuildingName).Key
+			key := ""
+			if bn, ok := core.GetComponent[*core.BuildingName](w, id); ok {
+				key = bn.Key
 				if key == "refinery" {
 					s.spawnRefineryHarvester(w, id)
 				}
 			}
+			if s.EventBus != nil {
+				ev := core.UnitEvent{ID: id, PlayerID: core.NeutralPlayerID, Key: key}
+				if o, ok := core.GetComponent[*core.Owner](w, id); ok {
+					ev.PlayerID = o.PlayerID
+				}
+				s.EventBus.Publish(core.Event{Type: core.EvtBuildingComplete, Tick: w.TickCount, Payload: ev})
+			}
 		} else {
 			// Health increases with construction
 			hp := w.Get(id, core.CompHealth).(*core.Health)
@@ -330,12 +578,18 @@ func (s *BuildingConstructionSystem) spawnRefineryHarvester(w *core.World, refID
 	w.Attach(uid, &core.Health{Current: 600, Max: 600})
 	w.Attach(uid, &core.Movable{Speed: 1.5, MoveType: core.MoveVehicle})
 	w.Attach(uid, &core.Harvester{Capacity: 20, Rate: 2.0, Resource: "ore"})
+	w.Attach(uid, &core.Regen{Rate: HarvesterRegen, NearBuilding: "refinery", Radius: HarvesterRegenRadius})
 	w.Attach(uid, &core.Selectable{Radius: 0.6})
 	w.Attach(uid, &core.Owner{PlayerID: o.PlayerID, Faction: o.Faction})
 	w.Attach(uid, &core.FogVision{Range: 4})
+	key := "harvester_a"
+	if o.Faction == "Soviet" {
+		key = "harvester_s"
+	}
+	w.Attach(uid, &core.UnitType{Key: key})
 
 	if s.EventBus != nil {
-		s.EventBus.Emit(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
+		s.EventBus.Publish(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
 	}
 }
 
@@ -365,7 +619,7 @@ func DeployMCV(w *core.World, mcvID core.EntityID, eventBus *core.EventBus) core
 	w.Attach(cyID, &core.BuildingConstruction{Progress: 0, Rate: 0.2, Complete: false}) // 5 seconds build
 
 	if eventBus != nil {
-		eventBus.Emit(core.Event{Type: core.EvtBuildingPlaced, Tick: w.TickCount})
+		eventBus.Publish(core.Event{Type: core.EvtBuildingPlaced, Tick: w.TickCount})
 	}
 
 	return cyID
@@ -393,9 +647,10 @@ func UndeployConYard(w *core.World, cyID core.EntityID, eventBus *core.EventBus)
 	w.Attach(mcvID, &core.FogVision{Range: 6})
 	w.Attach(mcvID, &core.MCV{CanDeploy: true})
 	w.Attach(mcvID, &core.Armor{ArmorType: core.ArmorHeavy})
+	w.Attach(mcvID, &core.UnitType{Key: "mcv"})
 
 	if eventBus != nil {
-		eventBus.Emit(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
+		eventBus.Publish(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
 	}
 	return mcvID
 }
@@ -413,12 +668,16 @@ func PlaceBuilding(w *core.World, key string, tt *TechTree, playerID int, tileX,
 	w.Attach(id, &core.Building{
 		SizeX: bdef.SizeX, SizeY: bdef.SizeY,
 		PowerGen: bdef.PowerGen, PowerDraw: bdef.PowerDraw,
-		TechLevel: bdef.TechLevel, Sellable: true,
+		TechLevel: bdef.TechLevel, Sellable: true, Pad: bdef.Pad,
+		Storage: bdef.Storage,
 	})
 	w.Attach(id, &core.Owner{PlayerID: playerID, Faction: faction})
 	w.Attach(id, &core.FogVision{Range: 5})
 	w.Attach(id, &core.Selectable{Radius: 1.0})
 	w.Attach(id, &core.BuildingName{Key: key})
+	if key == "gate" {
+		w.Attach(id, &core.Gate{})
+	}
 
 	// Construction animation
 	buildRate := 1.0 / bdef.BuildTime // completes in BuildTime seconds
@@ -430,7 +689,7 @@ func PlaceBuilding(w *core.World, key string, tt *TechTree, playerID int, tileX,
 	}
 
 	if eventBus != nil {
-		eventBus.Emit(core.Event{Type: core.EvtBuildingPlaced, Tick: w.TickCount})
+		eventBus.Publish(core.Event{Type: core.EvtBuildingPlaced, Tick: w.TickCount, Payload: core.UnitEvent{ID: id, PlayerID: playerID, Key: key}})
 	}
 	return id
 }
@@ -453,6 +712,71 @@ func FreeTiles(tm TileMapOccupy, tileX, tileY, sizeX, sizeY int) {
 	}
 }
 
+// UnitsInFootprint returns the ground units standing on a building footprint
+func UnitsInFootprint(w *core.World, tileX, tileY, sizeX, sizeY int) []core.EntityID {
+	var ids []core.EntityID
+	for _, id := range w.Query(core.CompMovable, core.CompPosition) {
+		if w.Get(id, core.CompMovable).(*core.Movable).MoveType == core.MoveAir {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		tx, ty := int(math.Floor(pos.X)), int(math.Floor(pos.Y))
+		if tx >= tileX && tx < tileX+sizeX && ty >= tileY && ty < tileY+sizeY {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// PadAt reports whether a drive-on building covers tile (x, y)
+func PadAt(w *core.World, x, y int) bool {
+	for _, id := range w.Query(core.CompBuilding, core.CompPosition) {
+		b := w.Get(id, core.CompBuilding).(*core.Building)
+		if !b.Pad {
+			continue
+		}
+		pos := w.Get(id, core.CompPosition).(*core.Position)
+		bx, by := int(pos.X), int(pos.Y)
+		if x >= bx && x < bx+b.SizeX && y >= by && y < by+b.SizeY {
+			return true
+		}
+	}
+	return false
+}
+
+// TileBuildable reports whether terrain and occupancy allow building on a tile
+func TileBuildable(w *core.World, tm *maplib.TileMap, x, y int) bool {
+	tile := tm.At(x, y)
+	if tile == nil {
+		return false
+	}
+	// Can't build on water, deep water, cliffs
+	if tile.Terrain == maplib.TerrainWater || tile.Terrain == maplib.TerrainDeepWater || tile.Terrain == maplib.TerrainCliff {
+		return false
+	}
+	// Can't overlap existing buildings, including drive-on pads
+	return !tile.Occupied && !PadAt(w, x, y)
+}
+
+// FootprintClear reports whether every tile under a footprint is buildable
+// and no ground unit other than ignore stands on it
+func FootprintClear(w *core.World, tm *maplib.TileMap, tileX, tileY, sizeX, sizeY int, ignore core.EntityID) bool {
+	for dy := 0; dy < sizeY; dy++ {
+		for dx := 0; dx < sizeX; dx++ {
+			if !TileBuildable(w, tm, tileX+dx, tileY+dy) {
+				return false
+			}
+		}
+	}
+	// Don't trap units under the new building
+	for _, id := range UnitsInFootprint(w, tileX, tileY, sizeX, sizeY) {
+		if id != ignore {
+			return false
+		}
+	}
+	return true
+}
+
 // TileMapOccupy interface for marking tiles
 type TileMapOccupy interface {
 	SetOccupied(x, y int, occupied bool)
@@ -460,7 +784,7 @@ type TileMapOccupy interface {
 
 // BuildingKeyOrder returns building keys in a stable order for sidebar display
 func (tt *TechTree) BuildingKeyOrder() []string {
-	order := []string{"power_plant", "barracks", "refinery", "war_factory", "radar"}
+	order := []string{"power_plant", "barracks", "refinery", "war_factory", "radar", "silo", "service_depot"}
 	var result []string
 	for _, k := range order {
 		if _, ok := tt.Buildings[k]; ok {
@@ -472,7 +796,7 @@ func (tt *TechTree) BuildingKeyOrder() []string {
 
 // DefenseKeyOrder returns defense building keys in a stable order
 func (tt *TechTree) DefenseKeyOrder() []string {
-	order := []string{"pillbox", "prism_tower", "wall"}
+	order := []string{"pillbox", "prism_tower", "wall", "gate"}
 	var result []string
 	for _, k := range order {
 		if _, ok := tt.Buildings[k]; ok {
@@ -484,7 +808,7 @@ func (tt *TechTree) DefenseKeyOrder() []string {
 
 // UnitKeyOrder returns unit keys in a stable order for sidebar display
 func (tt *TechTree) UnitKeyOrder() []string {
-	order := []string{"gi", "conscript", "engineer", "attack_dog", "grizzly", "rhino", "ifv", "harvester_a", "harvester_s", "mcv"}
+	order := []string{"gi", "conscript", "engineer", "attack_dog", "yuri", "chrono_legion", "grizzly", "rhino", "ifv", "harvester_a", "harvester_s", "v3", "mcv"}
 	var result []string
 	for _, k := range order {
 		if _, ok := tt.Units[k]; ok {
@@ -516,7 +840,7 @@ func RepairBuilding(w *core.World, id core.EntityID, pm *core.PlayerManager, dt
 	if float64(player.Credits) < costRate {
 		return false
 	}
-	player.Credits -= int(costRate)
+	player.Spend(int(costRate))
 	if player.Credits < 0 {
 		player.Credits = 0
 	}
@@ -527,6 +851,71 @@ func RepairBuilding(w *core.World, id core.EntityID, pm *core.PlayerManager, dt
 	return true
 }
 
+// QueueUnits pays for and queues up to n units of a type one at a time,
+// stopping once the player can't afford the next or every queue is full.
+// It returns how many were queued.
+func QueueUnits(w *core.World, tt *TechTree, pm *core.PlayerManager, playerID int, unitKey string, n int) int {
+	udef, ok := tt.Units[unitKey]
+	player := pm.GetPlayer(playerID)
+	if !ok || player == nil {
+		return 0
+	}
+	queued := 0
+	for ; queued < n && player.Credits >= udef.Cost; queued++ {
+		bid := FindProductionBuilding(w, tt, playerID, unitKey)
+		if bid == 0 {
+			break
+		}
+		prod := w.Get(bid, core.CompProduction).(*core.Production)
+		player.Spend(udef.Cost)
+		prod.Queue = append(prod.Queue, unitKey)
+	}
+	return queued
+}
+
+// DequeueUnit removes the most recently queued unit of a type from the
+// player's factories. A unit not yet started is refunded in full, one in
+// progress for what remains of it.
+func DequeueUnit(w *core.World, tt *TechTree, pm *core.PlayerManager, playerID int, unitKey string) bool {
+	var best core.EntityID
+	bestIdx := -1
+	for _, bid := range w.Query(core.CompProduction, core.CompOwner) {
+		if w.Get(bid, core.CompOwner).(*core.Owner).PlayerID != playerID {
+			continue
+		}
+		prod := w.Get(bid, core.CompProduction).(*core.Production)
+		for i := len(prod.Queue) - 1; i >= 0; i-- {
+			if prod.Queue[i] != unitKey {
+				continue
+			}
+			// Queries come back in map order; break ties by ID
+			if i > bestIdx || (i == bestIdx && bid < best) {
+				best, bestIdx = bid, i
+			}
+			break
+		}
+	}
+	if bestIdx < 0 {
+		return false
+	}
+	prod := w.Get(best, core.CompProduction).(*core.Production)
+	refund := 0
+	if udef, ok := tt.Units[unitKey]; ok {
+		refund = udef.Cost
+		if bestIdx == 0 {
+			refund = int(float64(udef.Cost) * (1.0 - prod.Progress))
+		}
+	}
+	if bestIdx == 0 {
+		prod.Progress = 0
+	}
+	prod.Queue = append(prod.Queue[:bestIdx], prod.Queue[bestIdx+1:]...)
+	if player := pm.GetPlayer(playerID); player != nil {
+		player.Refund(refund)
+	}
+	return true
+}
+
 // CancelUnitProduction cancels the first unit in queue, refunding based on progress
 func CancelUnitProduction(w *core.World, tt *TechTree, buildingID core.EntityID, pm *core.PlayerManager) {
 	prod := w.Get(buildingID, core.CompProduction)
@@ -550,7 +939,7 @@ func CancelUnitProduction(w *core.World, tt *TechTree, buildingID core.EntityID,
 	refund := int(float64(udef.Cost) * (1.0 - p.Progress))
 	player := pm.GetPlayer(o.PlayerID)
 	if player != nil {
-		player.Credits += refund
+		player.Refund(refund)
 	}
 	p.Queue = p.Queue[1:]
 	p.Progress = 0
@@ -578,7 +967,7 @@ func SellBuilding(w *core.World, id core.EntityID, tt *TechTree, pm *core.Player
 }
 
 // CanPlaceBuilding checks if a building can be placed at the given tile
-func CanPlaceBuilding(w *core.World, tileX, tileY, sizeX, sizeY, playerID int, tm interface{ InBounds(int, int) bool; IsPassable(int, int, interface{}) bool }) bool {
+func CanPlaceBuilding(w *core.World, tt *TechTree, tileX, tileY, sizeX, sizeY, playerID int, faction string, tm interface{ InBounds(int, int) bool }) bool {
 	// Check bounds
 	for dy := 0; dy < sizeY; dy++ {
 		for dx := 0; dx < sizeX; dx++ {
@@ -587,21 +976,5 @@ func CanPlaceBuilding(w *core.World, tileX, tileY, sizeX, sizeY, playerID int, t
 			}
 		}
 	}
-
-	// Check near existing buildings (build radius)
-	nearBuilding := false
-	for _, bid := range w.Query(core.CompBuilding, core.CompOwner, core.CompPosition) {
-		o := w.Get(bid, core.CompOwner).(*core.Owner)
-		if o.PlayerID != playerID {
-			continue
-		}
-		bp := w.Get(bid, core.CompPosition).(*core.Position)
-		dx := float64(tileX) - bp.X
-		dy := float64(tileY) - bp.Y
-		if dx*dx+dy*dy < 100 { // within ~10 tiles
-			nearBuilding = true
-			break
-		}
-	}
-	return nearBuilding
+	return InBuildRadius(w, tt, playerID, faction, tileX, tileY)
 }
@@ -0,0 +1,315 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+	"github.com/1siamBot/rts-engine/engine/pathfind"
+)
+
+func spawnGroundUnit(w *core.World, x, y float64, mt core.MoveType) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: x, Y: y})
+	w.Attach(id, &core.Movable{Speed: 1, MoveType: mt})
+	return id
+}
+
+func TestFootprintClearRejectsUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		x, y     float64
+		moveType core.MoveType
+		ignore   bool
+		want     bool
+	}{
+		{"harvester on footprint", 5.5, 5.5, core.MoveVehicle, false, false},
+		{"infantry on footprint edge", 6.9, 6.1, core.MoveInfantry, false, false},
+		{"unit beside footprint", 7.5, 5.5, core.MoveVehicle, false, true},
+		{"aircraft overhead", 5.5, 5.5, core.MoveAir, false, true},
+		{"ignored unit", 5.5, 5.5, core.MoveVehicle, true, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			id := spawnGroundUnit(w, tc.x, tc.y, tc.moveType)
+			var ignore core.EntityID
+			if tc.ignore {
+				ignore = id
+			}
+			if got := FootprintClear(w, tm, 5, 5, 2, 2, ignore); got != tc.want {
+				t.Errorf("FootprintClear = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFootprintClearTerrain(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(tm *maplib.TileMap)
+		x, y  int
+		want  bool
+	}{
+		{"open ground", func(*maplib.TileMap) {}, 5, 5, true},
+		{"water", func(tm *maplib.TileMap) { tm.At(6, 5).Terrain = maplib.TerrainWater }, 5, 5, false},
+		{"cliff", func(tm *maplib.TileMap) { tm.At(5, 6).Terrain = maplib.TerrainCliff }, 5, 5, false},
+		{"occupied", func(tm *maplib.TileMap) { tm.SetOccupied(6, 6, true) }, 5, 5, false},
+		{"off the map", func(*maplib.TileMap) {}, 15, 5, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			tc.setup(tm)
+			if got := FootprintClear(w, tm, tc.x, tc.y, 2, 2, 0); got != tc.want {
+				t.Errorf("FootprintClear = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func spawnFactory(w *core.World, owner int) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: 4, Y: 4})
+	w.Attach(id, &core.Owner{PlayerID: owner})
+	w.Attach(id, &core.Building{SizeX: 3, SizeY: 3})
+	w.Attach(id, &core.BuildingName{Key: "war_factory"})
+	w.Attach(id, &core.Production{Rate: 1, Rally: core.TilePos{X: 8, Y: 8}})
+	return id
+}
+
+func TestQueueUnitsAffordable(t *testing.T) {
+	tests := []struct {
+		name    string
+		credits int
+		n       int
+		want    int
+	}{
+		{"funds for all", 5000, 5, 5},
+		{"funds for three", 700*3 + 300, 5, 3},
+		{"funds for none", 699, 5, 0},
+		{"queue caps at five", 10000, 8, 5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tt := NewTechTree()
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0, Credits: tc.credits})
+			f := spawnFactory(w, 0)
+
+			if got := QueueUnits(w, tt, pm, 0, "grizzly", tc.n); got != tc.want {
+				t.Fatalf("QueueUnits = %d, want %d", got, tc.want)
+			}
+			prod, _ := core.GetComponent[*core.Production](w, f)
+			if len(prod.Queue) != tc.want {
+				t.Errorf("queue length = %d, want %d", len(prod.Queue), tc.want)
+			}
+			p := pm.GetPlayer(0)
+			if p.Credits != tc.credits-700*tc.want {
+				t.Errorf("credits = %d, want %d", p.Credits, tc.credits-700*tc.want)
+			}
+
+			// Cancelling everything refunds in full
+			for i := 0; i < tc.want; i++ {
+				if !DequeueUnit(w, tt, pm, 0, "grizzly") {
+					t.Fatalf("DequeueUnit %d failed", i)
+				}
+			}
+			if DequeueUnit(w, tt, pm, 0, "grizzly") {
+				t.Error("DequeueUnit succeeded on an empty queue")
+			}
+			if p.Credits != tc.credits || p.Spent != 0 {
+				t.Errorf("after cancelling: credits %d spent %d, want %d and 0", p.Credits, p.Spent, tc.credits)
+			}
+		})
+	}
+}
+
+func TestDequeueUnitRefundsProgress(t *testing.T) {
+	w := core.NewWorld(20)
+	tt := NewTechTree()
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, Credits: 700})
+	f := spawnFactory(w, 0)
+	QueueUnits(w, tt, pm, 0, "grizzly", 1)
+	prod, _ := core.GetComponent[*core.Production](w, f)
+	prod.Progress = 0.25
+
+	DequeueUnit(w, tt, pm, 0, "grizzly")
+	if got := pm.GetPlayer(0).Credits; got != 525 {
+		t.Errorf("credits = %d, want 525 back for a quarter-built tank", got)
+	}
+	if prod.Progress != 0 {
+		t.Errorf("progress = %v, want reset to 0", prod.Progress)
+	}
+}
+
+func TestWarModeSendsNewUnitsToAttackPoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		warMode bool
+		unit    string
+		want    bool
+	}{
+		{"war mode tank", true, "grizzly", true},
+		{"war mode off", false, "grizzly", false},
+		{"unarmed unit", true, "mcv", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tt := NewTechTree()
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0, WarMode: tc.warMode, AttackPoint: core.TilePos{X: 24, Y: 20}})
+			ng := pathfind.NewNavGrid(maplib.NewTileMap("t", 32, 32))
+			f := spawnFactory(w, 0)
+			prod, _ := core.GetComponent[*core.Production](w, f)
+			prod.Queue = []string{tc.unit}
+			prod.Progress = 0.9999
+
+			before := map[core.EntityID]bool{}
+			for _, id := range w.Query(core.CompMovable) {
+				before[id] = true
+			}
+			sys := &ProductionSystem{TechTree: tt, Players: pm, NavGrid: ng}
+			sys.Update(w, 1)
+
+			var uid core.EntityID
+			for _, id := range w.Query(core.CompMovable) {
+				if !before[id] {
+					uid = id
+				}
+			}
+			if uid == 0 {
+				t.Fatal("no unit was produced")
+			}
+			m, _ := core.GetComponent[*core.Movable](w, uid)
+			if m.AttackMove != tc.want {
+				t.Errorf("AttackMove = %v, want %v", m.AttackMove, tc.want)
+			}
+			if !tc.want {
+				return
+			}
+			if len(m.Path) == 0 {
+				t.Fatal("no path toward the attack point")
+			}
+			if last := m.Path[len(m.Path)-1]; last != (core.TilePos{X: 24, Y: 20}) {
+				t.Errorf("path ends at %v, want the attack point (24, 20)", last)
+			}
+		})
+	}
+}
+
+func TestStorageCapacity(t *testing.T) {
+	tests := []struct {
+		name         string
+		buildings    []string
+		constructing bool
+		ore          int
+		wantCapacity int
+		wantOre      int
+	}{
+		{"refinery", []string{"refinery"}, false, 0, RefineryStorage, 0},
+		{"refinery and silos", []string{"refinery", "silo", "silo"}, false, 0, RefineryStorage + 2*SiloStorage, 0},
+		{"silo still building", []string{"silo"}, true, 0, 0, 0},
+		{"storage lost", nil, false, 2500, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0, Credits: 10000, Ore: tc.ore})
+			tt := NewTechTree()
+			for _, key := range tc.buildings {
+				id := spawnBuilding(w, key, 0, 4, 4, 1)
+				w.Get(id, core.CompBuilding).(*core.Building).Storage = tt.Buildings[key].Storage
+				if tc.constructing {
+					w.Attach(id, &core.BuildingConstruction{})
+				}
+			}
+			w.AddSystem(&StorageSystem{Players: pm})
+			w.Tick(0.05)
+
+			p := pm.GetPlayer(0)
+			if p.OreCapacity != tc.wantCapacity || p.Ore != tc.wantOre {
+				t.Errorf("capacity %d, ore %d; want %d, %d", p.OreCapacity, p.Ore, tc.wantCapacity, tc.wantOre)
+			}
+			if p.Credits != 10000 {
+				t.Errorf("credits changed to %d", p.Credits)
+			}
+		})
+	}
+}
+
+func TestPrimaryFactory(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(w *core.World, a, b core.EntityID)
+		want  int // 0 for a, 1 for b
+	}{
+		{"no primary uses the oldest", func(*core.World, core.EntityID, core.EntityID) {}, 0},
+		{"primary set", func(w *core.World, _, b core.EntityID) { SetPrimary(w, b) }, 1},
+		{"primary moved", func(w *core.World, a, b core.EntityID) { SetPrimary(w, b); SetPrimary(w, a) }, 0},
+		{"primary destroyed", func(w *core.World, _, b core.EntityID) { SetPrimary(w, b); w.Despawn(b) }, 0},
+		{"primary queue full", func(w *core.World, _, b core.EntityID) {
+			SetPrimary(w, b)
+			w.Get(b, core.CompProduction).(*core.Production).Queue = []string{"gi", "gi", "gi", "gi", "gi"}
+		}, 0},
+		{"other player's primary", func(w *core.World, _, _ core.EntityID) {
+			SetPrimary(w, spawnFactory(w, 1))
+		}, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tt := NewTechTree()
+			a := spawnFactory(w, 0)
+			b := spawnFactory(w, 0)
+			tc.setup(w, a, b)
+			want := []core.EntityID{a, b}[tc.want]
+			if got := FindProductionBuilding(w, tt, 0, "grizzly"); got != want {
+				t.Errorf("FindProductionBuilding = %d, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestUnitsExitPrimaryFactory(t *testing.T) {
+	w := core.NewWorld(20)
+	tt := NewTechTree()
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, Credits: 10000})
+	spawnFactory(w, 0)
+	primary := spawnFactory(w, 0)
+	w.Get(primary, core.CompPosition).(*core.Position).X = 20
+	w.Get(primary, core.CompProduction).(*core.Production).Rally = core.TilePos{X: 24, Y: 8}
+	SetPrimary(w, primary)
+	w.AddSystem(&ProductionSystem{TechTree: tt, Players: pm})
+
+	if QueueUnits(w, tt, pm, 0, "grizzly", 2) != 2 {
+		t.Fatal("couldn't queue units")
+	}
+	before := map[core.EntityID]bool{}
+	for _, id := range w.Query(core.CompUnitType) {
+		before[id] = true
+	}
+	for i := 0; i < 400; i++ {
+		w.Tick(0.05)
+	}
+	made := 0
+	for _, id := range w.Query(core.CompUnitType, core.CompPosition) {
+		if before[id] {
+			continue
+		}
+		made++
+		if pos := w.Get(id, core.CompPosition).(*core.Position); int(pos.X) != 24 {
+			t.Errorf("unit %d came out at x=%.1f, not at the primary's rally point", id, pos.X)
+		}
+	}
+	if made != 2 {
+		t.Errorf("%d units built, want 2", made)
+	}
+}
@@ -4,11 +4,13 @@ import (
 	"math"
 
 	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
 )
 
 // ProjectileSystem moves projectiles and handles impact
 type ProjectileSystem struct {
 	EventBus *core.EventBus
+	TileMap  *maplib.TileMap // optional: cliffs and buildings stop shots
 }
 
 func (s *ProjectileSystem) Priority() int { return 25 }
@@ -30,7 +32,14 @@ func (s *ProjectileSystem) Update(w *core.World, dt float64) {
 		dy := proj.TargetY - pos.Y
 		dist := math.Sqrt(dx*dx + dy*dy)
 
-		if dist < 0.3 {
+		// A shot that flies into a cliff or building bursts there; splash
+		// still lands but the target takes no direct hit
+		blocked := false
+		if s.TileMap != nil && !isAirborne(w, proj.TargetID) {
+			blocked = shotBlockedAt(w, s.TileMap, int(pos.X), int(pos.Y), proj.SourceID, proj.TargetID)
+		}
+
+		if dist < 0.3 || blocked {
 			// Hit!
 			if proj.Splash > 0 {
 				// AoE damage
@@ -43,18 +52,19 @@ func (s *ProjectileSystem) Update(w *core.World, dt float64) {
 					d := math.Sqrt(math.Pow(tp.X-pos.X, 2) + math.Pow(tp.Y-pos.Y, 2))
 					if d <= proj.Splash {
 						scale := 1.0 - d/proj.Splash
-						dmg := int(float64(proj.Damage) * scale)
+						dmg := int(float64(proj.Damage) * scale * forestCover(w, s.TileMap, tid))
 						if dmg < 1 {
 							dmg = 1
 						}
-						ApplyDamage(w, tid, dmg, proj.DmgType, s.EventBus)
+						ApplyDamageFrom(w, proj.SourceID, tid, dmg, proj.DmgType, s.EventBus)
 					}
 				}
-			} else {
-				ApplyDamage(w, proj.TargetID, proj.Damage, proj.DmgType, s.EventBus)
+			} else if !blocked {
+				ApplyDamageFrom(w, proj.SourceID, proj.TargetID, int(float64(proj.Damage)*forestCover(w, s.TileMap, proj.TargetID)), proj.DmgType, s.EventBus)
 			}
+			hitTerrain(s.TileMap, int(pos.X), int(pos.Y), proj.Damage, proj.DmgType)
 			if s.EventBus != nil {
-				s.EventBus.Emit(core.Event{Type: core.EvtProjectileHit, Tick: w.TickCount})
+				s.EventBus.Publish(core.Event{Type: core.EvtProjectileHit, Tick: w.TickCount})
 			}
 			w.Destroy(id)
 			continue
@@ -0,0 +1,155 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+// Service depot tuning
+const (
+	DepotRepairRate = 0.08 // fraction of max HP restored per second
+	DepotCostRatio  = 0.5  // a full repair costs this fraction of the unit's price
+
+	HarvesterRegen       = 3.0 // HP per second a harvester heals near a refinery
+	HarvesterRegenRadius = 5.0
+)
+
+// RegenSystem heals entities with a Regen component
+type RegenSystem struct{}
+
+func (s *RegenSystem) Priority() int { return 56 }
+
+func (s *RegenSystem) Update(w *core.World, dt float64) {
+	for _, id := range w.Query(core.CompRegen, core.CompHealth, core.CompPosition) {
+		r := w.Get(id, core.CompRegen).(*core.Regen)
+		hp := w.Get(id, core.CompHealth).(*core.Health)
+		if hp.Current <= 0 || hp.Current >= hp.Max {
+			r.Carry = 0
+			continue
+		}
+		if r.NearBuilding != "" && !nearOwnBuilding(w, id, r.NearBuilding, r.Radius) {
+			continue
+		}
+		r.Carry += r.Rate * dt
+		heal := int(r.Carry)
+		r.Carry -= float64(heal)
+		hp.Current = min(hp.Current+heal, hp.Max)
+	}
+}
+
+// nearOwnBuilding reports whether id is within radius of a completed
+// building with the given key owned by the same player
+func nearOwnBuilding(w *core.World, id core.EntityID, key string, radius float64) bool {
+	own := w.Get(id, core.CompOwner)
+	if own == nil {
+		return false
+	}
+	pos := w.Get(id, core.CompPosition).(*core.Position)
+	for _, bid := range w.Query(core.CompBuilding, core.CompBuildingName, core.CompOwner, core.CompPosition) {
+		if w.Get(bid, core.CompBuildingName).(*core.BuildingName).Key != key ||
+			w.Get(bid, core.CompOwner).(*core.Owner).PlayerID != own.(*core.Owner).PlayerID {
+			continue
+		}
+		if bc := w.Get(bid, core.CompBuildingConstruction); bc != nil && !bc.(*core.BuildingConstruction).Complete {
+			continue
+		}
+		if pos.DistanceTo(w.Get(bid, core.CompPosition).(*core.Position)) <= radius {
+			return true
+		}
+	}
+	return false
+}
+
+// RepairDepotSystem repairs damaged vehicles parked on a service depot's
+// footprint, charging the owner as it goes. Repairs stop when credits run
+// out or the depot is powered down.
+type RepairDepotSystem struct {
+	TechTree *TechTree
+	Players  *core.PlayerManager
+
+	heal map[core.EntityID]float64 // fractional HP owed per vehicle
+	cost map[int]float64           // fractional credits owed per player
+}
+
+func (s *RepairDepotSystem) Priority() int { return 57 }
+
+func (s *RepairDepotSystem) Update(w *core.World, dt float64) {
+	if s.heal == nil {
+		s.heal = make(map[core.EntityID]float64)
+		s.cost = make(map[int]float64)
+	}
+	onPad := make(map[core.EntityID]bool)
+	for _, did := range w.Query(core.CompBuilding, core.CompBuildingName, core.CompOwner, core.CompPosition) {
+		if w.Get(did, core.CompBuildingName).(*core.BuildingName).Key != "service_depot" {
+			continue
+		}
+		b := w.Get(did, core.CompBuilding).(*core.Building)
+		if b.PoweredDown {
+			continue
+		}
+		if bc := w.Get(did, core.CompBuildingConstruction); bc != nil && !bc.(*core.BuildingConstruction).Complete {
+			continue
+		}
+		owner := w.Get(did, core.CompOwner).(*core.Owner).PlayerID
+		player := s.Players.GetPlayer(owner)
+		if player == nil {
+			continue
+		}
+		dpos := w.Get(did, core.CompPosition).(*core.Position)
+		for _, uid := range UnitsInFootprint(w, int(dpos.X), int(dpos.Y), b.SizeX, b.SizeY) {
+			if own := w.Get(uid, core.CompOwner); own == nil || own.(*core.Owner).PlayerID != owner {
+				continue
+			}
+			if w.Get(uid, core.CompMovable).(*core.Movable).MoveType != core.MoveVehicle {
+				continue
+			}
+			onPad[uid] = true
+			s.repair(w, uid, player, dt)
+		}
+	}
+	// a vehicle that drove off keeps no partial repair for its next visit
+	for uid := range s.heal {
+		if !onPad[uid] {
+			delete(s.heal, uid)
+		}
+	}
+}
+
+// OnDespawn drops the fractional repair owed to a removed vehicle
+func (s *RepairDepotSystem) OnDespawn(_ *core.World, id core.EntityID) {
+	delete(s.heal, id)
+}
+
+// repair restores one vehicle for a tick if its owner can pay
+func (s *RepairDepotSystem) repair(w *core.World, uid core.EntityID, player *core.Player, dt float64) {
+	hc := w.Get(uid, core.CompHealth)
+	if hc == nil {
+		return
+	}
+	hp := hc.(*core.Health)
+	if hp.Current <= 0 || hp.Current >= hp.Max {
+		delete(s.heal, uid)
+		return
+	}
+	price := 0
+	if ut := w.Get(uid, core.CompUnitType); ut != nil {
+		if udef, ok := s.TechTree.Units[ut.(*core.UnitType).Key]; ok {
+			price = udef.Cost
+		}
+	}
+
+	s.heal[uid] += float64(hp.Max) * DepotRepairRate * dt
+	heal := min(int(s.heal[uid]), hp.Max-hp.Current)
+	if heal <= 0 {
+		return
+	}
+	owed := s.cost[player.ID] + float64(price)*DepotCostRatio*float64(heal)/float64(hp.Max)
+	if float64(player.Credits) < owed {
+		s.heal[uid] = 0
+		return
+	}
+	s.heal[uid] -= float64(heal)
+	hp.Current += heal
+	charge := int(owed)
+	player.Spend(charge)
+	s.cost[player.ID] = owed - float64(charge)
+}
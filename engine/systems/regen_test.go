@@ -0,0 +1,133 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+func spawnBuilding(w *core.World, key string, owner int, x, y float64, size int) core.EntityID {
+	id := w.Spawn()
+	w.Attach(id, &core.Position{X: x, Y: y})
+	w.Attach(id, &core.Owner{PlayerID: owner})
+	w.Attach(id, &core.Building{SizeX: size, SizeY: size})
+	w.Attach(id, &core.BuildingName{Key: key})
+	return id
+}
+
+func TestRegenNearBuilding(t *testing.T) {
+	tests := []struct {
+		name     string
+		near     string
+		refOwner int
+		refX     float64
+		building bool // refinery still under construction
+		want     bool
+	}{
+		{"unconditional", "", 0, 40, false, true},
+		{"next to own refinery", "refinery", 0, 12, false, true},
+		{"far from refinery", "refinery", 0, 40, false, false},
+		{"next to enemy refinery", "refinery", 1, 12, false, false},
+		{"refinery not finished", "refinery", 0, 12, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			ref := spawnBuilding(w, "refinery", tc.refOwner, tc.refX, 10, 3)
+			if tc.building {
+				w.Attach(ref, &core.BuildingConstruction{})
+			}
+			id := spawnGroundUnit(w, 10, 10, core.MoveVehicle)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			w.Attach(id, &core.Health{Current: 100, Max: 600})
+			w.Attach(id, &core.Regen{Rate: HarvesterRegen, NearBuilding: tc.near, Radius: HarvesterRegenRadius})
+
+			w.AddSystem(&RegenSystem{})
+			for i := 0; i < 20; i++ {
+				w.Tick(0.1)
+			}
+			hp, _ := core.GetComponent[*core.Health](w, id)
+			if got := hp.Current > 100; got != tc.want {
+				t.Errorf("healed = %v (HP %d), want %v", got, hp.Current, tc.want)
+			}
+		})
+	}
+}
+
+func TestRefineryHarvesterRegensNearHome(t *testing.T) {
+	w := core.NewWorld(20)
+	tt := NewTechTree()
+	for _, key := range []string{"harvester_a", "harvester_s"} {
+		id := SpawnUnit(w, key, tt.Units[key], 0, "", 1, 1)
+		r, ok := core.GetComponent[*core.Regen](w, id)
+		if !ok || r.NearBuilding != "refinery" || r.Radius <= 0 {
+			t.Errorf("%s regen = %+v, want refinery-bound regen", key, r)
+		}
+	}
+}
+
+func TestRepairDepot(t *testing.T) {
+	tests := []struct {
+		name      string
+		moveType  core.MoveType
+		credits   int
+		powerDown bool
+		wantHeal  bool
+	}{
+		{"vehicle on pad", core.MoveVehicle, 5000, false, true},
+		{"infantry on pad", core.MoveInfantry, 5000, false, false},
+		{"owner is broke", core.MoveVehicle, 0, false, false},
+		{"depot powered down", core.MoveVehicle, 5000, true, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0, Credits: tc.credits})
+			depot := spawnBuilding(w, "service_depot", 0, 4, 4, 3)
+			w.Get(depot, core.CompBuilding).(*core.Building).PoweredDown = tc.powerDown
+			id := spawnGroundUnit(w, 5.5, 5.5, tc.moveType)
+			w.Attach(id, &core.Owner{PlayerID: 0})
+			w.Attach(id, &core.Health{Current: 100, Max: 500})
+			w.Attach(id, &core.UnitType{Key: "grizzly"})
+
+			w.AddSystem(&RepairDepotSystem{TechTree: NewTechTree(), Players: pm})
+			for i := 0; i < 50; i++ {
+				w.Tick(0.1)
+			}
+			hp, _ := core.GetComponent[*core.Health](w, id)
+			if got := hp.Current > 100; got != tc.wantHeal {
+				t.Fatalf("healed = %v (HP %d), want %v", got, hp.Current, tc.wantHeal)
+			}
+			if charged := pm.GetPlayer(0).Credits < tc.credits; charged != tc.wantHeal {
+				t.Errorf("charged = %v, want %v", charged, tc.wantHeal)
+			}
+		})
+	}
+}
+
+func TestRepairDepotForgetsDepartedVehicles(t *testing.T) {
+	w := core.NewWorld(20)
+	pm := core.NewPlayerManager()
+	pm.AddPlayer(&core.Player{ID: 0, Credits: 5000})
+	spawnBuilding(w, "service_depot", 0, 4, 4, 3)
+	leaver := spawnGroundUnit(w, 5.5, 5.5, core.MoveVehicle)
+	dying := spawnGroundUnit(w, 4.5, 4.5, core.MoveVehicle)
+	for _, id := range []core.EntityID{leaver, dying} {
+		w.Attach(id, &core.Owner{PlayerID: 0})
+		w.Attach(id, &core.Health{Current: 100, Max: 1000})
+	}
+	s := &RepairDepotSystem{TechTree: NewTechTree(), Players: pm}
+	w.AddSystem(s)
+	w.Tick(0.05)
+	if len(s.heal) != 2 {
+		t.Fatalf("tracking %d vehicles, want 2", len(s.heal))
+	}
+
+	w.Get(leaver, core.CompPosition).(*core.Position).X = 20
+	w.Destroy(dying)
+	w.Tick(0.05)
+	if len(s.heal) != 0 {
+		t.Errorf("still tracking %v after the vehicles left", s.heal)
+	}
+}
@@ -0,0 +1,89 @@
+package systems
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+// PlayerStats tallies one player's match
+type PlayerStats struct {
+	UnitsBuilt       int
+	UnitsLost        int
+	UnitsKilled      int
+	BuildingsBuilt   int
+	BuildingsLost    int
+	BuildingsKilled  int
+	CreditsHarvested int
+	CreditsSpent     int
+}
+
+// Score condenses the tally into a single number
+func (ps PlayerStats) Score() int {
+	return ps.UnitsKilled*10 + ps.BuildingsKilled*25 + ps.UnitsBuilt*2 + ps.BuildingsBuilt*5 + ps.CreditsHarvested/100
+}
+
+// StatsSystem counts match events per player
+type StatsSystem struct {
+	Players *core.PlayerManager
+	Stats   map[int]*PlayerStats
+}
+
+// NewStatsSystem creates a stats system listening on the event bus
+func NewStatsSystem(bus *core.EventBus, pm *core.PlayerManager) *StatsSystem {
+	s := &StatsSystem{Players: pm, Stats: make(map[int]*PlayerStats)}
+	core.Subscribe(bus, core.EvtUnitCreated, func(ev core.UnitEvent) {
+		s.For(ev.PlayerID).UnitsBuilt++
+	})
+	core.Subscribe(bus, core.EvtBuildingPlaced, func(ev core.UnitEvent) {
+		s.For(ev.PlayerID).BuildingsBuilt++
+	})
+	core.Subscribe(bus, core.EvtUnitDestroyed, func(ev core.KillEvent) {
+		s.For(ev.PlayerID).UnitsLost++
+		if ev.Credited && ev.KillerOwner != ev.PlayerID {
+			s.For(ev.KillerOwner).UnitsKilled++
+		}
+	})
+	core.Subscribe(bus, core.EvtBuildingDestroyed, func(ev core.KillEvent) {
+		s.For(ev.PlayerID).BuildingsLost++
+		if ev.Credited && ev.KillerOwner != ev.PlayerID {
+			s.For(ev.KillerOwner).BuildingsKilled++
+		}
+	})
+	core.Subscribe(bus, core.EvtResourceHarvested, func(ev core.HarvestEvent) {
+		s.For(ev.PlayerID).CreditsHarvested += ev.Amount
+	})
+	return s
+}
+
+func (s *StatsSystem) Priority() int { return 99 }
+
+// Update copies spending, which is tracked on the player rather than evented
+func (s *StatsSystem) Update(_ *core.World, _ float64) {
+	if s.Players == nil {
+		return
+	}
+	for _, p := range s.Players.Players {
+		s.For(p.ID).CreditsSpent = p.Spent
+	}
+}
+
+// For returns a player's tally, creating it on first use
+func (s *StatsSystem) For(playerID int) *PlayerStats {
+	ps, ok := s.Stats[playerID]
+	if !ok {
+		ps = &PlayerStats{}
+		s.Stats[playerID] = ps
+	}
+	return ps
+}
+
+// Save writes every player's tally as JSON
+func (s *StatsSystem) Save(path string) error {
+	data, err := json.MarshalIndent(s.Stats, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
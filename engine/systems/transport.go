@@ -0,0 +1,53 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// LoadUnit puts a unit into a transport with room for it. The unit stops
+// and leaves the map until it is unloaded.
+func LoadUnit(w *core.World, transportID, unitID core.EntityID) bool {
+	tc := w.Get(transportID, core.CompTransport)
+	if tc == nil || !w.Has(unitID, core.CompPosition) {
+		return false
+	}
+	t := tc.(*core.Transport)
+	if len(t.Passengers) >= t.Capacity {
+		return false
+	}
+	if mov := w.Get(unitID, core.CompMovable); mov != nil {
+		m := mov.(*core.Movable)
+		m.Path = nil
+		m.PathIdx = 0
+	}
+	w.Detach(unitID, core.CompPosition)
+	t.Passengers = append(t.Passengers, unitID)
+	return true
+}
+
+// UnloadRadius is how many tiles out from the drop point UnloadAll looks
+// for room
+const UnloadRadius = 3
+
+// UnloadAll sets passengers down on the free tiles nearest (x, y) and
+// returns them. A passenger with nowhere to stand within UnloadRadius
+// stays aboard.
+func UnloadAll(w *core.World, tm *maplib.TileMap, transportID core.EntityID, x, y float64) []core.EntityID {
+	t, ok := core.GetComponent[*core.Transport](w, transportID)
+	if !ok {
+		return nil
+	}
+	var out, aboard []core.EntityID
+	for _, id := range t.Passengers {
+		tx, ty, ok := nearestFree(w, tm, id, int(x), int(y), UnloadRadius)
+		if !ok {
+			aboard = append(aboard, id)
+			continue
+		}
+		w.Attach(id, &core.Position{X: float64(tx) + 0.5, Y: float64(ty) + 0.5})
+		out = append(out, id)
+	}
+	t.Passengers = aboard
+	return out
+}
@@ -0,0 +1,93 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+func TestUnloadAllFindsFreeTiles(t *testing.T) {
+	tests := []struct {
+		name       string
+		passengers int
+		setup      func(w *core.World, tm *maplib.TileMap)
+		x, y       float64
+		wantOut    int
+	}{
+		{"open ground", 5, func(*core.World, *maplib.TileMap) {}, 8, 8, 5},
+		{"drop on water", 3, func(_ *core.World, tm *maplib.TileMap) {
+			tm.SetTerrain(6, 6, 9, 9, maplib.TerrainWater)
+		}, 8, 8, 3},
+		{"drop at the map corner", 4, func(*core.World, *maplib.TileMap) {}, 0, 0, 4},
+		{"no room at all", 2, func(_ *core.World, tm *maplib.TileMap) {
+			tm.SetTerrain(0, 0, 15, 15, maplib.TerrainWater)
+		}, 8, 8, 0},
+		{"room for some", 3, func(_ *core.World, tm *maplib.TileMap) {
+			tm.SetTerrain(0, 0, 15, 15, maplib.TerrainWater)
+			tm.SetTerrain(8, 8, 9, 8, maplib.TerrainGrass)
+		}, 8, 8, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			tc.setup(w, tm)
+			carrier := w.Spawn()
+			w.Attach(carrier, &core.Transport{Capacity: tc.passengers})
+			for i := 0; i < tc.passengers; i++ {
+				id := spawnGroundUnit(w, 1, 1, core.MoveInfantry)
+				if !LoadUnit(w, carrier, id) {
+					t.Fatal("LoadUnit failed")
+				}
+			}
+
+			out := UnloadAll(w, tm, carrier, tc.x, tc.y)
+			if len(out) != tc.wantOut {
+				t.Fatalf("unloaded %d, want %d", len(out), tc.wantOut)
+			}
+			tr, _ := core.GetComponent[*core.Transport](w, carrier)
+			if len(tr.Passengers) != tc.passengers-tc.wantOut {
+				t.Errorf("%d still aboard, want %d", len(tr.Passengers), tc.passengers-tc.wantOut)
+			}
+			used := map[[2]int]bool{}
+			for _, id := range out {
+				pos, _ := core.GetComponent[*core.Position](w, id)
+				tx, ty := int(pos.X), int(pos.Y)
+				if !tm.IsPassable(tx, ty, maplib.PassInfantry) {
+					t.Errorf("unit set down on impassable tile (%d, %d)", tx, ty)
+				}
+				if used[[2]int{tx, ty}] {
+					t.Errorf("two units set down on (%d, %d)", tx, ty)
+				}
+				used[[2]int{tx, ty}] = true
+			}
+			for _, id := range tr.Passengers {
+				if w.Has(id, core.CompPosition) {
+					t.Error("a unit left aboard has a position")
+				}
+			}
+		})
+	}
+}
+
+func TestDeliveryTakesStrandedUnitsHome(t *testing.T) {
+	w := core.NewWorld(20)
+	tm := maplib.NewTileMap("t", 16, 16)
+	tm.SetTerrain(0, 0, 15, 15, maplib.TerrainWater)
+	tm.SetTerrain(8, 8, 8, 8, maplib.TerrainGrass)
+	carrier := SendDelivery(w, tm, NewTechTree(), 0, "Allied", []string{"gi", "gi"}, 8.5, 8.5)
+	tr, _ := core.GetComponent[*core.Transport](w, carrier)
+	stranded := tr.Passengers[1]
+
+	w.AddSystem(&DeliverySystem{TileMap: tm})
+	for i := 0; i < 400 && w.Alive(carrier); i++ {
+		w.Tick(0.05)
+	}
+	if w.Alive(carrier) {
+		t.Fatal("carrier never left")
+	}
+	if w.Alive(stranded) {
+		t.Error("the unit with no room to land was left behind off the map")
+	}
+}
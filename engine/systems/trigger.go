@@ -0,0 +1,168 @@
+package systems
+
+import (
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// TriggerSystem evaluates mission triggers loaded from the map file
+type TriggerSystem struct {
+	Triggers []maplib.TriggerDef
+	TechTree *TechTree
+	Players  *core.PlayerManager
+	EventBus *core.EventBus
+	Fog      *FogSystem      // optional: needed for reveal_area
+	TileMap  *maplib.TileMap // optional: needed for airdrop and to keep spawns off blocked tiles
+
+	elapsed float64
+	state   []triggerState
+}
+
+type triggerState struct {
+	fired     bool
+	armed     bool    // condition was false since last firing (for Repeat)
+	seenBldg  bool    // building_destroyed: target existed at some point
+	nextTimer float64 // timer: elapsed time of next firing
+}
+
+// NewTriggerSystem creates a trigger system for the given definitions
+func NewTriggerSystem(defs []maplib.TriggerDef, tt *TechTree, pm *core.PlayerManager, bus *core.EventBus, fog *FogSystem) *TriggerSystem {
+	s := &TriggerSystem{
+		Triggers: defs,
+		TechTree: tt,
+		Players:  pm,
+		EventBus: bus,
+		Fog:      fog,
+		state:    make([]triggerState, len(defs)),
+	}
+	for i, d := range defs {
+		s.state[i].armed = true
+		s.state[i].nextTimer = d.Seconds
+	}
+	return s
+}
+
+func (s *TriggerSystem) Priority() int { return 90 }
+
+func (s *TriggerSystem) Update(w *core.World, dt float64) {
+	s.elapsed += dt
+	for i := range s.Triggers {
+		def := &s.Triggers[i]
+		st := &s.state[i]
+		if st.fired && !def.Repeat {
+			continue
+		}
+
+		met := s.conditionMet(w, def, st)
+		if !met {
+			st.armed = true
+			continue
+		}
+		if !st.armed {
+			continue
+		}
+
+		st.fired = true
+		st.armed = false
+		if def.Condition == maplib.CondTimer {
+			st.nextTimer += def.Seconds
+			st.armed = true // timers re-arm by time, not by condition
+		}
+		for _, a := range def.Actions {
+			s.runAction(w, a)
+		}
+		if s.EventBus != nil {
+			s.EventBus.Publish(core.Event{Type: core.EvtTriggerFired, Tick: w.TickCount, Payload: def.Name})
+		}
+	}
+}
+
+func (s *TriggerSystem) conditionMet(w *core.World, def *maplib.TriggerDef, st *triggerState) bool {
+	switch def.Condition {
+	case maplib.CondTimer:
+		return s.elapsed >= st.nextTimer
+	case maplib.CondEnterArea:
+		for _, id := range w.Query(core.CompPosition, core.CompMovable, core.CompOwner) {
+			own := w.Get(id, core.CompOwner).(*core.Owner)
+			pos := w.Get(id, core.CompPosition).(*core.Position)
+			if own.PlayerID == def.Player && def.Area.Contains(pos.X, pos.Y) {
+				return true
+			}
+		}
+	case maplib.CondBuildingDestroyed:
+		exists := false
+		for _, id := range w.Query(core.CompBuilding, core.CompOwner) {
+			if w.Get(id, core.CompOwner).(*core.Owner).PlayerID != def.Player {
+				continue
+			}
+			if def.Key != "" {
+				bn := w.Get(id, core.CompBuildingName)
+				if bn == nil || bn.(*core.BuildingName).Key != def.Key {
+					continue
+				}
+			}
+			exists = true
+			break
+		}
+		if exists {
+			st.seenBldg = true
+		}
+		return st.seenBldg && !exists
+	}
+	return false
+}
+
+func (s *TriggerSystem) runAction(w *core.World, a maplib.TriggerAction) {
+	switch a.Type {
+	case maplib.ActSpawnUnits:
+		faction := ""
+		if p := s.Players.GetPlayer(a.Player); p != nil {
+			faction = p.Faction
+		}
+		cx, cy := a.Area.Center()
+		for i, key := range a.Units {
+			udef, ok := s.TechTree.Units[key]
+			if !ok {
+				continue
+			}
+			// Spread reinforcements in a small column, each on the nearest
+			// tile it can stand on
+			x := cx + float64(i%3) - 1
+			y := cy + float64(i/3)
+			if _, ok := SpawnUnitNear(w, s.TileMap, key, udef, a.Player, faction, x, y); !ok {
+				continue
+			}
+			if s.EventBus != nil {
+				s.EventBus.Publish(core.Event{Type: core.EvtUnitCreated, Tick: w.TickCount})
+			}
+		}
+	case maplib.ActAirdrop:
+		if s.TileMap == nil {
+			return
+		}
+		faction := ""
+		if p := s.Players.GetPlayer(a.Player); p != nil {
+			faction = p.Faction
+		}
+		cx, cy := a.Area.Center()
+		SendDelivery(w, s.TileMap, s.TechTree, a.Player, faction, a.Units, cx, cy)
+	case maplib.ActRevealArea:
+		if s.Fog != nil {
+			s.Fog.RevealArea(a.Player, a.Area.X1, a.Area.Y1, a.Area.X2, a.Area.Y2, a.Seconds)
+		}
+	case maplib.ActMessage:
+		if s.EventBus != nil {
+			s.EventBus.Publish(core.Event{Type: core.EvtMissionMessage, Tick: w.TickCount, Payload: a.Text})
+		}
+	case maplib.ActVictory:
+		for _, p := range s.Players.Players {
+			if p.ID != a.Player && !s.Players.AreAllies(p.ID, a.Player) {
+				p.Defeated = true
+			}
+		}
+	case maplib.ActDefeat:
+		if p := s.Players.GetPlayer(a.Player); p != nil {
+			p.Defeated = true
+		}
+	}
+}
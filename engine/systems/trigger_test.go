@@ -0,0 +1,139 @@
+package systems
+
+import (
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/1siamBot/rts-engine/engine/maplib"
+)
+
+// countFired counts EvtTriggerFired on bus after each dispatch
+func countFired(bus *core.EventBus) *int {
+	n := new(int)
+	core.Subscribe(bus, core.EvtTriggerFired, func(string) { *n++ })
+	return n
+}
+
+func TestTimerTriggers(t *testing.T) {
+	tests := []struct {
+		name    string
+		seconds float64
+		repeat  bool
+		want    int // firings in 3 seconds
+	}{
+		{"one-shot", 1, false, 1},
+		{"one-shot at the start", 0, false, 1},
+		{"repeating", 1, true, 3},
+		{"repeating twice a second", 0.5, true, 6},
+		{"not due yet", 5, true, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			bus := core.NewEventBus()
+			fired := countFired(bus)
+			defs := []maplib.TriggerDef{{Name: "t", Condition: maplib.CondTimer, Seconds: tc.seconds, Repeat: tc.repeat}}
+			w.AddSystem(NewTriggerSystem(defs, nil, core.NewPlayerManager(), bus, nil))
+			for i := 0; i < 12; i++ {
+				w.Tick(0.25)
+				bus.Dispatch()
+			}
+			if *fired != tc.want {
+				t.Errorf("fired %d times, want %d", *fired, tc.want)
+			}
+		})
+	}
+}
+
+func TestEnterAreaTrigger(t *testing.T) {
+	tests := []struct {
+		name   string
+		owner  int
+		repeat bool
+		path   []float64 // unit x on successive ticks, area spans x 5-7
+		want   int
+	}{
+		{"own unit walks in", 0, false, []float64{2.5, 6.5, 6.5}, 1},
+		{"enemy unit walks in", 1, false, []float64{2.5, 6.5, 6.5}, 0},
+		{"unit stops at the edge", 0, false, []float64{2.5, 4.9, 8.0}, 0},
+		{"one-shot ignores re-entry", 0, false, []float64{6.5, 2.5, 6.5}, 1},
+		{"repeat fires on each entry", 0, true, []float64{6.5, 2.5, 6.5}, 2},
+		{"repeat waits for the unit to leave", 0, true, []float64{6.5, 6.5, 6.5}, 1},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			bus := core.NewEventBus()
+			fired := countFired(bus)
+			defs := []maplib.TriggerDef{{Name: "t", Condition: maplib.CondEnterArea, Player: 0,
+				Area: maplib.Area{X1: 5, Y1: 5, X2: 7, Y2: 7}, Repeat: tc.repeat}}
+			w.AddSystem(NewTriggerSystem(defs, nil, core.NewPlayerManager(), bus, nil))
+			id := spawnGroundUnit(w, tc.path[0], 6.5, core.MoveInfantry)
+			w.Attach(id, &core.Owner{PlayerID: tc.owner})
+			pos, _ := core.GetComponent[*core.Position](w, id)
+			for _, x := range tc.path {
+				pos.X = x
+				w.Tick(0.05)
+				bus.Dispatch()
+			}
+			if *fired != tc.want {
+				t.Errorf("fired %d times, want %d", *fired, tc.want)
+			}
+		})
+	}
+}
+
+func TestSpawnTriggerPlacement(t *testing.T) {
+	tests := []struct {
+		name   string
+		block  func(tm *maplib.TileMap)
+		want   int  // units spawned
+		inArea bool // all of them where asked
+	}{
+		{"open ground", func(*maplib.TileMap) {}, 3, true},
+		{"water on the drop point", func(tm *maplib.TileMap) { tm.SetTerrain(4, 5, 6, 5, maplib.TerrainWater) }, 3, false},
+		{"building on the drop point", func(tm *maplib.TileMap) { OccupyTiles(tm, 4, 5, 3, 1) }, 3, false},
+		{"no ground in reach", func(tm *maplib.TileMap) { tm.SetTerrain(0, 0, 15, 15, maplib.TerrainWater) }, 0, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := core.NewWorld(20)
+			tm := maplib.NewTileMap("t", 16, 16)
+			tc.block(tm)
+			pm := core.NewPlayerManager()
+			pm.AddPlayer(&core.Player{ID: 0})
+			defs := []maplib.TriggerDef{{Name: "t", Condition: maplib.CondTimer, Actions: []maplib.TriggerAction{{
+				Type: maplib.ActSpawnUnits, Player: 0, Units: []string{"grizzly", "grizzly", "grizzly"},
+				Area: maplib.Area{X1: 5, Y1: 5, X2: 5, Y2: 5},
+			}}}}
+			ts := NewTriggerSystem(defs, NewTechTree(), pm, nil, nil)
+			ts.TileMap = tm
+			w.AddSystem(ts)
+			w.Tick(0.05)
+
+			units := w.Query(core.CompMovable, core.CompPosition)
+			if len(units) != tc.want {
+				t.Fatalf("spawned %d units, want %d", len(units), tc.want)
+			}
+			seen := make(map[[2]int]bool)
+			inArea := true
+			for _, id := range units {
+				pos := w.Get(id, core.CompPosition).(*core.Position)
+				x, y := int(pos.X), int(pos.Y)
+				if !tm.IsPassable(x, y, maplib.PassVehicle) {
+					t.Errorf("unit %d on blocked tile (%d, %d)", id, x, y)
+				}
+				if seen[[2]int{x, y}] {
+					t.Errorf("two units on tile (%d, %d)", x, y)
+				}
+				seen[[2]int{x, y}] = true
+				if y != 5 || x < 4 || x > 6 {
+					inArea = false
+				}
+			}
+			if tc.want > 0 && inArea != tc.inArea {
+				t.Errorf("units on the drop tiles = %v, want %v", inArea, tc.inArea)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	floaterLife    = 1.0  // seconds a damage number stays up
+	killMarkerLife = 0.8  // seconds a kill marker stays up
+	floaterMerge   = 0.25 // hits on one target within this window add to one number
+	floaterRise    = 28.0 // pixels a number drifts up over its life
+	maxFloaters    = 48
+)
+
+var (
+	floaterEffective = color.NRGBA{255, 150, 40, 255}  // damage type beats the armor
+	floaterNormal    = color.NRGBA{235, 235, 235, 255} // neutral multiplier
+	floaterReduced   = color.NRGBA{130, 150, 180, 255} // armor soaked the hit
+	floaterKill      = color.NRGBA{255, 60, 40, 255}
+)
+
+// Floater is a damage number or kill marker anchored to a world position
+type Floater struct {
+	Target  core.EntityID
+	X, Y    float64 // world position
+	Amount  int
+	Mult    float64
+	Kill    bool
+	Timer   float64
+	MaxTime float64
+}
+
+// AddDamage records a hit. Hits on the same target within floaterMerge are
+// summed into one number so rapid-fire weapons don't spam.
+func (h *HUD) AddDamage(d core.DamageEvent) {
+	if d.Killed {
+		h.addFloater(Floater{Target: d.Target, X: d.X, Y: d.Y, Kill: true, MaxTime: killMarkerLife})
+	}
+	for i := range h.Floaters {
+		f := &h.Floaters[i]
+		if !f.Kill && f.Target == d.Target && f.Timer < floaterMerge {
+			f.Amount += d.Amount
+			f.Mult = d.Multiplier
+			f.X, f.Y = d.X, d.Y
+			return
+		}
+	}
+	h.addFloater(Floater{Target: d.Target, X: d.X, Y: d.Y, Amount: d.Amount, Mult: d.Multiplier, MaxTime: floaterLife})
+}
+
+// addFloater appends f, dropping the oldest entry once the list is full
+func (h *HUD) addFloater(f Floater) {
+	if len(h.Floaters) >= maxFloaters {
+		h.Floaters = h.Floaters[1:]
+	}
+	h.Floaters = append(h.Floaters, f)
+}
+
+func (h *HUD) updateFloaters(dt float64) {
+	alive := h.Floaters[:0]
+	for _, f := range h.Floaters {
+		f.Timer += dt
+		if f.Timer < f.MaxTime {
+			alive = append(alive, f)
+		}
+	}
+	h.Floaters = alive
+}
+
+// floaterColor picks the number color from the armor multiplier
+func floaterColor(mult float64) color.NRGBA {
+	switch {
+	case mult > 1.05:
+		return floaterEffective
+	case mult < 0.95:
+		return floaterReduced
+	}
+	return floaterNormal
+}
+
+// DrawFloaters draws damage numbers and kill markers; project maps a world
+// position to the screen
+func (h *HUD) DrawFloaters(screen *ebiten.Image, project func(x, y float64) (int, int)) {
+	for _, f := range h.Floaters {
+		t := f.Timer / f.MaxTime
+		alpha := uint8(255 * (1 - t*t))
+		sx, sy := project(f.X, f.Y)
+
+		if f.Kill {
+			r := float32(6 + 14*t)
+			clr := floaterKill
+			clr.A = alpha
+			vector.StrokeCircle(screen, float32(sx), float32(sy), r, 2, clr, true)
+			vector.StrokeLine(screen, float32(sx)-5, float32(sy)-5, float32(sx)+5, float32(sy)+5, 2, clr, true)
+			vector.StrokeLine(screen, float32(sx)-5, float32(sy)+5, float32(sx)+5, float32(sy)-5, 2, clr, true)
+			h.Font.DrawCentered(screen, "DESTROYED", sx, sy-int(r)-16, FontSmall, clr)
+			continue
+		}
+
+		clr := floaterColor(f.Mult)
+		clr.A = alpha
+		size := FontSmall
+		if f.Mult > 1.05 {
+			size = FontNormal
+		}
+		h.Font.DrawCentered(screen, fmt.Sprintf("-%d", f.Amount), sx, sy-18-int(floaterRise*t), size, clr)
+	}
+}
@@ -0,0 +1,124 @@
+package ui
+
+import (
+	"image/color"
+	"log"
+	"sync"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+)
+
+// Font sizes used by the HUD and menus, in pixels
+const (
+	FontSmall  = 11.0
+	FontNormal = 13.0
+	FontLarge  = 20.0
+)
+
+// debugCharW is the glyph advance of ebitenutil.DebugPrint's bitmap font
+const debugCharW = 6
+
+// Font draws TrueType text at a few fixed sizes. A Font without faces (load
+// failed) falls back to ebitenutil.DebugPrint.
+type Font struct {
+	faces map[float64]font.Face
+}
+
+var (
+	defaultFont     *Font
+	defaultFontOnce sync.Once
+)
+
+// DefaultFont returns the shared Go Regular font, loaded on first use
+func DefaultFont() *Font {
+	defaultFontOnce.Do(func() {
+		f, err := LoadFont(goregular.TTF, FontSmall, FontNormal, FontLarge)
+		if err != nil {
+			log.Printf("Font not loaded (%v), using debug text", err)
+			f = &Font{}
+		}
+		defaultFont = f
+	})
+	return defaultFont
+}
+
+// LoadFont parses TrueType data and creates a face for each size
+func LoadFont(ttf []byte, sizes ...float64) (*Font, error) {
+	tt, err := opentype.Parse(ttf)
+	if err != nil {
+		return nil, err
+	}
+	f := &Font{faces: make(map[float64]font.Face, len(sizes))}
+	for _, size := range sizes {
+		face, err := opentype.NewFace(tt, &opentype.FaceOptions{
+			Size:    size,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, err
+		}
+		f.faces[size] = face
+	}
+	return f, nil
+}
+
+// face returns the face for size, or the closest loaded size
+func (f *Font) face(size float64) font.Face {
+	if f == nil || len(f.faces) == 0 {
+		return nil
+	}
+	if face, ok := f.faces[size]; ok {
+		return face
+	}
+	var best font.Face
+	bestD := 0.0
+	for s, face := range f.faces {
+		d := s - size
+		if d < 0 {
+			d = -d
+		}
+		if best == nil || d < bestD {
+			best, bestD = face, d
+		}
+	}
+	return best
+}
+
+// DrawText draws s with its top-left corner at (x, y), like DebugPrintAt
+func (f *Font) DrawText(screen *ebiten.Image, s string, x, y int, size float64, clr color.Color) {
+	face := f.face(size)
+	if face == nil {
+		ebitenutil.DebugPrintAt(screen, s, x, y)
+		return
+	}
+	text.Draw(screen, s, face, x, y+face.Metrics().Ascent.Ceil(), clr)
+}
+
+// DrawCentered draws s horizontally centred on cx
+func (f *Font) DrawCentered(screen *ebiten.Image, s string, cx, y int, size float64, clr color.Color) {
+	f.DrawText(screen, s, cx-f.Measure(s, size)/2, y, size, clr)
+}
+
+// Measure returns the advance width of s in pixels
+func (f *Font) Measure(s string, size float64) int {
+	face := f.face(size)
+	if face == nil {
+		return len(s) * debugCharW
+	}
+	return font.MeasureString(face, s).Ceil()
+}
+
+// LineHeight returns the distance between baselines for size
+func (f *Font) LineHeight(size float64) int {
+	face := f.face(size)
+	if face == nil {
+		return 16
+	}
+	return face.Metrics().Height.Ceil()
+}
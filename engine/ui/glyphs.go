@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Vector glyphs for HUD symbols the fonts can't draw. Each is centred on
+// (cx, cy) and fits in a size x size box; they stand in for missing icon
+// sprites.
+
+// fillPolygon fills the closed polygon through pts (x, y pairs)
+func fillPolygon(screen *ebiten.Image, clr color.Color, pts ...float32) {
+	var path vector.Path
+	path.MoveTo(pts[0], pts[1])
+	for i := 2; i+1 < len(pts); i += 2 {
+		path.LineTo(pts[i], pts[i+1])
+	}
+	path.Close()
+	op := &vector.DrawPathOptions{AntiAlias: true}
+	op.ColorScale.ScaleWithColor(clr)
+	vector.FillPath(screen, &path, nil, op)
+}
+
+// drawArrowGlyph draws a solid triangle pointing up or down
+func drawArrowGlyph(screen *ebiten.Image, cx, cy, size float32, up bool, clr color.Color) {
+	hw, hh := size/2, size/3
+	if up {
+		fillPolygon(screen, clr, cx, cy-hh, cx+hw, cy+hh, cx-hw, cy+hh)
+	} else {
+		fillPolygon(screen, clr, cx, cy+hh, cx+hw, cy-hh, cx-hw, cy-hh)
+	}
+}
+
+// drawBoltGlyph draws a lightning bolt
+func drawBoltGlyph(screen *ebiten.Image, cx, cy, size float32, clr color.Color) {
+	s := size / 2
+	fillPolygon(screen, clr,
+		cx+s*0.2, cy-s,
+		cx-s*0.6, cy+s*0.15,
+		cx-s*0.05, cy+s*0.15,
+		cx-s*0.2, cy+s,
+		cx+s*0.6, cy-s*0.15,
+		cx+s*0.05, cy-s*0.15,
+	)
+}
+
+// drawLockGlyph draws a padlock
+func drawLockGlyph(screen *ebiten.Image, cx, cy, size float32, clr color.Color) {
+	s := size / 2
+	vector.StrokeCircle(screen, cx, cy-s*0.2, s*0.45, s*0.2, clr, true)
+	vector.DrawFilledRect(screen, cx-s*0.7, cy-s*0.1, s*1.4, s*1.1, clr, true)
+	vector.DrawFilledRect(screen, cx-s*0.1, cy+s*0.25, s*0.2, s*0.4, color.RGBA{0, 0, 0, 200}, false)
+}
+
+// drawWrenchGlyph draws a diagonal wrench
+func drawWrenchGlyph(screen *ebiten.Image, cx, cy, size float32, clr color.Color) {
+	s := size / 2
+	vector.StrokeLine(screen, cx-s*0.6, cy+s*0.6, cx+s*0.3, cy-s*0.3, s*0.35, clr, true)
+	vector.StrokeCircle(screen, cx+s*0.45, cy-s*0.45, s*0.35, s*0.25, clr, true)
+	vector.DrawFilledCircle(screen, cx-s*0.65, cy+s*0.65, s*0.2, clr, true)
+}
+
+// drawCoinGlyph draws a gold coin with a struck "$" bar, used for selling
+func drawCoinGlyph(screen *ebiten.Image, cx, cy, size float32, clr color.Color) {
+	s := size / 2
+	vector.DrawFilledCircle(screen, cx, cy, s*0.9, clr, true)
+	vector.StrokeCircle(screen, cx, cy, s*0.6, 1, color.RGBA{120, 90, 10, 255}, true)
+	vector.StrokeLine(screen, cx, cy-s*0.5, cx, cy+s*0.5, 1.5, color.RGBA{120, 90, 10, 255}, true)
+}
+
+// drawFlagGlyph draws a flag on a pole, used for waypoints
+func drawFlagGlyph(screen *ebiten.Image, cx, cy, size float32, clr color.Color) {
+	s := size / 2
+	vector.StrokeLine(screen, cx-s*0.5, cy-s, cx-s*0.5, cy+s, 1.5, clr, true)
+	fillPolygon(screen, clr, cx-s*0.5, cy-s, cx+s*0.7, cy-s*0.55, cx-s*0.5, cy-s*0.1)
+}
+
+// drawIconOrGlyph draws the sprite icon when loaded, otherwise the vector glyph
+func (h *HUD) drawIconOrGlyph(screen *ebiten.Image, icon *ebiten.Image, cx, cy, size int,
+	glyph func(*ebiten.Image, float32, float32, float32, color.Color), clr color.Color) {
+	if icon != nil {
+		h.Sprites.DrawIcon(screen, icon, cx, cy, size)
+		return
+	}
+	glyph(screen, float32(cx), float32(cy), float32(size), clr)
+}
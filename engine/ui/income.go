@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"math"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+// IncomeWindow is how many seconds of harvesting the income rate averages over
+const IncomeWindow = 60.0
+
+// incomeSample is one delivery of ore, stamped with the HUD clock
+type incomeSample struct {
+	at     float64
+	amount int
+}
+
+// RecordHarvest adds a delivery to the local player's income window
+func (h *HUD) RecordHarvest(ev core.HarvestEvent) {
+	if ev.PlayerID != h.LocalPlayer || ev.Amount <= 0 {
+		return
+	}
+	h.income = append(h.income, incomeSample{at: h.tick, amount: ev.Amount})
+}
+
+// IncomePerMinute returns the credits harvested over the last IncomeWindow
+// seconds, scaled to a minute. Early on the rate covers the time played.
+func (h *HUD) IncomePerMinute() int {
+	cutoff := h.tick - IncomeWindow
+	n := 0
+	for n < len(h.income) && h.income[n].at < cutoff {
+		n++
+	}
+	h.income = h.income[n:]
+	total := 0
+	for _, s := range h.income {
+		total += s.amount
+	}
+	span := math.Min(h.tick, IncomeWindow)
+	if span <= 0 {
+		return 0
+	}
+	return int(float64(total) * 60 / span)
+}
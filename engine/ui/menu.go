@@ -5,8 +5,9 @@ import (
 	"image/color"
 	"math"
 
+	"github.com/1siamBot/rts-engine/engine/input"
+	"github.com/1siamBot/rts-engine/engine/systems"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/inpututil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
@@ -32,14 +33,37 @@ type SkirmishSettings struct {
 	MapSize        int // 0=Small, 1=Medium, 2=Large
 }
 
+// DefaultSkirmishSettings returns the skirmish setup shown on first launch
+func DefaultSkirmishSettings() SkirmishSettings {
+	return SkirmishSettings{
+		MapIndex:        0,
+		Faction:         0,
+		AIDifficulty:    1,
+		StartingCredits: 1, // 10000
+		MapSize:         1, // Medium
+	}
+}
+
+// FactionName returns the chosen faction's name
+func (s SkirmishSettings) FactionName() string {
+	return factionNames[s.Faction]
+}
+
+// Credits returns the chosen starting credits
+func (s SkirmishSettings) Credits() int {
+	return creditOptions[s.StartingCredits]
+}
+
 // GameOverStats holds end-game statistics
 type GameOverStats struct {
-	Victory           bool
-	UnitsBuilt        int
-	UnitsLost         int
-	BuildingsBuilt    int
-	BuildingsDestroyed int
-	CreditsEarned     int
+	Victory bool
+	Players []PlayerResult
+}
+
+// PlayerResult is one column of the end-game stats table
+type PlayerResult struct {
+	Name  string
+	Stats systems.PlayerStats
 }
 
 // MenuButton represents a clickable menu button
@@ -58,6 +82,7 @@ type MenuSystem struct {
 	ScreenH     int
 	Tick        float64
 	Sprites     *UISprites
+	Font        *Font
 
 	// Skirmish
 	Skirmish SkirmishSettings
@@ -65,6 +90,7 @@ type MenuSystem struct {
 	// Settings
 	Settings     GameSettings
 	TempSettings GameSettings // edited but not applied
+	Bindings     input.KeyBindings // listed on the Controls tab
 
 	// Game Over
 	GameOverData GameOverStats
@@ -80,7 +106,10 @@ type MenuSystem struct {
 	OnRestartGame func()
 	OnQuitToMenu  func()
 	OnExitGame    func()
+	OnOpenEditor  func() // nil disables the MAP EDITOR button
 	OnApplySettings func(GameSettings)
+	OnGameOver    func(victory bool)
+	MatchStats    func() []PlayerResult // optional; fills the game over table
 }
 
 // GameSettings holds configurable settings
@@ -90,8 +119,38 @@ type GameSettings struct {
 	MusicVolume   float64 // 0-1
 	SFXVolume     float64 // 0-1
 	ScrollSpeed   float64 // 1-10
-	ShowHealthBars bool
+	HealthBars    HealthBarMode
 	ShowMinimap   bool
+	EdgeScroll    bool
+	EdgeSize      int     // edge-scroll trigger zone in pixels, 2-50
+	EdgeSpeed     float64 // 1-10
+}
+
+// controlLabels lists the rebindable actions shown on the Controls tab
+var controlLabels = []struct{ Action, Label string }{
+	{input.ActionScrollUp, "Scroll Up"},
+	{input.ActionScrollDown, "Scroll Down"},
+	{input.ActionScrollLeft, "Scroll Left"},
+	{input.ActionScrollRight, "Scroll Right"},
+	{input.ActionMenu, "Menu / Cancel"},
+	{input.ActionToggleGrid, "Toggle Grid"},
+	{input.ActionToggleMinimap, "Toggle Minimap"},
+	{input.ActionPerfOverlay, "Perf Overlay"},
+	{input.ActionFullscreen, "Fullscreen"},
+	{input.ActionDeploy, "Deploy MCV"},
+	{input.ActionRelocate, "Relocate Base"},
+	{input.ActionSell, "Sell Building"},
+	{input.ActionPowerToggle, "Power On/Off"},
+	{input.ActionQueueInfantry, "Train Infantry"},
+	{input.ActionCycleSubGroup, "Cycle Subgroup"},
+	{input.ActionStop, "Stop"},
+	{input.ActionScatter, "Scatter"},
+	{input.ActionFlare, "Flare"},
+	{input.ActionChrono, "Chrono Jump"},
+	{input.ActionWarMode, "War Mode"},
+	{input.ActionAttackPoint, "Attack Point"},
+	{input.ActionAddModifier, "Add to Select"},
+	{input.ActionBoxAll, "Box All"},
 }
 
 var (
@@ -122,21 +181,9 @@ func NewMenuSystem(screenW, screenH int, sprites *UISprites) *MenuSystem {
 		ScreenW: screenW,
 		ScreenH: screenH,
 		Sprites: sprites,
-		Skirmish: SkirmishSettings{
-			MapIndex:        0,
-			Faction:         0,
-			AIDifficulty:    1,
-			StartingCredits: 1, // 10000
-			MapSize:         1, // Medium
-		},
-		Settings: GameSettings{
-			VSync:          true,
-			MusicVolume:    0.7,
-			SFXVolume:      0.8,
-			ScrollSpeed:    5,
-			ShowHealthBars: true,
-			ShowMinimap:    true,
-		},
+		Font:    DefaultFont(),
+		Skirmish: DefaultSkirmishSettings(),
+		Settings: DefaultGameSettings(),
 		hoverIdx: -1,
 	}
 }
@@ -186,16 +233,18 @@ func (m *MenuSystem) updateMainMenu(mx, my int) {
 	}
 	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) && m.hoverIdx >= 0 {
 		switch m.hoverIdx {
-		case 0: // SKIRMISH
+		case 0: // NEW SKIRMISH
 			m.State = StateSkirmishSetup
-		case 1: // MULTIPLAYER (placeholder)
-			// no-op
-		case 2: // SETTINGS
+		case 1: // MAP EDITOR
+			if m.OnOpenEditor != nil {
+				m.OnOpenEditor()
+			}
+		case 2: // OPTIONS
 			m.PrevState = StateMainMenu
 			m.TempSettings = m.Settings
 			m.settingsTab = 0
 			m.State = StateSettings
-		case 3: // EXIT
+		case 3: // QUIT
 			if m.OnExitGame != nil {
 				m.OnExitGame()
 			}
@@ -207,8 +256,8 @@ func (m *MenuSystem) mainMenuButtons() []MenuButton {
 	cx := m.ScreenW / 2
 	startY := m.ScreenH/2 - 20
 	bw, bh, gap := 260, 40, 8
-	names := []string{"SKIRMISH", "MULTIPLAYER", "SETTINGS", "EXIT"}
-	disabled := []bool{false, true, false, false}
+	names := []string{"NEW SKIRMISH", "MAP EDITOR", "OPTIONS", "QUIT"}
+	disabled := []bool{false, m.OnOpenEditor == nil, false, false}
 	buttons := make([]MenuButton, len(names))
 	for i, name := range names {
 		buttons[i] = MenuButton{
@@ -233,7 +282,7 @@ func (m *MenuSystem) drawMainMenu(screen *ebiten.Image) {
 	}
 
 	// Version
-	ebitenutil.DebugPrintAt(screen, "RTS Engine v0.5.0", 10, m.ScreenH-20)
+	m.Font.DrawText(screen, "RTS Engine v0.5.0", 10, m.ScreenH-20, FontSmall, menuTextDim)
 }
 
 func (m *MenuSystem) drawTitle(screen *ebiten.Image) {
@@ -242,29 +291,23 @@ func (m *MenuSystem) drawTitle(screen *ebiten.Image) {
 	subtitle := "RTS ENGINE"
 
 	// Title with glow effect
-	titleW := len(title) * 12
+	titleW := m.Font.Measure(title, FontLarge)
 	// Glow behind
 	pulse := 0.7 + 0.3*math.Sin(m.Tick*2)
 	glowAlpha := uint8(40 * pulse)
 	drawRoundedRect(screen, float32(cx-titleW/2-20), float32(60), float32(titleW+40), 70, 8,
 		color.RGBA{0, 100, 180, glowAlpha})
 
-	// Title text (large - use debug print scaled)
-	// Since we only have debug text, draw it big by repeating with offsets for "bold"
-	ty := 75
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
-			ebitenutil.DebugPrintAt(screen, title, cx-len(title)*3+dx, ty+dy)
-		}
-	}
+	ty := 70
+	m.Font.DrawCentered(screen, title, cx, ty, FontLarge, menuText)
 	// Cyan accent line under title
-	lineY := float32(ty + 20)
+	lineY := float32(ty + 26)
 	vector.DrawFilledRect(screen, float32(cx-120), lineY, 240, 2, menuAccent, false)
 	// Glow
 	vector.DrawFilledRect(screen, float32(cx-120), lineY-1, 240, 4, color.RGBA{0, 180, 255, 40}, false)
 
 	// Subtitle
-	ebitenutil.DebugPrintAt(screen, subtitle, cx-len(subtitle)*3, ty+30)
+	m.Font.DrawCentered(screen, subtitle, cx, ty+34, FontNormal, menuAccent)
 }
 
 func (m *MenuSystem) drawAnimatedBG(screen *ebiten.Image) {
@@ -373,7 +416,7 @@ func (m *MenuSystem) drawSkirmishSetup(screen *ebiten.Image) {
 	cx := m.ScreenW / 2
 
 	// Title
-	ebitenutil.DebugPrintAt(screen, "SKIRMISH SETUP", cx-len("SKIRMISH SETUP")*3, 30)
+	m.Font.DrawCentered(screen, "SKIRMISH SETUP", cx, 24, FontLarge, menuText)
 	vector.DrawFilledRect(screen, float32(cx-80), 48, 160, 2, menuAccent, false)
 
 	// Panel background
@@ -406,7 +449,7 @@ func (m *MenuSystem) drawSkirmishSetup(screen *ebiten.Image) {
 }
 
 func (m *MenuSystem) drawOption(screen *ebiten.Image, x, y int, label, value string) {
-	ebitenutil.DebugPrintAt(screen, label, x+40, y)
+	m.Font.DrawText(screen, label, x+40, y, FontNormal, menuText)
 
 	// Value display with arrows
 	valX := x + 40
@@ -420,8 +463,7 @@ func (m *MenuSystem) drawOption(screen *ebiten.Image, x, y int, label, value str
 	m.drawArrowButton(screen, x+370, y+20, true)
 
 	// Centered value text
-	textX := valX + valW/2 - len(value)*3
-	ebitenutil.DebugPrintAt(screen, value, textX, y+22)
+	m.Font.DrawCentered(screen, value, valX+valW/2, y+22, FontNormal, menuText)
 }
 
 func (m *MenuSystem) drawArrowButton(screen *ebiten.Image, x, y int, right bool) {
@@ -430,7 +472,7 @@ func (m *MenuSystem) drawArrowButton(screen *ebiten.Image, x, y int, right bool)
 	if right {
 		arrow = ">"
 	}
-	ebitenutil.DebugPrintAt(screen, arrow, x+12, y+6)
+	m.Font.DrawCentered(screen, arrow, x+15, y+5, FontNormal, menuText)
 }
 
 // ==================== PAUSE MENU ====================
@@ -459,7 +501,7 @@ func (m *MenuSystem) updatePauseMenu(mx, my int) {
 			if m.OnResumeGame != nil {
 				m.OnResumeGame()
 			}
-		case 1: // SETTINGS
+		case 1: // OPTIONS
 			m.PrevState = StatePaused
 			m.TempSettings = m.Settings
 			m.settingsTab = 0
@@ -469,8 +511,7 @@ func (m *MenuSystem) updatePauseMenu(mx, my int) {
 				m.OnRestartGame()
 			}
 		case 3: // SURRENDER
-			m.GameOverData = GameOverStats{Victory: false}
-			m.State = StateGameOver
+			m.ShowGameOver(false)
 		case 4: // QUIT TO MENU
 			m.State = StateMainMenu
 			if m.OnQuitToMenu != nil {
@@ -484,7 +525,7 @@ func (m *MenuSystem) pauseMenuButtons() []MenuButton {
 	cx := m.ScreenW / 2
 	startY := m.ScreenH/2 - 80
 	bw, bh, gap := 220, 36, 8
-	names := []string{"RESUME", "SETTINGS", "RESTART", "SURRENDER", "QUIT TO MENU"}
+	names := []string{"RESUME", "OPTIONS", "RESTART", "SURRENDER", "QUIT TO MENU"}
 	buttons := make([]MenuButton, len(names))
 	for i, name := range names {
 		buttons[i] = MenuButton{
@@ -510,7 +551,7 @@ func (m *MenuSystem) drawPauseMenu(screen *ebiten.Image) {
 
 	// Title
 	title := "PAUSED"
-	ebitenutil.DebugPrintAt(screen, title, cx-len(title)*3, int(py)+20)
+	m.Font.DrawCentered(screen, title, cx, int(py)+14, FontLarge, menuText)
 	vector.DrawFilledRect(screen, px+20, py+38, float32(panelW-40), 2, menuAccent, false)
 
 	// Buttons
@@ -576,14 +617,30 @@ func (m *MenuSystem) updateSettings(mx, my int) {
 			if m.TempSettings.ScrollSpeed < 1 { m.TempSettings.ScrollSpeed = 1 }
 			if m.TempSettings.ScrollSpeed > 10 { m.TempSettings.ScrollSpeed = 10 }
 		}
-		y += 50
+		y += 40
 		if m.clickInRect(mx, my, panelX+250, y, 100, 24) {
-			m.TempSettings.ShowHealthBars = !m.TempSettings.ShowHealthBars
+			m.TempSettings.HealthBars = (m.TempSettings.HealthBars + 1) % healthBarModeCount
 		}
-		y += 50
+		y += 40
 		if m.clickInRect(mx, my, panelX+250, y, 100, 24) {
 			m.TempSettings.ShowMinimap = !m.TempSettings.ShowMinimap
 		}
+		y += 40
+		if m.clickInRect(mx, my, panelX+250, y, 100, 24) {
+			m.TempSettings.EdgeScroll = !m.TempSettings.EdgeScroll
+		}
+		y += 40
+		// Edge size slider (2-50 px)
+		if mx >= panelX+150 && mx < panelX+380 && my >= y && my < y+24 {
+			m.TempSettings.EdgeSize = 2 + int(float64(mx-panelX-150)/230.0*48)
+		}
+		y += 40
+		// Edge speed slider
+		if mx >= panelX+150 && mx < panelX+380 && my >= y && my < y+24 {
+			m.TempSettings.EdgeSpeed = float64(mx-panelX-150) / 230.0 * 10
+			if m.TempSettings.EdgeSpeed < 1 { m.TempSettings.EdgeSpeed = 1 }
+			if m.TempSettings.EdgeSpeed > 10 { m.TempSettings.EdgeSpeed = 10 }
+		}
 	}
 
 	// APPLY / BACK buttons
@@ -620,7 +677,7 @@ func (m *MenuSystem) drawSettings(screen *ebiten.Image) {
 	drawRoundedRectStroke(screen, px, py, float32(panelW), float32(panelH), 10, menuBorder)
 
 	// Title
-	ebitenutil.DebugPrintAt(screen, "SETTINGS", cx-len("SETTINGS")*3, 60)
+	m.Font.DrawCentered(screen, "OPTIONS", cx, 54, FontLarge, menuText)
 	vector.DrawFilledRect(screen, px+20, 78, float32(panelW-40), 2, menuAccent, false)
 
 	// Tabs
@@ -634,7 +691,7 @@ func (m *MenuSystem) drawSettings(screen *ebiten.Image) {
 			clr = menuBtnAct
 		}
 		drawRoundedRect(screen, float32(tx), 90, float32(tabW-4), 28, 4, clr)
-		ebitenutil.DebugPrintAt(screen, name, tx+(tabW-4)/2-len(name)*3, 97)
+		m.Font.DrawCentered(screen, name, tx+(tabW-4)/2, 96, FontNormal, menuText)
 	}
 
 	panelX := cx - 200
@@ -642,44 +699,57 @@ func (m *MenuSystem) drawSettings(screen *ebiten.Image) {
 
 	switch m.settingsTab {
 	case 0: // Graphics
-		ebitenutil.DebugPrintAt(screen, "Fullscreen", panelX+20, y+4)
+		m.Font.DrawText(screen, "Fullscreen", panelX+20, y+4, FontNormal, menuText)
 		m.drawToggle(screen, panelX+250, y, m.TempSettings.Fullscreen)
 		y += 50
-		ebitenutil.DebugPrintAt(screen, "VSync", panelX+20, y+4)
+		m.Font.DrawText(screen, "VSync", panelX+20, y+4, FontNormal, menuText)
 		m.drawToggle(screen, panelX+250, y, m.TempSettings.VSync)
 	case 1: // Audio
-		ebitenutil.DebugPrintAt(screen, "Music Volume", panelX+20, y+4)
+		m.Font.DrawText(screen, "Music Volume", panelX+20, y+4, FontNormal, menuText)
 		m.drawSlider(screen, panelX+150, y, 230, m.TempSettings.MusicVolume)
 		y += 50
-		ebitenutil.DebugPrintAt(screen, "SFX Volume", panelX+20, y+4)
+		m.Font.DrawText(screen, "SFX Volume", panelX+20, y+4, FontNormal, menuText)
 		m.drawSlider(screen, panelX+150, y, 230, m.TempSettings.SFXVolume)
 	case 2: // Game
-		ebitenutil.DebugPrintAt(screen, "Scroll Speed", panelX+20, y+4)
+		m.Font.DrawText(screen, "Scroll Speed", panelX+20, y+4, FontNormal, menuText)
 		m.drawSlider(screen, panelX+150, y, 230, m.TempSettings.ScrollSpeed/10)
-		y += 50
-		ebitenutil.DebugPrintAt(screen, "Health Bars", panelX+20, y+4)
-		m.drawToggle(screen, panelX+250, y, m.TempSettings.ShowHealthBars)
-		y += 50
-		ebitenutil.DebugPrintAt(screen, "Show Minimap", panelX+20, y+4)
+		y += 40
+		m.Font.DrawText(screen, "Health Bars (hold Alt: all)", panelX+20, y+4, FontNormal, menuText)
+		m.drawChoice(screen, panelX+250, y, m.TempSettings.HealthBars.String())
+		y += 40
+		m.Font.DrawText(screen, "Show Minimap", panelX+20, y+4, FontNormal, menuText)
 		m.drawToggle(screen, panelX+250, y, m.TempSettings.ShowMinimap)
+		y += 40
+		m.Font.DrawText(screen, "Edge Scroll", panelX+20, y+4, FontNormal, menuText)
+		m.drawToggle(screen, panelX+250, y, m.TempSettings.EdgeScroll)
+		y += 40
+		m.Font.DrawText(screen, fmt.Sprintf("Edge Size %dpx", m.TempSettings.EdgeSize), panelX+20, y+4, FontNormal, menuText)
+		m.drawSlider(screen, panelX+150, y, 230, float64(m.TempSettings.EdgeSize-2)/48)
+		y += 40
+		m.Font.DrawText(screen, "Edge Speed", panelX+20, y+4, FontNormal, menuText)
+		m.drawSlider(screen, panelX+150, y, 230, m.TempSettings.EdgeSpeed/10)
 	case 3: // Controls
-		keys := []string{
-			"W/A/S/D  — Camera Pan",
-			"Mouse Wheel — Zoom",
-			"Left Click — Select / Place",
-			"Right Click — Move / Cancel",
-			"ESC — Menu / Cancel",
-			"G — Toggle Grid",
-			"M — Toggle Minimap",
-			"H — Deploy MCV",
-			"DEL — Sell Building",
-			"Q — Quick Train Infantry",
-			"Ctrl+0-9 — Set Group",
-			"0-9 — Recall Group",
+		keys := [][2]string{
+			{"Left Click", "Select / Place"},
+			{"Right Click", "Move / Cancel"},
+			{"Mouse Wheel", "Zoom"},
+		}
+		for _, c := range controlLabels {
+			keys = append(keys, [2]string{c.Label, m.Bindings.KeyNames(c.Action)})
 		}
+		keys = append(keys,
+			[2]string{"Set Group", m.Bindings.KeyNames(input.ActionGroupModifier) + "+0-9"},
+			[2]string{"Recall Group", "0-9"},
+			[2]string{"Build Slot", m.Bindings.KeyNames(input.ActionBuildModifier) + "+1-0"},
+		)
+		// Two columns of label / key; rebinding is done in the key binding file
+		rows := (len(keys) + 1) / 2
 		for i, k := range keys {
-			ebitenutil.DebugPrintAt(screen, k, panelX+30, y+i*18)
+			kx, ky := panelX+10+(i/rows)*205, y-10+(i%rows)*18
+			m.Font.DrawText(screen, k[0], kx, ky, FontNormal, menuTextDim)
+			m.Font.DrawText(screen, k[1], kx+95, ky, FontNormal, menuText)
 		}
+		m.Font.DrawText(screen, "Edit keybindings.json to rebind keys", panelX+10, y-10+rows*18+8, FontNormal, menuTextDim)
 	}
 
 	// APPLY / BACK
@@ -688,8 +758,25 @@ func (m *MenuSystem) drawSettings(screen *ebiten.Image) {
 	m.drawBigButton(screen, cx+10, btnY, 120, 36, "BACK", menuBtnNorm)
 }
 
+// Resize re-anchors the menus to a new screen size
+func (m *MenuSystem) Resize(w, h int) {
+	m.ScreenW, m.ScreenH = w, h
+}
+
 // ==================== GAME OVER ====================
 
+// ShowGameOver switches to the end screen with the match stats so far
+func (m *MenuSystem) ShowGameOver(victory bool) {
+	m.GameOverData = GameOverStats{Victory: victory}
+	if m.MatchStats != nil {
+		m.GameOverData.Players = m.MatchStats()
+	}
+	m.State = StateGameOver
+	if m.OnGameOver != nil {
+		m.OnGameOver(victory)
+	}
+}
+
 func (m *MenuSystem) updateGameOver(mx, my int) {
 	if !inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
 		return
@@ -697,7 +784,7 @@ func (m *MenuSystem) updateGameOver(mx, my int) {
 
 	cx := m.ScreenW / 2
 	btnW, btnH := 200, 40
-	btnY := m.ScreenH/2 + 120
+	btnY := m.ScreenH/2 + 150
 
 	if m.clickInRect(mx, my, cx-btnW-10, btnY, btnW, btnH) {
 		// PLAY AGAIN
@@ -721,8 +808,12 @@ func (m *MenuSystem) drawGameOver(screen *ebiten.Image) {
 	cx := m.ScreenW / 2
 	cy := m.ScreenH / 2
 
-	// Panel
-	panelW, panelH := 400, 340
+	// Panel: a label column plus one 100px column per player
+	stats := m.GameOverData.Players
+	panelW, panelH := 400, 420
+	if w := 200 + 100*len(stats); w > panelW {
+		panelW = w
+	}
 	px := float32(cx - panelW/2)
 	py := float32(cy - panelH/2)
 	drawRoundedRect(screen, px, py, float32(panelW), float32(panelH), 12, menuPanel)
@@ -739,34 +830,45 @@ func (m *MenuSystem) drawGameOver(screen *ebiten.Image) {
 		resultClr = menuRed
 	}
 
-	// Big text with glow
-	tx := cx - len(resultText)*3
-	ty := int(py) + 30
-	for dx := -1; dx <= 1; dx++ {
-		for dy := -1; dy <= 1; dy++ {
-			ebitenutil.DebugPrintAt(screen, resultText, tx+dx, ty+dy)
-		}
-	}
+	// Big result text
+	ty := int(py) + 24
+	m.Font.DrawCentered(screen, resultText, cx, ty, FontLarge, resultClr)
 	// Color underline
-	vector.DrawFilledRect(screen, float32(cx-60), float32(ty+18), 120, 3, resultClr, false)
+	vector.DrawFilledRect(screen, float32(cx-60), float32(ty+26), 120, 3, resultClr, false)
 
-	// Stats
-	stats := m.GameOverData
+	// Stats table: values right-aligned in each player's column
 	sy := ty + 40
-	statLines := []string{
-		fmt.Sprintf("Units Built:         %d", stats.UnitsBuilt),
-		fmt.Sprintf("Units Lost:          %d", stats.UnitsLost),
-		fmt.Sprintf("Buildings Built:     %d", stats.BuildingsBuilt),
-		fmt.Sprintf("Buildings Destroyed: %d", stats.BuildingsDestroyed),
-		fmt.Sprintf("Credits Earned:      $%d", stats.CreditsEarned),
-	}
-	for i, line := range statLines {
-		ebitenutil.DebugPrintAt(screen, line, cx-len(line)*3, sy+i*22)
+	lx := int(px) + 20
+	colX := func(i int) int { return int(px) + 200 + 100*i + 80 }
+	for i, r := range stats {
+		m.Font.DrawText(screen, r.Name, colX(i)-m.Font.Measure(r.Name, FontNormal), sy, FontNormal, menuAccent)
+	}
+	statLines := []struct {
+		label string
+		value func(systems.PlayerStats) string
+	}{
+		{"Score", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.Score()) }},
+		{"Units Built", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.UnitsBuilt) }},
+		{"Units Lost", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.UnitsLost) }},
+		{"Units Killed", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.UnitsKilled) }},
+		{"Buildings Built", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.BuildingsBuilt) }},
+		{"Buildings Lost", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.BuildingsLost) }},
+		{"Buildings Destroyed", func(ps systems.PlayerStats) string { return fmt.Sprintf("%d", ps.BuildingsKilled) }},
+		{"Credits Harvested", func(ps systems.PlayerStats) string { return fmt.Sprintf("$%d", ps.CreditsHarvested) }},
+		{"Credits Spent", func(ps systems.PlayerStats) string { return fmt.Sprintf("$%d", ps.CreditsSpent) }},
+	}
+	for j, l := range statLines {
+		y := sy + (j+1)*22
+		m.Font.DrawText(screen, l.label+":", lx, y, FontNormal, menuText)
+		for i, r := range stats {
+			v := l.value(r.Stats)
+			m.Font.DrawText(screen, v, colX(i)-m.Font.Measure(v, FontNormal), y, FontNormal, menuGold)
+		}
 	}
 
 	// Buttons
 	btnW, btnH := 200, 40
-	btnY := cy + 120
+	btnY := cy + 150
 	m.drawBigButton(screen, cx-btnW-10, btnY, btnW, btnH, "PLAY AGAIN", menuGreen)
 	m.drawBigButton(screen, cx+10, btnY, btnW, btnH, "MAIN MENU", menuBtnNorm)
 }
@@ -792,10 +894,7 @@ func (m *MenuSystem) drawMenuButton(screen *ebiten.Image, b MenuButton, hovered
 	if b.Disabled {
 		textClr = menuTextDim
 	}
-	_ = textClr // debug print is always white; we use it directly
-	tx := b.X + b.W/2 - len(b.Text)*3
-	ty := b.Y + b.H/2 - 6
-	ebitenutil.DebugPrintAt(screen, b.Text, tx, ty)
+	m.Font.DrawCentered(screen, b.Text, b.X+b.W/2, b.Y+b.H/2-8, FontNormal, textClr)
 }
 
 func (m *MenuSystem) drawBigButton(screen *ebiten.Image, x, y, w, h int, text string, clr color.RGBA) {
@@ -817,9 +916,7 @@ func (m *MenuSystem) drawBigButton(screen *ebiten.Image, x, y, w, h int, text st
 	}
 	drawRoundedRectStroke(screen, float32(x), float32(y), float32(w), float32(h), 6, borderClr)
 
-	tx := x + w/2 - len(text)*3
-	ty := y + h/2 - 6
-	ebitenutil.DebugPrintAt(screen, text, tx, ty)
+	m.Font.DrawCentered(screen, text, x+w/2, y+h/2-8, FontNormal, menuText)
 }
 
 func (m *MenuSystem) drawToggle(screen *ebiten.Image, x, y int, on bool) {
@@ -838,7 +935,14 @@ func (m *MenuSystem) drawToggle(screen *ebiten.Image, x, y int, on bool) {
 	if on {
 		label = "ON"
 	}
-	ebitenutil.DebugPrintAt(screen, label, x+w+8, y+5)
+	m.Font.DrawText(screen, label, x+w+8, y+4, FontNormal, menuText)
+}
+
+// drawChoice draws a button showing the current value of a click-to-cycle option
+func (m *MenuSystem) drawChoice(screen *ebiten.Image, x, y int, value string) {
+	drawRoundedRect(screen, float32(x), float32(y), 100, 24, 4, menuBtnNorm)
+	drawRoundedRectStroke(screen, float32(x), float32(y), 100, 24, 4, color.RGBA{40, 70, 120, 200})
+	m.Font.DrawCentered(screen, value, x+50, y+4, FontNormal, menuText)
 }
 
 func (m *MenuSystem) drawSlider(screen *ebiten.Image, x, y, w int, value float64) {
@@ -857,7 +961,7 @@ func (m *MenuSystem) drawSlider(screen *ebiten.Image, x, y, w int, value float64
 	vector.StrokeCircle(screen, knobX, float32(y+h/2), 8, 1.5, color.RGBA{255, 255, 255, 100}, false)
 
 	// Value text
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d%%", int(value*100)), x+w+10, y+5)
+	m.Font.DrawText(screen, fmt.Sprintf("%d%%", int(value*100)), x+w+10, y+5, FontNormal, menuText)
 }
 
 func (m *MenuSystem) clickInRect(mx, my, x, y, w, h int) bool {
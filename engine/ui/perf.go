@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const perfHistory = 120 // frames kept for the frame-time graph
+
+// PerfStats are the per-frame numbers shown by the perf overlay
+type PerfStats struct {
+	Ticks     int           // simulation ticks run this frame
+	TickTime  time.Duration // time spent in World.Tick
+	SceneTime time.Duration // time spent in DrawScene
+	Entities  int
+	DrawCalls int
+}
+
+// PerfOverlay is the F3 diagnostics panel with a rolling frame-time graph.
+// It only samples while visible, so it costs nothing when hidden.
+type PerfOverlay struct {
+	Visible bool
+
+	frames [perfHistory]float64 // frame times in ms (ring buffer)
+	next   int
+	count  int
+	last   time.Time
+}
+
+// Toggle shows or hides the overlay, clearing stale samples
+func (p *PerfOverlay) Toggle() {
+	p.Visible = !p.Visible
+	p.count, p.next = 0, 0
+	p.last = time.Time{}
+}
+
+// Frame records the time since the previous frame; call once per Draw
+func (p *PerfOverlay) Frame() {
+	if !p.Visible {
+		return
+	}
+	now := time.Now()
+	if !p.last.IsZero() {
+		p.frames[p.next] = float64(now.Sub(p.last).Microseconds()) / 1000
+		p.next = (p.next + 1) % perfHistory
+		if p.count < perfHistory {
+			p.count++
+		}
+	}
+	p.last = now
+}
+
+// Draw renders the overlay in the top-left corner
+func (p *PerfOverlay) Draw(screen *ebiten.Image, s PerfStats) {
+	if !p.Visible {
+		return
+	}
+	const x, y, w, gh = 8, 8, 240, 60
+	vector.DrawFilledRect(screen, x, y, w, 84+gh, color.RGBA{0, 0, 0, 170}, false)
+
+	worst := 0.0
+	for i := 0; i < p.count; i++ {
+		if p.frames[i] > worst {
+			worst = p.frames[i]
+		}
+	}
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("FPS %.1f  TPS %.1f", ebiten.ActualFPS(), ebiten.ActualTPS()), x+6, y+4)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Tick  %5.2f ms (%d)", ms(s.TickTime), s.Ticks), x+6, y+20)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Scene %5.2f ms  worst %.1f ms", ms(s.SceneTime), worst), x+6, y+36)
+	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("Entities %d  Draws %d", s.Entities, s.DrawCalls), x+6, y+52)
+
+	// Frame-time graph, oldest on the left; full height = 50 ms
+	gx, gy := float32(x+6), float32(y+76)
+	barW := float32(w-12) / perfHistory
+	scale := float32(gh) / 50
+	for i := 0; i < p.count; i++ {
+		ft := p.frames[(p.next-p.count+i+perfHistory)%perfHistory]
+		bh := float32(ft) * scale
+		if bh > gh {
+			bh = gh
+		}
+		clr := color.RGBA{80, 220, 80, 255}
+		if ft > 33.4 {
+			clr = color.RGBA{240, 70, 60, 255}
+		} else if ft > 16.7 {
+			clr = color.RGBA{240, 200, 60, 255}
+		}
+		vector.DrawFilledRect(screen, gx+float32(i)*barW, gy+gh-bh, barW, bh, clr, false)
+	}
+	// 60 and 30 FPS reference lines
+	for _, ref := range []float32{16.7, 33.3} {
+		ly := gy + gh - ref*scale
+		vector.StrokeLine(screen, gx, ly, gx+float32(w-12), ly, 1, color.RGBA{255, 255, 255, 70}, false)
+	}
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// HealthBarMode selects which world health bars are drawn
+type HealthBarMode int
+
+const (
+	HealthBarsDamaged HealthBarMode = iota // damaged or selected only
+	HealthBarsAlways
+	HealthBarsNever
+	healthBarModeCount
+)
+
+var healthBarModeNames = []string{"Damaged", "Always", "Never"}
+
+func (m HealthBarMode) String() string {
+	if m < 0 || m >= healthBarModeCount {
+		return "Damaged"
+	}
+	return healthBarModeNames[m]
+}
+
+// MarshalText stores the mode by name in the settings file
+func (m HealthBarMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText parses a mode name
+func (m *HealthBarMode) UnmarshalText(b []byte) error {
+	for i, name := range healthBarModeNames {
+		if name == string(b) {
+			*m = HealthBarMode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown health bar mode %q", b)
+}
+
+// DefaultGameSettings returns the settings used when no config file exists
+func DefaultGameSettings() GameSettings {
+	return GameSettings{
+		VSync:       true,
+		MusicVolume: 0.7,
+		SFXVolume:   0.8,
+		ScrollSpeed: 5,
+		HealthBars:  HealthBarsDamaged,
+		ShowMinimap: true,
+		EdgeScroll:  true,
+		EdgeSize:    20,
+		EdgeSpeed:   5,
+	}
+}
+
+// LoadSettings reads a JSON settings file over the defaults
+func LoadSettings(path string) (GameSettings, error) {
+	s := DefaultGameSettings()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return DefaultGameSettings(), fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Save writes the settings as JSON
+func (s GameSettings) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
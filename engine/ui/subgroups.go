@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	subGroupPortrait = 44
+	subGroupGap      = 6
+)
+
+// SubGroup is one unit type within a mixed selection
+type SubGroup struct {
+	Key  string
+	Name string
+	IDs  []core.EntityID
+}
+
+// SelectionGroups splits SelectedIDs by tech-tree key, in selection order
+func (h *HUD) SelectionGroups(w *core.World) []SubGroup {
+	var groups []SubGroup
+	index := make(map[string]int)
+	for _, id := range h.SelectedIDs {
+		if !w.Has(id, core.CompPosition) {
+			continue
+		}
+		key := entityKey(w, id)
+		i, ok := index[key]
+		if !ok {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, SubGroup{Key: key, Name: h.keyName(key)})
+		}
+		groups[i].IDs = append(groups[i].IDs, id)
+	}
+	return groups
+}
+
+// entityKey returns the unit or building tech-tree key of an entity
+func entityKey(w *core.World, id core.EntityID) string {
+	if ut := w.Get(id, core.CompUnitType); ut != nil {
+		return ut.(*core.UnitType).Key
+	}
+	if bn := w.Get(id, core.CompBuildingName); bn != nil {
+		return bn.(*core.BuildingName).Key
+	}
+	return "unit"
+}
+
+func (h *HUD) keyName(key string) string {
+	if udef, ok := h.TechTree.Units[key]; ok {
+		return udef.Name
+	}
+	if bdef, ok := h.TechTree.Buildings[key]; ok {
+		return bdef.Name
+	}
+	return key
+}
+
+// activeSubGroup returns the index of ActiveSubGroup, falling back to the first group
+func (h *HUD) activeSubGroup(groups []SubGroup) int {
+	for i, g := range groups {
+		if g.Key == h.ActiveSubGroup {
+			return i
+		}
+	}
+	return 0
+}
+
+// CycleSubGroup makes the next unit type in the selection active
+func (h *HUD) CycleSubGroup(w *core.World) {
+	groups := h.SelectionGroups(w)
+	if len(groups) == 0 {
+		return
+	}
+	i := (h.activeSubGroup(groups) + 1) % len(groups)
+	h.ActiveSubGroup = groups[i].Key
+}
+
+// subGroupRect returns the bounds of the i-th portrait in the bottom panel
+func (h *HUD) subGroupRect(i int) (x, y, w, hh int) {
+	x = h.MinimapSize + 20 + i*(subGroupPortrait+subGroupGap)
+	y = h.ScreenH - h.BottomPanelH + 30
+	return x, y, subGroupPortrait, subGroupPortrait
+}
+
+// maxSubGroups is how many portraits fit left of the command buttons
+func (h *HUD) maxSubGroups() int {
+	panelW := h.ScreenW - h.SidebarWidth - h.MinimapSize - 20
+	return (panelW - 270) / (subGroupPortrait + subGroupGap)
+}
+
+// HandleSubGroupClick narrows the selection to the clicked unit type, or
+// drops that type from it when remove is set (Shift-click)
+func (h *HUD) HandleSubGroupClick(mx, my int, w *core.World, remove bool) bool {
+	if len(h.SelectedIDs) < 2 {
+		return false
+	}
+	groups := h.SelectionGroups(w)
+	for i, g := range groups {
+		if i >= h.maxSubGroups() {
+			break
+		}
+		x, y, pw, ph := h.subGroupRect(i)
+		if mx < x || mx >= x+pw || my < y || my >= y+ph {
+			continue
+		}
+		if !remove {
+			h.SelectedIDs = append([]core.EntityID(nil), g.IDs...)
+			h.ActiveSubGroup = g.Key
+			return true
+		}
+		var kept []core.EntityID
+		for _, other := range groups {
+			if other.Key != g.Key {
+				kept = append(kept, other.IDs...)
+			}
+		}
+		h.SelectedIDs = kept
+		return true
+	}
+	return false
+}
+
+func (h *HUD) drawMultiSelectInfo(screen *ebiten.Image, w *core.World, x, y int) {
+	groups := h.SelectionGroups(w)
+	if len(groups) == 0 {
+		return
+	}
+	active := h.activeSubGroup(groups)
+	header := fmt.Sprintf("%d selected", len(h.SelectedIDs))
+	if len(groups) > 1 {
+		header += fmt.Sprintf("  -  %s (Tab to cycle)", groups[active].Name)
+	}
+	h.Font.DrawText(screen, header, x+10, y+2, FontNormal, textWhite)
+
+	for i, g := range groups {
+		px, py, pw, ph := h.subGroupRect(i)
+		if i >= h.maxSubGroups() {
+			h.Font.DrawText(screen, "...", px, py+ph/2-6, FontNormal, textWhite)
+			break
+		}
+		h.drawSubGroupPortrait(screen, w, g, px, py, pw, ph, i == active)
+	}
+}
+
+// drawSubGroupPortrait draws a cameo with a count badge and the group's average health
+func (h *HUD) drawSubGroupPortrait(screen *ebiten.Image, w *core.World, g SubGroup, x, y, pw, ph int, active bool) {
+	vector.DrawFilledRect(screen, float32(x), float32(y), float32(pw), float32(ph), color.RGBA{10, 15, 25, 240}, false)
+	if icon := h.Sprites.GetBuildIcon(g.Key); icon != nil {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(float64(pw-4)/float64(icon.Bounds().Dx()), float64(ph-4)/float64(icon.Bounds().Dy()))
+		op.GeoM.Translate(float64(x+2), float64(y+2))
+		screen.DrawImage(icon, op)
+	} else {
+		clr := color.RGBA{50, 120, 255, 200}
+		if w.Has(g.IDs[0], core.CompHarvester) {
+			clr = color.RGBA{50, 200, 120, 200}
+		}
+		if w.Has(g.IDs[0], core.CompMCV) {
+			clr = color.RGBA{100, 80, 220, 200}
+		}
+		vector.DrawFilledCircle(screen, float32(x+pw/2), float32(y+ph/2-4), 14, clr, false)
+		label := g.Name
+		if len(label) > 7 {
+			label = label[:7]
+		}
+		h.Font.DrawCentered(screen, label, x+pw/2, y+ph-15, FontSmall, textWhite)
+	}
+
+	border := color.RGBA{70, 80, 100, 200}
+	if active {
+		border = ra2Gold
+	}
+	vector.StrokeRect(screen, float32(x), float32(y), float32(pw), float32(ph), 1.5, border, false)
+
+	if len(g.IDs) > 1 {
+		count := fmt.Sprintf("%d", len(g.IDs))
+		cw := h.Font.Measure(count, FontSmall)
+		vector.DrawFilledRect(screen, float32(x+pw-cw-4), float32(y+1), float32(cw+3), 14, color.RGBA{0, 0, 0, 180}, false)
+		h.Font.DrawText(screen, count, x+pw-cw-2, y+1, FontSmall, textWhite)
+	}
+
+	total, n := 0.0, 0
+	for _, id := range g.IDs {
+		if hp := w.Get(id, core.CompHealth); hp != nil {
+			total += hp.(*core.Health).Ratio()
+			n++
+		}
+	}
+	if n > 0 {
+		ratio := float32(total / float64(n))
+		vector.DrawFilledRect(screen, float32(x), float32(y+ph+2), float32(pw), 3, color.RGBA{0, 0, 0, 160}, false)
+		vector.DrawFilledRect(screen, float32(x), float32(y+ph+2), float32(pw)*ratio, 3, healthBarColor(ratio), false)
+	}
+}
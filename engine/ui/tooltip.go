@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+const (
+	tooltipLineH   = 16
+	tooltipPadding = 8
+	tooltipOffset  = 16 // gap between the cursor and the box
+)
+
+// buildTooltipLines returns the tooltip text for a sidebar item from the TechTree
+func (h *HUD) buildTooltipLines(item SidebarBuildItem) []string {
+	var lines []string
+	if item.IsBuilding {
+		bdef, ok := h.TechTree.Buildings[item.Key]
+		if !ok {
+			return nil
+		}
+		lines = append(lines,
+			bdef.Name,
+			fmt.Sprintf("Cost $%d   Build %.0fs", bdef.Cost, bdef.BuildTime),
+			fmt.Sprintf("HP %d   Size %dx%d", bdef.HP, bdef.SizeX, bdef.SizeY),
+		)
+		switch {
+		case bdef.PowerGen > 0:
+			lines = append(lines, fmt.Sprintf("Power +%d", bdef.PowerGen))
+		case bdef.PowerDraw > 0:
+			lines = append(lines, fmt.Sprintf("Power -%d", bdef.PowerDraw))
+		}
+		lines = append(lines, "Requires: "+prereqNames(h.TechTree, bdef.Prereqs))
+	} else {
+		udef, ok := h.TechTree.Units[item.Key]
+		if !ok {
+			return nil
+		}
+		lines = append(lines,
+			udef.Name,
+			fmt.Sprintf("Cost $%d   Build %.0fs", udef.Cost, udef.BuildTime),
+			fmt.Sprintf("HP %d   Speed %.1f", udef.HP, udef.Speed),
+		)
+		if udef.Damage > 0 {
+			lines = append(lines, fmt.Sprintf("Damage %d   Range %.0f", udef.Damage, udef.Range))
+		}
+		lines = append(lines, "Requires: "+prereqNames(h.TechTree, udef.Prereqs))
+	}
+	if item.Tooltip != "" {
+		lines = append(lines, item.Tooltip)
+	}
+	return lines
+}
+
+// drawBuildTooltip draws the stats box for a hovered build slot near the cursor
+func (h *HUD) drawBuildTooltip(screen *ebiten.Image, item SidebarBuildItem, mx, my int) {
+	lines := h.buildTooltipLines(item)
+	if len(lines) == 0 {
+		return
+	}
+	textW := 0
+	for _, l := range lines {
+		textW = max(textW, h.Font.Measure(l, FontNormal))
+	}
+	bw := textW + tooltipPadding*2
+	bh := len(lines)*tooltipLineH + tooltipPadding
+	bx, by := tooltipPos(mx, my, bw, bh, h.ScreenW, h.ScreenH)
+
+	drawRoundedRect(screen, float32(bx), float32(by), float32(bw), float32(bh), 4, color.RGBA{12, 14, 20, 235})
+	drawRoundedRectStroke(screen, float32(bx), float32(by), float32(bw), float32(bh), 4, ra2MetalLight)
+	vector.DrawFilledRect(screen, float32(bx+tooltipPadding), float32(by+tooltipLineH+2), float32(textW), 1, ra2Gold, false)
+	for i, l := range lines {
+		clr := textWhite
+		if i == 0 {
+			clr = ra2Gold
+		}
+		h.Font.DrawText(screen, l, bx+tooltipPadding, by+4+i*tooltipLineH, FontNormal, clr)
+	}
+	// Blocking reason in red at the bottom
+	if item.Tooltip != "" {
+		vector.DrawFilledRect(screen, float32(bx+2), float32(by+bh-tooltipLineH-2), 3, tooltipLineH-2, powerRed, false)
+	}
+}
+
+// tooltipPos places a w x h box beside the cursor, flipping to the other
+// side and clamping so it stays fully on screen
+func tooltipPos(mx, my, w, h, screenW, screenH int) (int, int) {
+	x := mx + tooltipOffset
+	if x+w > screenW {
+		x = mx - tooltipOffset - w
+	}
+	y := my + tooltipOffset
+	if y+h > screenH {
+		y = my - tooltipOffset - h
+	}
+	x = max(0, min(x, screenW-w))
+	y = max(0, min(y, screenH-h))
+	return x, y
+}
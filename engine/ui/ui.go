@@ -9,7 +9,6 @@ import (
 	"github.com/1siamBot/rts-engine/engine/core"
 	"github.com/1siamBot/rts-engine/engine/systems"
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
 	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
@@ -46,6 +45,10 @@ type PlacementMode struct {
 	SizeX, SizeY int
 	Valid        bool
 	TileX, TileY int
+
+	// Wall drag preview: segment tiles and whether each can be built
+	Segments     []core.TilePos
+	SegmentValid []bool
 }
 
 // Effect represents a visual effect (explosion, smoke, etc.)
@@ -84,10 +87,12 @@ type HUD struct {
 	CurrentCommand CommandType
 	BuildQueue     []string
 	SelectedIDs    []core.EntityID
+	ActiveSubGroup string // unit type key Tab-cycled within a mixed selection
 	ControlGroups  [10][]core.EntityID
 	ActiveTab      BuildTab
 	Placement      PlacementMode
 	Effects        []Effect
+	Floaters       []Floater // damage numbers and kill markers
 
 	// Sidebar mode: repair/sell cursor mode
 	RepairMode bool
@@ -96,11 +101,13 @@ type HUD struct {
 	// Animated credits display
 	DisplayCredits float64
 	ActualCredits  int
+	income         []incomeSample // recent harvests, for the income rate
 
 	// Hover state
 	HoverBuildIdx  int
 	HoverCmdIdx    int
 	HoverSidebar   bool
+	hoverItem      *SidebarBuildItem // build slot under the cursor, for the tooltip
 
 	// Build progress tracking for sidebar (building key -> progress 0-1)
 	BuildProgress map[string]float64
@@ -117,12 +124,20 @@ type HUD struct {
 	TechTree    *systems.TechTree
 	Players     *core.PlayerManager
 	LocalPlayer int
+	Fog         *systems.FogOfWar // local player's fog, shown on the radar
+	MapWidth    int               // tiles the minimap covers
+	MapHeight   int
 
 	// Cached images for rounded rects
 	panelCache map[string]*ebiten.Image
 
+	// Radar fog layer, one pixel per tile
+	fogImg *ebiten.Image
+	fogPix []byte
+
 	// UI Sprites (metallic panels, buttons, icons)
 	Sprites *UISprites
+	Font    *Font
 
 	// Tick counter for animations
 	tick float64
@@ -138,7 +153,7 @@ type HUD struct {
 
 // RA2 sidebar layout constants
 const (
-	sidebarCreditsH   = 30  // credits + power display height
+	sidebarCreditsH   = 44  // credits + power display height, with income and surplus below
 	sidebarCmdBtnH    = 32  // repair/sell/waypoint button row height
 	sidebarTabH       = 28  // tab bar height
 	sidebarSlotSize   = 86  // each build slot is square (was 80, slightly bigger)
@@ -156,6 +171,8 @@ func NewHUD(sw, sh int, tt *systems.TechTree, pm *core.PlayerManager, localPlaye
 		TopBarHeight:   0, // No separate top bar; credits are in sidebar
 		BottomPanelH:   100,
 		MinimapSize:    160,
+		MapWidth:       64,
+		MapHeight:      64,
 		TechTree:       tt,
 		Players:        pm,
 		LocalPlayer:    localPlayer,
@@ -165,9 +182,16 @@ func NewHUD(sw, sh int, tt *systems.TechTree, pm *core.PlayerManager, localPlaye
 		BuildReady:     make(map[string]bool),
 		panelCache:     make(map[string]*ebiten.Image),
 		Sprites:        NewUISprites(),
+		Font:           DefaultFont(),
 	}
 }
 
+// Resize re-anchors the HUD to a new screen size; panels are laid out from
+// the screen edges every frame
+func (h *HUD) Resize(sw, sh int) {
+	h.ScreenW, h.ScreenH = sw, sh
+}
+
 // ---- Drawing Helpers ----
 
 func drawRoundedRect(screen *ebiten.Image, x, y, w, h float32, r float32, clr color.RGBA) {
@@ -267,6 +291,7 @@ func (h *HUD) Update(dt float64) {
 		}
 	}
 	h.Effects = alive
+	h.updateFloaters(dt)
 }
 
 // ShowMessage displays a temporary status message on screen
@@ -295,19 +320,27 @@ func (h *HUD) Draw(screen *ebiten.Image, w *core.World) {
 		if h.statusMsgTime < 0.5 {
 			alpha = uint8(h.statusMsgTime / 0.5 * 255)
 		}
-		msgW := len(h.statusMsg)*7 + 20
+		msgW := h.Font.Measure(h.statusMsg, FontNormal) + 20
 		msgX := (h.ScreenW-h.SidebarWidth)/2 - msgW/2
 		msgY := h.ScreenH/2 - 40
 		drawRoundedRect(screen, float32(msgX), float32(msgY), float32(msgW), 28, 6, color.RGBA{180, 30, 30, alpha})
-		ebitenutil.DebugPrintAt(screen, h.statusMsg, msgX+10, msgY+8)
+		h.Font.DrawText(screen, h.statusMsg, msgX+10, msgY+7, FontNormal, color.RGBA{255, 255, 255, alpha})
 	}
 
 	// Repair/Sell cursor indicator
 	if h.RepairMode {
-		ebitenutil.DebugPrintAt(screen, "🔧 REPAIR MODE - Click a building", 10, 10)
+		h.drawIconOrGlyph(screen, h.Sprites.IconRepair, 18, 18, 16, drawWrenchGlyph, textWhite)
+		h.Font.DrawText(screen, "REPAIR MODE - Click a building", 32, 10, FontNormal, textWhite)
 	}
 	if h.SellMode {
-		ebitenutil.DebugPrintAt(screen, "💰 SELL MODE - Click a building", 10, 10)
+		h.drawIconOrGlyph(screen, h.Sprites.IconSell, 18, 18, 16, drawCoinGlyph, ra2Gold)
+		h.Font.DrawText(screen, "SELL MODE - Click a building", 32, 10, FontNormal, textWhite)
+	}
+
+	// Build slot tooltip goes on top of everything else
+	if h.hoverItem != nil {
+		mx, my := ebiten.CursorPosition()
+		h.drawBuildTooltip(screen, *h.hoverItem, mx, my)
 	}
 }
 
@@ -422,7 +455,7 @@ func (h *HUD) DrawWorldEffects(screen *ebiten.Image, w *core.World, worldToScree
 				vector.DrawFilledRect(screen, float32(sx)-bw/2, float32(sy)+bh/2-builtH, bw, builtH, bcolor, false)
 				vector.StrokeRect(screen, float32(sx)-bw/2, float32(sy)-bh/2, bw, bh, 1, color.RGBA{200, 200, 100, 100}, false)
 				pctText := fmt.Sprintf("%d%%", int(constr.Progress*100))
-				ebitenutil.DebugPrintAt(screen, pctText, sx-10, sy-5)
+				h.Font.DrawCentered(screen, pctText, sx, sy-6, FontSmall, textWhite)
 				continue
 			}
 		}
@@ -472,7 +505,7 @@ func (h *HUD) DrawWorldEffects(screen *ebiten.Image, w *core.World, worldToScree
 					badgeX := float32(sx) + bw/2 - 5
 					badgeY := float32(sy) - bh/2 - 5
 					vector.DrawFilledCircle(screen, badgeX, badgeY, 7, color.RGBA{220, 50, 50, 240}, false)
-					ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d", len(p.Queue)), int(badgeX)-3, int(badgeY)-6)
+					h.Font.DrawCentered(screen, fmt.Sprintf("%d", len(p.Queue)), int(badgeX), int(badgeY)-6, FontSmall, textWhite)
 				}
 			}
 		}
@@ -525,7 +558,7 @@ func (h *HUD) DrawPlacementGhost(screen *ebiten.Image, worldToScreen func(float6
 	drawRoundedRectStroke(screen, float32(sx)-bw/2, float32(sy)-bh/2, bw, bh, 3, borderColor)
 
 	if bdef, ok := h.TechTree.Buildings[h.Placement.BuildingKey]; ok {
-		ebitenutil.DebugPrintAt(screen, bdef.Name, sx-int(bw/2), sy-int(bh/2)-14)
+		h.Font.DrawCentered(screen, bdef.Name, sx, sy-int(bh/2)-16, FontNormal, textWhite)
 	}
 }
 
@@ -587,12 +620,21 @@ func (h *HUD) drawSidebarCredits(screen *ebiten.Image, sx, y int) int {
 	// Credits icon + scrolling number
 	credX := sx + sidebarPowerBarW + 8
 	// Gold coin
-	vector.DrawFilledCircle(screen, float32(credX+6), float32(y+15), 7, color.RGBA{255, 200, 0, 255}, false)
-	vector.DrawFilledCircle(screen, float32(credX+5), float32(y+14), 4, color.RGBA{255, 230, 100, 255}, false)
-	ebitenutil.DebugPrintAt(screen, "$", credX+3, y+9)
+	if h.Sprites.IconCredits != nil {
+		h.Sprites.DrawIcon(screen, h.Sprites.IconCredits, credX+6, y+15, 14)
+	} else {
+		vector.DrawFilledCircle(screen, float32(credX+6), float32(y+15), 7, color.RGBA{255, 200, 0, 255}, false)
+		vector.DrawFilledCircle(screen, float32(credX+5), float32(y+14), 4, color.RGBA{255, 230, 100, 255}, false)
+		h.Font.DrawCentered(screen, "$", credX+6, y+8, FontSmall, color.RGBA{90, 60, 0, 255})
+	}
 
 	creditStr := fmt.Sprintf("$%d", int(h.DisplayCredits))
-	ebitenutil.DebugPrintAt(screen, creditStr, credX+18, y+9)
+	credClr := ra2Gold
+	// Storage full: further harvesting is wasted
+	if player.OreCapacity > 0 && player.Ore >= player.OreCapacity {
+		credClr = powerRed
+	}
+	h.Font.DrawText(screen, creditStr, credX+18, y+7, FontNormal, credClr)
 
 	// Power display on right side
 	pwrX := sx + h.SidebarWidth - 70
@@ -601,9 +643,18 @@ func (h *HUD) drawSidebarCredits(screen *ebiten.Image, sx, y int) int {
 	if !hasPower && int(h.tick*4)%2 == 0 {
 		pwrClr = powerRed
 	}
-	vector.DrawFilledCircle(screen, float32(pwrX+6), float32(y+15), 5, pwrClr, false)
-	ebitenutil.DebugPrintAt(screen, "⚡", pwrX, y+9)
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d/%d", player.Power, player.PowerUse), pwrX+14, y+9)
+	vector.DrawFilledCircle(screen, float32(pwrX+6), float32(y+15), 7, pwrClr, false)
+	h.drawIconOrGlyph(screen, h.Sprites.IconPower, pwrX+6, y+15, 10, drawBoltGlyph, color.RGBA{20, 22, 26, 255})
+	h.Font.DrawText(screen, fmt.Sprintf("%d/%d", player.Power, player.PowerUse), pwrX+14, y+8, FontNormal, textWhite)
+
+	// Income rate and power surplus underneath
+	h.Font.DrawText(screen, fmt.Sprintf("+$%d/min", h.IncomePerMinute()), credX+18, y+27, FontSmall, textDim)
+	surplus := player.Power - player.PowerUse
+	surClr := powerGreen
+	if surplus < 0 {
+		surClr = powerRed
+	}
+	h.Font.DrawText(screen, fmt.Sprintf("%+d", surplus), pwrX+14, y+27, FontSmall, surClr)
 
 	return y + sidebarCreditsH
 }
@@ -657,14 +708,15 @@ func (h *HUD) drawSidebarCmdButtons(screen *ebiten.Image, sx, y int) int {
 	startX := sx + sidebarPowerBarW + sidebarPadding
 
 	type cmdBtn struct {
-		label  string
 		active bool
 		icon   *ebiten.Image
+		glyph  func(*ebiten.Image, float32, float32, float32, color.Color)
+		clr    color.Color
 	}
 	buttons := []cmdBtn{
-		{"🔧", h.RepairMode, h.Sprites.IconRepair},
-		{"💰", h.SellMode, h.Sprites.IconSell},
-		{"🏳", false, nil},
+		{h.RepairMode, h.Sprites.IconRepair, drawWrenchGlyph, textWhite},
+		{h.SellMode, h.Sprites.IconSell, drawCoinGlyph, ra2Gold},
+		{false, h.Sprites.IconRally, drawFlagGlyph, textWhite},
 	}
 
 	for i, btn := range buttons {
@@ -683,12 +735,7 @@ func (h *HUD) drawSidebarCmdButtons(screen *ebiten.Image, sx, y int) int {
 		vector.StrokeLine(screen, float32(bx+btnW), float32(by), float32(bx+btnW), float32(by+btnH), 1, color.RGBA{20, 22, 26, 255}, false)
 		vector.StrokeLine(screen, float32(bx), float32(by+btnH), float32(bx+btnW), float32(by+btnH), 1, color.RGBA{20, 22, 26, 255}, false)
 
-		// Icon or label
-		if btn.icon != nil {
-			h.Sprites.DrawIcon(screen, btn.icon, bx+btnW/2, by+btnH/2, 20)
-		} else {
-			ebitenutil.DebugPrintAt(screen, btn.label, bx+btnW/2-6, by+btnH/2-6)
-		}
+		h.drawIconOrGlyph(screen, btn.icon, bx+btnW/2, by+btnH/2, 20, btn.glyph, btn.clr)
 	}
 
 	return y + sidebarCmdBtnH
@@ -726,8 +773,7 @@ func (h *HUD) drawSidebarTabs(screen *ebiten.Image, sx, y int) int {
 			vector.StrokeLine(screen, float32(tx+tabW-1), float32(ty), float32(tx+tabW-1), float32(ty+sidebarTabH), 1, color.RGBA{25, 28, 32, 255}, false)
 		}
 
-		textX := tx + tabW/2 - len(name)*3
-		ebitenutil.DebugPrintAt(screen, name, textX, ty+9)
+		h.Font.DrawCentered(screen, name, tx+tabW/2, ty+7, FontNormal, textWhite)
 	}
 
 	return y + sidebarTabH + 2
@@ -876,12 +922,19 @@ func (h *HUD) drawSidebarBuildGrid(screen *ebiten.Image, w *core.World, sx, star
 	}
 
 	// Draw build slots (2 columns)
+	mx, my := ebiten.CursorPosition()
+	h.HoverBuildIdx = -1
+	h.hoverItem = nil
 	for i := 0; i < visibleSlots && i+h.ScrollOffset < totalItems; i++ {
 		item := items[i+h.ScrollOffset]
 		col := i % 2
 		row := i / 2
 		slotX := gridStartX + col*(slotW+sidebarSlotGap)
 		slotY := startY + row*(slotH+sidebarSlotGap)
+		if mx >= slotX && mx < slotX+slotW && my >= slotY && my < slotY+slotH {
+			h.HoverBuildIdx = i
+			h.hoverItem = &items[i+h.ScrollOffset]
+		}
 
 		h.drawBuildSlot(screen, slotX, slotY, slotW, slotH, item, i)
 	}
@@ -894,12 +947,12 @@ func (h *HUD) drawSidebarBuildGrid(screen *ebiten.Image, w *core.World, sx, star
 		// Up arrow
 		if h.ScrollOffset > 0 {
 			vector.DrawFilledRect(screen, float32(gridStartX), float32(startY-16), float32(contentW), 14, ra2MetalMid, false)
-			ebitenutil.DebugPrintAt(screen, "▲ scroll up", arrowX-30, startY-14)
+			drawArrowGlyph(screen, float32(arrowX), float32(startY-9), 12, true, textWhite)
 		}
 		// Down arrow
 		if h.ScrollOffset < maxScroll {
 			vector.DrawFilledRect(screen, float32(gridStartX), float32(arrowY), float32(contentW), float32(sidebarScrollH), ra2MetalMid, false)
-			ebitenutil.DebugPrintAt(screen, "▼ scroll down", arrowX-36, arrowY+4)
+			drawArrowGlyph(screen, float32(arrowX), float32(arrowY+sidebarScrollH/2), 12, false, textWhite)
 		}
 	}
 }
@@ -977,7 +1030,7 @@ func (h *HUD) drawBuildSlot(screen *ebiten.Image, x, y, w, hh int, item SidebarB
 		iconM := 6
 		drawIsoBlock(screen, float32(x+w/2), float32(y+hh/2-6), float32(w-iconM*2), float32((hh-iconM*2)/2), iconClr)
 		if len(item.Name) > 0 {
-			ebitenutil.DebugPrintAt(screen, string(item.Name[0]), x+w/2-3, y+hh/2-12)
+			h.Font.DrawCentered(screen, string(item.Name[0]), x+w/2, y+hh/2-16, FontLarge, textWhite)
 		}
 	}
 
@@ -989,10 +1042,11 @@ func (h *HUD) drawBuildSlot(screen *ebiten.Image, x, y, w, hh int, item SidebarB
 	// "READY" flashing text
 	if item.Ready {
 		if int(h.tick*4)%2 == 0 {
-			readyX := x + w/2 - 18
+			readyW := h.Font.Measure("READY", FontNormal)
+			readyX := x + w/2 - readyW/2
 			readyY := y + hh/2 - 6
-			vector.DrawFilledRect(screen, float32(readyX-2), float32(readyY-2), 40, 16, color.RGBA{0, 0, 0, 180}, false)
-			ebitenutil.DebugPrintAt(screen, "READY", readyX, readyY)
+			vector.DrawFilledRect(screen, float32(readyX-3), float32(readyY-2), float32(readyW+6), 16, color.RGBA{0, 0, 0, 180}, false)
+			h.Font.DrawText(screen, "READY", readyX, readyY, FontNormal, ra2ReadyGreen)
 		}
 	}
 
@@ -1001,14 +1055,12 @@ func (h *HUD) drawBuildSlot(screen *ebiten.Image, x, y, w, hh int, item SidebarB
 		badgeX := x + w - 12
 		badgeY := y + 2
 		vector.DrawFilledCircle(screen, float32(badgeX+6), float32(badgeY+6), 8, color.RGBA{220, 50, 50, 240}, false)
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d", item.QueueCount), badgeX+2, badgeY)
+		h.Font.DrawCentered(screen, fmt.Sprintf("%d", item.QueueCount), badgeX+6, badgeY, FontSmall, textWhite)
 	}
 
 	// Lock icon if prerequisites not met
 	if !item.HasPrereqs {
-		lockX := x + w/2 - 3
-		lockY := y + hh/2 - 6
-		ebitenutil.DebugPrintAt(screen, "🔒", lockX, lockY)
+		drawLockGlyph(screen, float32(x+w/2), float32(y+hh/2), 14, textWhite)
 	}
 
 	// Cost text at bottom of slot
@@ -1017,12 +1069,10 @@ func (h *HUD) drawBuildSlot(screen *ebiten.Image, x, y, w, hh int, item SidebarB
 	if !item.CanAfford {
 		costClr = color.RGBA{180, 60, 60, 255}
 	}
-	_ = costClr // using debug print which is always white
-	costX := x + w/2 - len(costStr)*3
 	costY := y + hh - 12
 	// Dark background strip for cost
 	vector.DrawFilledRect(screen, float32(x), float32(costY-2), float32(w), 14, color.RGBA{0, 0, 0, 160}, false)
-	ebitenutil.DebugPrintAt(screen, costStr, costX, costY)
+	h.Font.DrawCentered(screen, costStr, x+w/2, costY-1, FontSmall, costClr)
 }
 
 // drawClockWipe draws a clock-wipe progress overlay on a build slot
@@ -1066,7 +1116,7 @@ func (h *HUD) drawClockWipe(screen *ebiten.Image, x, y, w, hh int, progress floa
 
 	// Progress text
 	pctText := fmt.Sprintf("%d%%", int(progress*100))
-	ebitenutil.DebugPrintAt(screen, pctText, x+w/2-12, y+hh/2-6)
+	h.Font.DrawCentered(screen, pctText, x+w/2, y+hh/2-8, FontNormal, textWhite)
 }
 
 // drawIsoBlock draws a small 3D isometric block for building icons
@@ -1184,14 +1234,14 @@ func (h *HUD) drawSingleUnitInfo(screen *ebiten.Image, w *core.World, x, y int)
 			name = bdef.Name
 		}
 	}
-	ebitenutil.DebugPrintAt(screen, name, x+72, y+5)
+	h.Font.DrawText(screen, name, x+72, y+5, FontNormal, textWhite)
 
 	if hp := w.Get(id, core.CompHealth); hp != nil {
 		health := hp.(*core.Health)
 		ratio := health.Ratio()
 		h.Sprites.DrawBar(screen, x+72, y+20, 130, 12, ratio, "health")
 		hpText := fmt.Sprintf("%d / %d", health.Current, health.Max)
-		ebitenutil.DebugPrintAt(screen, hpText, x+72, y+34)
+		h.Font.DrawText(screen, hpText, x+72, y+34, FontNormal, textWhite)
 	}
 
 	if wep := w.Get(id, core.CompWeapon); wep != nil {
@@ -1199,49 +1249,94 @@ func (h *HUD) drawSingleUnitInfo(screen *ebiten.Image, w *core.World, x, y int)
 		if h.Sprites.IconAttack != nil {
 			h.Sprites.DrawIcon(screen, h.Sprites.IconAttack, x+80, y+55, 12)
 		}
-		ebitenutil.DebugPrintAt(screen, fmt.Sprintf("DMG:%d RNG:%.0f", weapon.Damage, weapon.Range), x+90, y+50)
+		h.Font.DrawText(screen, fmt.Sprintf("DMG:%d RNG:%.0f", weapon.Damage, weapon.Range), x+90, y+50, FontNormal, textWhite)
 	}
 
-	if w.Has(id, core.CompMCV) {
-		state := "normal"
+	if dep := w.Get(id, core.CompDeploying); dep != nil {
+		d := dep.(*core.Deploying)
+		label := "DEPLOYING"
+		if d.Undeploy {
+			label = "PACKING UP"
+		}
+		h.Sprites.DrawBar(screen, x+72, y+64, 130, 10, d.Progress(), "progress")
+		h.Font.DrawText(screen, label, x+72, y+76, FontSmall, ra2Gold)
+	} else if sg := w.Get(id, core.CompSiege); w.Has(id, core.CompMCV) || sg != nil {
+		state, label := "normal", "DEPLOY [H]"
 		if h.CurrentCommand == CmdDeploy {
 			state = "active"
 		}
+		if sg != nil && sg.(*core.Siege).Deployed {
+			label = "PACK UP [H]"
+		}
 		h.Sprites.DrawRectButton(screen, x+72, y+62, 80, 22, state)
 		if h.Sprites.IconDeploy != nil {
 			h.Sprites.DrawIcon(screen, h.Sprites.IconDeploy, x+84, y+73, 14)
 		}
-		ebitenutil.DebugPrintAt(screen, "DEPLOY [H]", x+94, y+67)
+		h.Font.DrawText(screen, label, x+94, y+67, FontNormal, textWhite)
 	}
-}
 
-func (h *HUD) drawMultiSelectInfo(screen *ebiten.Image, w *core.World, x, y int) {
-	ebitenutil.DebugPrintAt(screen, fmt.Sprintf("%d units selected", len(h.SelectedIDs)), x+10, y+5)
+	if bc := w.Get(id, core.CompBuilding); bc != nil {
+		h.drawBuildingState(screen, w, id, bc.(*core.Building), x, y)
+	}
+}
 
-	ix := x + 10
-	count := 0
-	for _, id := range h.SelectedIDs {
-		if count >= 20 {
-			break
-		}
-		clr := color.RGBA{50, 120, 255, 200}
-		if w.Has(id, core.CompHarvester) {
-			clr = color.RGBA{50, 200, 120, 200}
-		}
-		if w.Has(id, core.CompMCV) {
-			clr = color.RGBA{100, 80, 220, 200}
+// drawBuildingState shows the power toggle and sell/repair state of a building
+func (h *HUD) drawBuildingState(screen *ebiten.Image, w *core.World, id core.EntityID, b *core.Building, x, y int) {
+	if b.PowerDraw > 0 && h.ownsEntity(w, id) {
+		bx, by, bw, bh := h.powerButtonRect()
+		state, label := "normal", "POWER ON [P]"
+		if b.PoweredDown {
+			state, label = "active", "POWER OFF [P]"
 		}
-		vector.DrawFilledCircle(screen, float32(ix+8), float32(y+30), 8, clr, false)
+		h.Sprites.DrawRectButton(screen, bx, by, bw, bh, state)
+		h.Font.DrawText(screen, label, bx+8, by+5, FontNormal, textWhite)
+	}
 
-		if hp := w.Get(id, core.CompHealth); hp != nil {
-			health := hp.(*core.Health)
-			ratio := float32(health.Ratio())
-			vector.DrawFilledRect(screen, float32(ix), float32(y+42), 16*ratio, 2, healthBarColor(ratio), false)
+	tx := x + 210
+	if b.Sellable {
+		h.Font.DrawText(screen, "Sellable", tx, y+5, FontNormal, textWhite)
+	} else {
+		h.Font.DrawText(screen, "Cannot sell", tx, y+5, FontNormal, textWhite)
+	}
+	if hp := w.Get(id, core.CompHealth); hp != nil {
+		health := hp.(*core.Health)
+		switch {
+		case h.RepairTargetID == id:
+			h.Font.DrawText(screen, "Repairing...", tx, y+20, FontNormal, textWhite)
+		case health.Current < health.Max:
+			h.Font.DrawText(screen, "Needs repair", tx, y+20, FontNormal, textWhite)
 		}
+	}
+	if b.PoweredDown {
+		h.Font.DrawText(screen, "Offline", tx, y+35, FontNormal, textWhite)
+	}
+}
+
+// powerButtonRect returns the power toggle button bounds in the bottom panel
+func (h *HUD) powerButtonRect() (bx, by, bw, bh int) {
+	panelX := h.MinimapSize + 10
+	panelY := h.ScreenH - h.BottomPanelH
+	return panelX + 82, panelY + 72, 110, 22
+}
 
-		ix += 20
-		count++
+// PowerButtonHit reports whether (mx, my) is on the power toggle of a
+// single selected building the local player owns that draws power
+func (h *HUD) PowerButtonHit(mx, my int, w *core.World) bool {
+	if len(h.SelectedIDs) != 1 {
+		return false
+	}
+	bc := w.Get(h.SelectedIDs[0], core.CompBuilding)
+	if bc == nil || bc.(*core.Building).PowerDraw <= 0 || !h.ownsEntity(w, h.SelectedIDs[0]) {
+		return false
 	}
+	bx, by, bw, bh := h.powerButtonRect()
+	return mx >= bx && mx < bx+bw && my >= by && my < by+bh
+}
+
+// ownsEntity reports whether the local player owns an entity
+func (h *HUD) ownsEntity(w *core.World, id core.EntityID) bool {
+	own, ok := core.GetComponent[*core.Owner](w, id)
+	return ok && own.PlayerID == h.LocalPlayer
 }
 
 func (h *HUD) drawCommandButtons(screen *ebiten.Image, x, y int) {
@@ -1275,8 +1370,7 @@ func (h *HUD) drawCommandButtons(screen *ebiten.Image, x, y int) {
 			h.Sprites.DrawIcon(screen, icon, bx+22, by+18, 20)
 		}
 
-		textX := bx + 22 - len(c.name)*3
-		ebitenutil.DebugPrintAt(screen, c.name, textX, by+32)
+		h.Font.DrawCentered(screen, c.name, bx+22, by+31, FontSmall, textWhite)
 	}
 }
 
@@ -1298,9 +1392,15 @@ func (h *HUD) drawMinimap(screen *ebiten.Image, w *core.World) {
 		drawRoundedRectStroke(screen, float32(mx-2), float32(my-18), float32(mw+4), float32(mh+22), 6, panelBorder)
 	}
 
-	ebitenutil.DebugPrintAt(screen, "TACTICAL MAP", mx+30, my-16)
+	h.Font.DrawCentered(screen, "TACTICAL MAP", mx+mw/2, my-17, FontNormal, textWhite)
 	vector.DrawFilledRect(screen, float32(mx), float32(my), float32(mw), float32(mh), minimapBG, false)
 
+	// Without a working radar the map stays dark
+	if !h.RadarOnline() {
+		h.Font.DrawCentered(screen, "RADAR OFFLINE", mx+mw/2, my+mh/2-6, FontSmall, textDim)
+		return
+	}
+
 	// Radar sweep effect
 	sweepAngle := h.tick * 0.8
 	sweepCx := float32(mx) + float32(mw)/2
@@ -1320,12 +1420,16 @@ func (h *HUD) drawMinimap(screen *ebiten.Image, w *core.World) {
 		vector.StrokeLine(screen, sweepCx, sweepCy, tEndX, tEndY, 1, color.RGBA{0, 200, 100, alpha}, false)
 	}
 
+	h.drawMinimapFog(screen, mx, my, mw, mh)
+
 	for _, id := range w.Query(core.CompPosition, core.CompOwner) {
 		pos := w.Get(id, core.CompPosition).(*core.Position)
 		own := w.Get(id, core.CompOwner).(*core.Owner)
+		if own.PlayerID != h.LocalPlayer && h.Fog != nil && !h.Fog.IsVisible(int(pos.X), int(pos.Y)) {
+			continue
+		}
 
-		dotX := float32(mx) + float32(pos.X/64.0)*float32(mw)
-		dotY := float32(my) + float32(pos.Y/64.0)*float32(mh)
+		dotX, dotY := h.minimapPoint(mx, my, pos.X, pos.Y)
 
 		dotClr := color.RGBA{60, 140, 255, 255}
 		dotR := float32(2)
@@ -1340,10 +1444,51 @@ func (h *HUD) drawMinimap(screen *ebiten.Image, w *core.World) {
 		vector.DrawFilledCircle(screen, dotX, dotY, dotR, dotClr, false)
 	}
 
+	// Crates blink gold
+	if math.Mod(h.tick, 1.0) < 0.6 {
+		for _, id := range w.Query(core.CompCrate, core.CompPosition) {
+			pos := w.Get(id, core.CompPosition).(*core.Position)
+			if h.Fog != nil && h.Fog.At(int(pos.X), int(pos.Y)) == systems.FogShroud {
+				continue
+			}
+			dotX, dotY := h.minimapPoint(mx, my, pos.X, pos.Y)
+			vector.DrawFilledRect(screen, dotX-1.5, dotY-1.5, 3, 3, color.RGBA{255, 210, 60, 255}, false)
+		}
+	}
+
 	scanY := float32(my) + float32(mh)*float32(math.Mod(h.tick*0.3, 1.0))
 	vector.DrawFilledRect(screen, float32(mx), scanY, float32(mw), 1, color.RGBA{0, 255, 0, 15}, false)
 }
 
+// drawMinimapFog blacks out unexplored tiles on the radar and dims ones
+// not currently in view
+func (h *HUD) drawMinimapFog(screen *ebiten.Image, mx, my, mw, mh int) {
+	fog := h.Fog
+	if fog == nil || fog.Width == 0 || fog.Height == 0 {
+		return
+	}
+	if h.fogImg == nil || h.fogImg.Bounds().Dx() != fog.Width || h.fogImg.Bounds().Dy() != fog.Height {
+		h.fogImg = ebiten.NewImage(fog.Width, fog.Height)
+		h.fogPix = make([]byte, fog.Width*fog.Height*4)
+	}
+	for i, st := range fog.Grid {
+		// Premultiplied black: only alpha varies
+		var a byte
+		switch st {
+		case systems.FogShroud:
+			a = 255
+		case systems.FogExplored:
+			a = 140
+		}
+		h.fogPix[i*4+3] = a
+	}
+	h.fogImg.WritePixels(h.fogPix)
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(mw)/float64(fog.Width), float64(mh)/float64(fog.Height))
+	op.GeoM.Translate(float64(mx), float64(my))
+	screen.DrawImage(h.fogImg, op)
+}
+
 // ---- Ore Sparkle Drawing ----
 
 func (h *HUD) DrawOreSparkles(screen *ebiten.Image, tileX, tileY int, oreAmount int, screenX, screenY int) {
@@ -1476,10 +1621,26 @@ func (h *HUD) HandleScroll(mx, my int, scrollY float64) bool {
 }
 
 // GetMinimapWorldPos converts a minimap click to world coordinates
-func (h *HUD) GetMinimapWorldPos(mx, my, mapSize int) (float64, float64) {
+func (h *HUD) GetMinimapWorldPos(mx, my int) (float64, float64) {
 	relX := float64(mx-5) / float64(h.MinimapSize)
 	relY := float64(my-(h.ScreenH-h.MinimapSize-5)) / float64(h.MinimapSize)
-	return relX * float64(mapSize), relY * float64(mapSize)
+	return relX * float64(h.MapWidth), relY * float64(h.MapHeight)
+}
+
+// minimapPoint converts a world position to a point on the minimap whose
+// top-left corner is (mx, my)
+func (h *HUD) minimapPoint(mx, my int, x, y float64) (float32, float32) {
+	return float32(mx) + float32(x/float64(h.MapWidth)*float64(h.MinimapSize)),
+		float32(my) + float32(y/float64(h.MapHeight)*float64(h.MinimapSize))
+}
+
+// RadarOnline reports whether the local player's minimap is lit
+func (h *HUD) RadarOnline() bool {
+	if h.Players == nil {
+		return false
+	}
+	p := h.Players.GetPlayer(h.LocalPlayer)
+	return p != nil && p.Radar
 }
 
 // IsInMinimap checks if click is in minimap area
@@ -1563,12 +1724,53 @@ func (h *HUD) AssignControlGroup(n int) {
 	copy(h.ControlGroups[n], h.SelectedIDs)
 }
 
-func (h *HUD) RecallControlGroup(n int) {
+// RecallControlGroup selects the members of group n the local player still
+// owns. Members held by someone else for now stay in the group, so a unit
+// that is won back is in it again.
+func (h *HUD) RecallControlGroup(w *core.World, n int) {
 	if n < 0 || n > 9 {
 		return
 	}
 	h.SelectedIDs = make([]core.EntityID, len(h.ControlGroups[n]))
 	copy(h.SelectedIDs, h.ControlGroups[n])
+	h.SelectedIDs = h.ownedIDs(w, h.SelectedIDs)
+}
+
+// PruneDead drops destroyed entities from the selection and control
+// groups, and clears a destroyed repair target. The selection also loses
+// entities the local player no longer owns (e.g. mind-controlled away);
+// control groups keep them for when they come back.
+func (h *HUD) PruneDead(w *core.World) {
+	h.SelectedIDs = h.ownedIDs(w, h.SelectedIDs)
+	for i := range h.ControlGroups {
+		h.ControlGroups[i] = liveIDs(w, h.ControlGroups[i])
+	}
+	if h.RepairTargetID != 0 && !w.Alive(h.RepairTargetID) {
+		h.RepairTargetID = 0
+	}
+}
+
+// ownedIDs filters ids in place, keeping those still in the world and
+// owned by the local player
+func (h *HUD) ownedIDs(w *core.World, ids []core.EntityID) []core.EntityID {
+	kept := ids[:0]
+	for _, id := range ids {
+		if w.Alive(id) && h.ownsEntity(w, id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+// liveIDs filters ids in place, keeping those still in the world
+func liveIDs(w *core.World, ids []core.EntityID) []core.EntityID {
+	kept := ids[:0]
+	for _, id := range ids {
+		if w.Alive(id) {
+			kept = append(kept, id)
+		}
+	}
+	return kept
 }
 
 func (h *HUD) IsInSidebar(mx, _ int) bool {
@@ -1579,6 +1781,8 @@ func (h *HUD) IsInSidebar(mx, _ int) bool {
 func (h *HUD) CancelPlacement() {
 	h.Placement.Active = false
 	h.Placement.BuildingKey = ""
+	h.Placement.Segments = nil
+	h.Placement.SegmentValid = nil
 }
 
 // StartPlacement enters building placement mode
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/1siamBot/rts-engine/engine/core"
+)
+
+func TestPruneDeadDropsLostUnits(t *testing.T) {
+	w := core.NewWorld(20)
+	unit := func(owner int) core.EntityID {
+		id := w.Spawn()
+		w.Attach(id, &core.Owner{PlayerID: owner})
+		return id
+	}
+	kept := unit(0)
+	taken := unit(0)
+	dead := unit(0)
+	h := &HUD{LocalPlayer: 0}
+	h.SelectedIDs = []core.EntityID{kept, taken, dead}
+	h.ControlGroups[1] = []core.EntityID{taken, kept, dead}
+
+	own := w.Get(taken, core.CompOwner).(*core.Owner)
+	own.PlayerID = 1 // mind-controlled away
+	w.Destroy(dead)
+	w.Tick(0.05)
+	h.PruneDead(w)
+
+	if want := []core.EntityID{kept}; !slices.Equal(h.SelectedIDs, want) {
+		t.Errorf("selection = %v, want %v", h.SelectedIDs, want)
+	}
+	if want := []core.EntityID{taken, kept}; !slices.Equal(h.ControlGroups[1], want) {
+		t.Errorf("group 1 = %v, want %v", h.ControlGroups[1], want)
+	}
+
+	// while it's held by the enemy, recalling the group skips it
+	h.RecallControlGroup(w, 1)
+	if want := []core.EntityID{kept}; !slices.Equal(h.SelectedIDs, want) {
+		t.Errorf("recalled %v while captured, want %v", h.SelectedIDs, want)
+	}
+
+	// won back, it answers to its group again
+	own.PlayerID = 0
+	h.PruneDead(w)
+	h.RecallControlGroup(w, 1)
+	if want := []core.EntityID{taken, kept}; !slices.Equal(h.SelectedIDs, want) {
+		t.Errorf("recalled %v after recapture, want %v", h.SelectedIDs, want)
+	}
+}
+
+func TestHUDAnchorsToScreen(t *testing.T) {
+	tests := []struct {
+		name string
+		w, h int
+	}{
+		{"720p", 1280, 720},
+		{"1080p", 1920, 1080},
+		{"small window", 1024, 600},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &HUD{ScreenW: 1280, ScreenH: 720, SidebarWidth: 200, MinimapSize: 160}
+			h.Resize(tc.w, tc.h)
+
+			// Sidebar hugs the right edge at its fixed width
+			if !h.IsInSidebar(tc.w-1, 10) || !h.IsInSidebar(tc.w-h.SidebarWidth, 10) {
+				t.Error("right edge not in the sidebar")
+			}
+			if h.IsInSidebar(tc.w-h.SidebarWidth-1, 10) {
+				t.Error("sidebar wider than SidebarWidth")
+			}
+			// Minimap sits in the bottom-left corner
+			if !h.IsInMinimap(6, tc.h-6) || !h.IsInMinimap(5+h.MinimapSize-1, tc.h-h.MinimapSize-5) {
+				t.Error("bottom-left corner not in the minimap")
+			}
+			if h.IsInMinimap(6, tc.h-h.MinimapSize-6) || h.IsInMinimap(6+h.MinimapSize, tc.h-6) {
+				t.Error("minimap larger than MinimapSize")
+			}
+		})
+	}
+}
+
+func TestMainMenuCentred(t *testing.T) {
+	for _, size := range [][2]int{{1280, 720}, {1920, 1080}} {
+		m := NewMenuSystem(1280, 720, nil)
+		m.Resize(size[0], size[1])
+		for _, b := range m.mainMenuButtons() {
+			if mid := b.X + b.W/2; mid != size[0]/2 {
+				t.Errorf("%dx%d: %q centred at x=%d, want %d", size[0], size[1], b.Text, mid, size[0]/2)
+			}
+			if b.Y < 0 || b.Y+b.H > size[1] {
+				t.Errorf("%dx%d: %q off screen at y=%d", size[0], size[1], b.Text, b.Y)
+			}
+		}
+	}
+}
+
+func TestRadarOnline(t *testing.T) {
+	tests := []struct {
+		name   string
+		player *core.Player
+		want   bool
+	}{
+		{"radar working", &core.Player{ID: 0, Radar: true}, true},
+		{"radar offline", &core.Player{ID: 0}, false},
+		{"only another player has radar", &core.Player{ID: 1, Radar: true}, false},
+		{"no players", nil, false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			h := &HUD{}
+			if tc.player != nil {
+				h.Players = core.NewPlayerManager()
+				h.Players.AddPlayer(tc.player)
+			}
+			if got := h.RadarOnline(); got != tc.want {
+				t.Errorf("RadarOnline() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
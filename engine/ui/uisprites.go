@@ -200,7 +200,7 @@ func (us *UISprites) loadRA2UIAssets() {
 	// Build icons (real RA2 cameo icons extracted from game files)
 	buildingKeys := []string{
 		"construction_yard", "power_plant", "barracks", "refinery", "war_factory",
-		"radar", "tech_center", "wall", "pillbox", "prism_tower", "tesla_coil",
+		"radar", "tech_center", "wall", "gate", "pillbox", "prism_tower", "tesla_coil",
 		"flak_cannon", "iron_curtain", "chronosphere", "cloning_vat", "naval_yard",
 		"soviet_power_plant", "soviet_barracks", "soviet_war_factory", "soviet_refinery", "soviet_radar",
 	}